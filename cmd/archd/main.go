@@ -0,0 +1,200 @@
+// Command archd is the arch inventory daemon: it runs the collectors,
+// the reconciler and the HTTP API in one process.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/qrunner/arch/internal/api"
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/enrich"
+	"github.com/qrunner/arch/internal/events"
+	snexport "github.com/qrunner/arch/internal/export/servicenow"
+	"github.com/qrunner/arch/internal/graph"
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/notify"
+	"github.com/qrunner/arch/internal/reconcile"
+	"github.com/qrunner/arch/internal/store/postgres"
+
+	// Collector types register themselves in init().
+	_ "github.com/qrunner/arch/internal/collector/ansible"
+	_ "github.com/qrunner/arch/internal/collector/aws"
+	_ "github.com/qrunner/arch/internal/collector/azure"
+	_ "github.com/qrunner/arch/internal/collector/consul"
+	_ "github.com/qrunner/arch/internal/collector/dns"
+	_ "github.com/qrunner/arch/internal/collector/docker"
+	_ "github.com/qrunner/arch/internal/collector/elasticsearch"
+	_ "github.com/qrunner/arch/internal/collector/exec"
+	_ "github.com/qrunner/arch/internal/collector/extdns"
+	_ "github.com/qrunner/arch/internal/collector/f5"
+	_ "github.com/qrunner/arch/internal/collector/firewall"
+	_ "github.com/qrunner/arch/internal/collector/gcp"
+	_ "github.com/qrunner/arch/internal/collector/haproxy"
+	_ "github.com/qrunner/arch/internal/collector/helm"
+	_ "github.com/qrunner/arch/internal/collector/k8s"
+	_ "github.com/qrunner/arch/internal/collector/kafka"
+	_ "github.com/qrunner/arch/internal/collector/ldap"
+	_ "github.com/qrunner/arch/internal/collector/lldp"
+	_ "github.com/qrunner/arch/internal/collector/mdm"
+	_ "github.com/qrunner/arch/internal/collector/mikrotik"
+	_ "github.com/qrunner/arch/internal/collector/netbox"
+	_ "github.com/qrunner/arch/internal/collector/netscaler"
+	_ "github.com/qrunner/arch/internal/collector/nmap"
+	_ "github.com/qrunner/arch/internal/collector/openstack"
+	_ "github.com/qrunner/arch/internal/collector/otel"
+	_ "github.com/qrunner/arch/internal/collector/phpipam"
+	_ "github.com/qrunner/arch/internal/collector/prometheus"
+	_ "github.com/qrunner/arch/internal/collector/redfish"
+	_ "github.com/qrunner/arch/internal/collector/servicenow"
+	_ "github.com/qrunner/arch/internal/collector/snmp"
+	_ "github.com/qrunner/arch/internal/collector/ssh"
+	_ "github.com/qrunner/arch/internal/collector/tlsscan"
+	_ "github.com/qrunner/arch/internal/collector/unifi"
+	_ "github.com/qrunner/arch/internal/collector/vmware"
+	_ "github.com/qrunner/arch/internal/collector/vulnscan"
+	_ "github.com/qrunner/arch/internal/collector/zabbix"
+)
+
+func main() {
+	configPath := flag.String("config", "archd.yaml", "path to the configuration file")
+	debug := flag.Bool("debug", false, "enable debug logging")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
+	}
+	log := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	if err := run(*configPath, log); err != nil {
+		log.Error("archd exiting", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, log *slog.Logger) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if err := model.RegisterRelationshipTypes(cfg.Neo4j.CustomRelationshipTypes); err != nil {
+		return fmt.Errorf("neo4j.custom_relationship_types: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	st, err := postgres.New(ctx, cfg.Postgres.DSN, log, cfg.Postgres.SlowQueryThreshold.Std())
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	var gr *graph.Store
+	if cfg.Neo4j.URI != "" {
+		gr, err = graph.New(ctx, cfg.Neo4j.URI, cfg.Neo4j.Username, cfg.Neo4j.Password)
+		if err != nil {
+			return err
+		}
+		defer gr.Close(context.Background())
+	} else {
+		log.Info("neo4j not configured, graph features disabled")
+	}
+
+	var pub *events.Publisher
+	if cfg.NATS.URL != "" {
+		pub, err = events.New(cfg.NATS, st, log)
+		if err != nil {
+			return err
+		}
+		defer pub.Close()
+		go pub.Run(ctx)
+	} else {
+		log.Info("nats not configured, event publishing disabled")
+	}
+
+	hub := api.NewHub()
+	notifier := notify.New(cfg.Notify, st, log)
+
+	var sinks []reconcile.EventSink
+	if pub != nil {
+		// With NATS, the hub is fed from the shared subject so clients
+		// on any API replica see changes reconciled on any other; the
+		// hub must then not also be a direct sink or local clients
+		// would see every event twice.
+		if err := pub.Subscribe(hub.Publish); err != nil {
+			return fmt.Errorf("subscribe hub to nats: %w", err)
+		}
+	} else {
+		sinks = append(sinks, hub)
+	}
+	if notifier != nil {
+		sinks = append(sinks, notifier)
+	}
+	if exporter := snexport.New(cfg.Export.ServiceNow, st, log); exporter != nil {
+		sinks = append(sinks, exporter)
+	}
+	var enrichers []reconcile.Enricher
+	if rdns := enrich.NewReverseDNS(cfg.Enrich.ReverseDNS, log); rdns != nil {
+		enrichers = append(enrichers, rdns)
+	}
+	sites, err := enrich.NewSiteMapper(cfg.Enrich.Sites)
+	if err != nil {
+		return err
+	}
+	if sites != nil {
+		enrichers = append(enrichers, sites)
+	}
+	rec := reconcile.New(st, gr, pub, enrichers, reconcile.Config{
+		BatchSize:           cfg.Reconcile.BatchSize,
+		Workers:             cfg.Reconcile.Workers,
+		NameCollisionPolicy: cfg.Reconcile.NameCollisionPolicy,
+		MaxDropPercent:      cfg.Reconcile.MaxDropPercent,
+		MatchThreshold:      cfg.Reconcile.MatchThreshold,
+		Authority:           cfg.Reconcile.Authority,
+		Normalize: reconcile.NormalizeConfig{
+			LowercaseFQDN:    cfg.Reconcile.Normalize.LowercaseFQDN,
+			StripDomains:     cfg.Reconcile.Normalize.StripDomains,
+			AssetTypes:       cfg.Reconcile.Normalize.AssetTypes,
+			DropIPs:          cfg.Reconcile.Normalize.DropIPs,
+			RenameAttributes: cfg.Reconcile.Normalize.RenameAttributes,
+		},
+	}, log, sinks...)
+
+	var sched *collector.Scheduler
+	if len(cfg.Collectors) > 0 {
+		sched, err = collector.NewScheduler(cfg.Collectors, rec, cfg.Notify.CollectorFailureThreshold, log, sinks...)
+		if err != nil {
+			return err
+		}
+		go sched.Run(ctx)
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           api.New(cfg, st, gr, sched, rec, hub, pub, log),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("archd listening", "addr", cfg.Listen)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
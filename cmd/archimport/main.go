@@ -0,0 +1,112 @@
+// Command archimport uploads a CSV inventory to a running archd
+// through the import endpoint and prints the per-row error report.
+// It is a thin client: all parsing, mapping and reconciliation happen
+// server-side, so the CLI and the API cannot drift apart.
+//
+//	archimport -url http://archd:8080 -token $TOKEN \
+//	    -map Hostname=name -map "IP Address=ips" -map Notes=- hosts.csv
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+func main() {
+	apiURL := flag.String("url", "http://localhost:8080", "archd base URL")
+	token := flag.String("token", os.Getenv("ARCH_TOKEN"), "API token (defaults to $ARCH_TOKEN)")
+	var mappings []string
+	flag.Func("map", "column=field mapping, repeatable ('-' discards a column)", func(v string) error {
+		mappings = append(mappings, v)
+		return nil
+	})
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: archimport [flags] <file.csv> ('-' for stdin)")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	if err := run(*apiURL, *token, mappings, flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, "archimport:", err)
+		os.Exit(1)
+	}
+}
+
+func run(apiURL, token string, mappings []string, path string) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	q := url.Values{}
+	for _, m := range mappings {
+		q.Add("map", m)
+	}
+	endpoint := apiURL + "/api/v1/import/csv"
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, in)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/csv")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned %s: %s", resp.Status, body)
+	}
+	var result struct {
+		Imported int `json:"imported"`
+		Rejected int `json:"rejected"`
+		Errors   []struct {
+			Line  int    `json:"line"`
+			Error string `json:"error"`
+		} `json:"errors"`
+		Report *struct {
+			Created   int `json:"created"`
+			Updated   int `json:"updated"`
+			Unchanged int `json:"unchanged"`
+			Errors    int `json:"errors"`
+		} `json:"report"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	fmt.Printf("imported %d rows, rejected %d\n", result.Imported, result.Rejected)
+	if result.Report != nil {
+		fmt.Printf("created %d, updated %d, unchanged %d, errors %d\n",
+			result.Report.Created, result.Report.Updated, result.Report.Unchanged, result.Report.Errors)
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("line %d: %s\n", e.Line, e.Error)
+	}
+	if result.Rejected > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/qrunner/arch/internal/config"
+)
+
+// API scopes, ordered: each scope implies the ones below it.
+type scope int
+
+const (
+	scopeRead scope = iota
+	scopeWrite
+	scopeAdmin
+)
+
+func parseScope(s string) scope {
+	switch s {
+	case "admin":
+		return scopeAdmin
+	case "write":
+		return scopeWrite
+	default:
+		return scopeRead
+	}
+}
+
+// authenticator checks bearer tokens against the static token list. An
+// empty token list disables authentication entirely, which is intended
+// for development only.
+type authenticator struct {
+	tokens map[string]scope
+}
+
+func newAuthenticator(cfg config.AuthConfig) *authenticator {
+	a := &authenticator{tokens: make(map[string]scope, len(cfg.Tokens))}
+	for _, t := range cfg.Tokens {
+		a.tokens[t.Token] = parseScope(t.Scope)
+	}
+	return a
+}
+
+// require returns middleware enforcing at least the given scope.
+func (a *authenticator) require(min scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(a.tokens) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			token := bearerToken(r)
+			if token == "" {
+				writeError(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+			got, ok := a.tokens[token]
+			if !ok {
+				writeError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+			if got < min {
+				writeError(w, http.StatusForbidden, "insufficient scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(h, prefix) {
+		return ""
+	}
+	return strings.TrimSpace(h[len(prefix):])
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// assetCursor marks where an asset page ended: the sort order and the
+// last row's sort key and id. Clients see it only as an opaque base64
+// token, so the representation can change without breaking them.
+type assetCursor struct {
+	Sort string `json:"s,omitempty"`
+	Desc bool   `json:"d,omitempty"`
+	Key  string `json:"k,omitempty"` // RFC 3339 for time sorts
+	ID   int64  `json:"id"`
+}
+
+// encodeAssetCursor builds the next-page token from the last row of the
+// current page.
+func encodeAssetCursor(a *model.Asset, sort string, desc bool) string {
+	c := assetCursor{Sort: sort, Desc: desc, ID: a.ID}
+	switch sort {
+	case "name":
+		c.Key = a.Name
+	case "last_seen":
+		c.Key = a.LastSeen.UTC().Format(time.RFC3339Nano)
+	case "created_at":
+		c.Key = a.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	raw, _ := json.Marshal(c) // marshaling this struct cannot fail
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// applyCursor resumes the filter after the position a token encodes.
+// The token carries its own sort order, which wins over sort/order
+// parameters — mixing orders across pages would skip rows.
+func applyCursor(token string, f *store.AssetFilter) error {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("invalid cursor")
+	}
+	var c assetCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == 0 {
+		return fmt.Errorf("invalid cursor")
+	}
+	f.Sort, f.Desc, f.AfterID, f.Offset = c.Sort, c.Desc, c.ID, 0
+	switch c.Sort {
+	case "", "id":
+	case "name":
+		f.AfterValue = c.Key
+	case "last_seen", "created_at":
+		at, err := time.Parse(time.RFC3339Nano, c.Key)
+		if err != nil {
+			return fmt.Errorf("invalid cursor")
+		}
+		f.AfterValue = at
+	default:
+		return fmt.Errorf("invalid cursor")
+	}
+	return nil
+}
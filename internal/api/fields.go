@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// assetFieldPath is one entry of a fields= selection: a top-level asset
+// field, optionally descending into a JSON column (attributes.os, or
+// deeper paths like attributes.labels.app).
+type assetFieldPath struct {
+	col  string
+	path []string
+}
+
+// assetFieldNames are the selectable top-level fields, derived from the
+// asset's JSON tags so the list cannot drift from the model.
+var assetFieldNames = func() map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(model.Asset{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// assetJSONColumns are the fields that dotted paths may descend into.
+var assetJSONColumns = map[string]bool{"attributes": true, "tags": true, "custom": true}
+
+// parseAssetFields parses a fields= selection. An empty parameter means
+// no projection; the second return is a non-empty error message on an
+// unknown field.
+func parseAssetFields(raw string) ([]assetFieldPath, string) {
+	if raw == "" {
+		return nil, ""
+	}
+	var out []assetFieldPath
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		col, rest, _ := strings.Cut(name, ".")
+		switch {
+		case rest != "" && assetJSONColumns[col]:
+			out = append(out, assetFieldPath{col: col, path: strings.Split(rest, ".")})
+		case assetFieldNames[name]:
+			out = append(out, assetFieldPath{col: name})
+		default:
+			return nil, "unknown field " + name
+		}
+	}
+	return out, ""
+}
+
+// projectAsset returns only the selected fields of an asset, keyed and
+// shaped as in the full JSON representation. Selected fields the asset
+// does not carry are simply absent, like omitempty fields are in full
+// responses.
+func projectAsset(a *model.Asset, fields []assetFieldPath) map[string]any {
+	// Round-tripping through JSON keeps names and value shapes exactly
+	// as the full representation renders them.
+	raw, _ := json.Marshal(a)
+	var full map[string]any
+	_ = json.Unmarshal(raw, &full)
+
+	out := make(map[string]any, len(fields))
+	for _, f := range fields {
+		v, ok := full[f.col]
+		if !ok {
+			continue
+		}
+		for _, seg := range f.path {
+			m, isMap := v.(map[string]any)
+			if !isMap {
+				ok = false
+				break
+			}
+			if v, ok = m[seg]; !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		// Rebuild the nesting for the selected leaf.
+		cur := out
+		keys := append([]string{f.col}, f.path...)
+		for _, k := range keys[:len(keys)-1] {
+			next, isMap := cur[k].(map[string]any)
+			if !isMap {
+				next = map[string]any{}
+				cur[k] = next
+			}
+			cur = next
+		}
+		cur[keys[len(keys)-1]] = v
+	}
+	return out
+}
+
+// projectAssets applies projectAsset across a page.
+func projectAssets(assets []*model.Asset, fields []assetFieldPath) []map[string]any {
+	out := make([]map[string]any, len(assets))
+	for i, a := range assets {
+		out[i] = projectAsset(a, fields)
+	}
+	return out
+}
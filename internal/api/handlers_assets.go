@@ -0,0 +1,721 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/query"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// listResponse is the envelope for paged collections. NextCursor, when
+// present, resumes the listing after the last item of this page.
+type listResponse struct {
+	Items      any    `json:"items"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// assetFilterFromQuery parses the shared asset filter parameters used
+// by the list and export endpoints. The second return is a non-empty
+// error message on malformed input.
+func assetFilterFromQuery(r *http.Request) (store.AssetFilter, string) {
+	q := r.URL.Query()
+	f := store.AssetFilter{
+		Source:         q.Get("source"),
+		AssetType:      q.Get("asset_type"),
+		Status:         q.Get("status"),
+		Site:           q.Get("site"),
+		Owner:          q.Get("owner"),
+		Team:           q.Get("team"),
+		Search:         q.Get("search"),
+		MaxSeenCount:   queryInt(r, "max_seen_count", 0),
+		IncludeDeleted: q.Get("include_deleted") == "true",
+	}
+	// Repeatable tag=key=value parameters narrow by tags; all given
+	// pairs must match.
+	for _, raw := range q["tag"] {
+		k, v, ok := strings.Cut(raw, "=")
+		if !ok || k == "" {
+			return f, "invalid tag filter " + raw + ", want key=value"
+		}
+		if f.Tags == nil {
+			f.Tags = map[string]string{}
+		}
+		f.Tags[k] = v
+	}
+	// custom=key=value works the same way for custom field values.
+	for _, raw := range q["custom"] {
+		k, v, ok := strings.Cut(raw, "=")
+		if !ok || k == "" {
+			return f, "invalid custom filter " + raw + ", want key=value"
+		}
+		if f.Custom == nil {
+			f.Custom = map[string]string{}
+		}
+		f.Custom[k] = v
+	}
+	// query= carries an expression in the asset query language, e.g.
+	// asset_type = "vm" AND attributes.os ~ "Ubuntu" AND last_seen < now()-7d.
+	if raw := q.Get("query"); raw != "" {
+		expr, err := query.Parse(raw)
+		if err != nil {
+			return f, "invalid query: " + err.Error()
+		}
+		f.Query = expr
+	}
+	return f, ""
+}
+
+func (s *Server) handleListAssets(w http.ResponseWriter, r *http.Request) {
+	f, msg := assetFilterFromQuery(r)
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	q := r.URL.Query()
+	// fields=name,attributes.os trims each asset to the selected
+	// fields, which matters for attribute-heavy inventories.
+	fields, msg := parseAssetFields(q.Get("fields"))
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	switch sort := q.Get("sort"); sort {
+	case "", "id", "name", "last_seen", "created_at":
+		f.Sort = sort
+	default:
+		writeError(w, http.StatusBadRequest, "invalid sort "+sort+", want id, name, last_seen or created_at")
+		return
+	}
+	switch order := q.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		f.Desc = true
+	default:
+		writeError(w, http.StatusBadRequest, "invalid order "+order+", want asc or desc")
+		return
+	}
+	f.Limit = queryInt(r, "limit", 50)
+	if f.Limit <= 0 || f.Limit > 500 {
+		f.Limit = 500
+	}
+	if tok := q.Get("cursor"); tok != "" {
+		if err := applyCursor(tok, &f); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	} else {
+		f.Offset = queryInt(r, "offset", 0)
+	}
+	assets, total, err := s.store.ListAssets(r.Context(), f)
+	if err != nil {
+		s.log.Error("api: list assets", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing assets failed")
+		return
+	}
+	if assets == nil {
+		assets = []*model.Asset{}
+	}
+	resp := listResponse{Items: assets, Total: total}
+	if fields != nil {
+		resp.Items = projectAssets(assets, fields)
+	}
+	// A full page may have more behind it; the token resumes there.
+	if len(assets) == f.Limit {
+		resp.NextCursor = encodeAssetCursor(assets[len(assets)-1], f.Sort, f.Desc)
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+}
+
+func (s *Server) handleGetAsset(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	fields, msg := parseAssetFields(r.URL.Query().Get("fields"))
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	a, err := s.store.GetAssetByID(r.Context(), id)
+	if err == store.ErrNotFound {
+		writeError(w, http.StatusNotFound, "asset not found")
+		return
+	}
+	if err != nil {
+		s.log.Error("api: get asset", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "fetching asset failed")
+		return
+	}
+	if a.Aliases, err = s.store.ListAliasesByAssetID(r.Context(), id); err != nil {
+		s.log.Error("api: list aliases", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "fetching asset failed")
+		return
+	}
+	if a.Sources, err = s.store.ListAssetSources(r.Context(), id); err != nil {
+		s.log.Error("api: list asset sources", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "fetching asset failed")
+		return
+	}
+	if fields != nil {
+		writeJSON(w, r, http.StatusOK, projectAsset(a, fields))
+		return
+	}
+	writeJSON(w, r, http.StatusOK, a)
+}
+
+// createAssetRequest is the body for manually registering an asset.
+type createAssetRequest struct {
+	Name       string         `json:"name"`
+	FQDN       string         `json:"fqdn"`
+	IPs        []string       `json:"ips"`
+	AssetType  string         `json:"asset_type"`
+	ExternalID string         `json:"external_id"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+func (s *Server) handleCreateAsset(w http.ResponseWriter, r *http.Request) {
+	var req createAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if req.Name == "" || req.AssetType == "" {
+		writeError(w, http.StatusBadRequest, "name and asset_type are required")
+		return
+	}
+	a := &model.Asset{
+		Name:       req.Name,
+		FQDN:       req.FQDN,
+		IPs:        req.IPs,
+		AssetType:  req.AssetType,
+		Status:     model.StatusActive,
+		Source:     "manual",
+		ExternalID: req.ExternalID,
+		Attributes: req.Attributes,
+	}
+	if a.ExternalID == "" {
+		a.ExternalID = a.Name
+	}
+	if err := s.store.CreateAsset(r.Context(), a); err != nil {
+		var tooLarge *store.AttributesTooLargeError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, tooLarge.Error())
+			return
+		}
+		s.log.Error("api: create asset", "error", err)
+		writeError(w, http.StatusInternalServerError, "creating asset failed")
+		return
+	}
+	if err := s.graph.UpsertAsset(r.Context(), a); err != nil {
+		s.log.Warn("api: graph upsert failed", "asset_id", a.ID, "error", err)
+	}
+	e := &model.ChangeEvent{
+		AssetID: a.ID,
+		Action:  model.ActionAssetCreated,
+		Source:  "manual",
+		Actor:   "api",
+		After:   map[string]any{"name": a.Name, "asset_type": a.AssetType},
+	}
+	if err := s.store.CreateChangeEvent(r.Context(), e); err != nil {
+		s.log.Error("api: record create event", "asset_id", a.ID, "error", err)
+	}
+	writeJSON(w, r, http.StatusCreated, a)
+}
+
+// notesRequest is the body for replacing an asset's notes.
+type notesRequest struct {
+	Notes string `json:"notes"`
+}
+
+func (s *Server) handleUpdateNotes(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	var req notesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	previous, err := s.store.UpdateAssetNotes(r.Context(), id, req.Notes)
+	if err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: update notes", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "updating notes failed")
+		return
+	}
+	if previous != req.Notes {
+		e := &model.ChangeEvent{
+			AssetID: id,
+			Action:  model.ActionAssetAnnotated,
+			Source:  "manual",
+			Actor:   "api",
+			Before:  map[string]any{"notes": previous},
+			After:   map[string]any{"notes": req.Notes},
+		}
+		if err := s.store.CreateChangeEvent(r.Context(), e); err != nil {
+			s.log.Error("api: record notes event", "asset_id", id, "error", err)
+		}
+	}
+	writeJSON(w, r, http.StatusOK, map[string]string{"notes": req.Notes})
+}
+
+// bulkTagRequest applies tag changes to every asset matching the
+// filter. At least one filter field is required so a malformed request
+// can't retag the whole inventory.
+type bulkTagRequest struct {
+	Filter struct {
+		Source    string `json:"source"`
+		AssetType string `json:"asset_type"`
+		Status    string `json:"status"`
+		Search    string `json:"search"`
+	} `json:"filter"`
+	Add    map[string]string `json:"add"`
+	Remove []string          `json:"remove"`
+}
+
+func (s *Server) handleBulkTag(w http.ResponseWriter, r *http.Request) {
+	var req bulkTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		writeError(w, http.StatusBadRequest, "nothing to do: add and remove are both empty")
+		return
+	}
+	f := store.AssetFilter{
+		Source:    req.Filter.Source,
+		AssetType: req.Filter.AssetType,
+		Status:    req.Filter.Status,
+		Search:    req.Filter.Search,
+	}
+	if f.Source == "" && f.AssetType == "" && f.Status == "" && f.Search == "" {
+		writeError(w, http.StatusBadRequest, "a filter is required for bulk tagging")
+		return
+	}
+	affected, err := s.store.BulkTag(r.Context(), f, req.Add, req.Remove)
+	if err != nil {
+		s.log.Error("api: bulk tag", "error", err)
+		writeError(w, http.StatusInternalServerError, "bulk tagging failed")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, map[string]int64{"affected": affected})
+}
+
+// ownerRequest assigns the accountable person and team. Empty values
+// clear the assignment.
+type ownerRequest struct {
+	Owner string `json:"owner"`
+	Team  string `json:"team"`
+}
+
+func (s *Server) handleAssignOwner(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	var req ownerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	prevOwner, prevTeam, err := s.store.UpdateAssetOwner(r.Context(), id, req.Owner, req.Team)
+	if err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: assign owner", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "assigning owner failed")
+		return
+	}
+	if prevOwner != req.Owner || prevTeam != req.Team {
+		e := &model.ChangeEvent{
+			AssetID: id,
+			Action:  model.ActionAssetUpdated,
+			Source:  "manual",
+			Actor:   "api",
+			Before:  map[string]any{"owner": prevOwner, "team": prevTeam},
+			After:   map[string]any{"owner": req.Owner, "team": req.Team},
+		}
+		if err := s.store.CreateChangeEvent(r.Context(), e); err != nil {
+			s.log.Error("api: record owner event", "asset_id", id, "error", err)
+		}
+	}
+	writeJSON(w, r, http.StatusOK, map[string]string{"owner": req.Owner, "team": req.Team})
+}
+
+// bulkOwnerRequest assigns owner and team to every asset matching the
+// filter. Like bulk tagging, at least one filter field is required so
+// a malformed request can't reassign the whole inventory.
+type bulkOwnerRequest struct {
+	Filter struct {
+		Source    string `json:"source"`
+		AssetType string `json:"asset_type"`
+		Status    string `json:"status"`
+		Site      string `json:"site"`
+		Search    string `json:"search"`
+	} `json:"filter"`
+	Owner string `json:"owner"`
+	Team  string `json:"team"`
+}
+
+func (s *Server) handleBulkAssignOwner(w http.ResponseWriter, r *http.Request) {
+	var req bulkOwnerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if req.Owner == "" && req.Team == "" {
+		writeError(w, http.StatusBadRequest, "nothing to do: owner and team are both empty")
+		return
+	}
+	f := store.AssetFilter{
+		Source:    req.Filter.Source,
+		AssetType: req.Filter.AssetType,
+		Status:    req.Filter.Status,
+		Site:      req.Filter.Site,
+		Search:    req.Filter.Search,
+	}
+	if f.Source == "" && f.AssetType == "" && f.Status == "" && f.Site == "" && f.Search == "" {
+		writeError(w, http.StatusBadRequest, "a filter is required for bulk assignment")
+		return
+	}
+	affected, err := s.store.BulkAssignOwner(r.Context(), f, req.Owner, req.Team)
+	if err != nil {
+		s.log.Error("api: bulk assign owner", "error", err)
+		writeError(w, http.StatusInternalServerError, "bulk assignment failed")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, map[string]int64{"affected": affected})
+}
+
+// tagRequest edits one asset's tags.
+type tagRequest struct {
+	Add    map[string]string `json:"add"`
+	Remove []string          `json:"remove"`
+}
+
+// handleUpdateTags applies tag additions and removals to one asset and
+// mirrors the new set into the graph so Cypher filters stay current.
+func (s *Server) handleUpdateTags(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	var req tagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		writeError(w, http.StatusBadRequest, "nothing to do: add and remove are both empty")
+		return
+	}
+	before, after, err := s.store.UpdateAssetTags(r.Context(), id, req.Add, req.Remove)
+	if err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: update tags", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "updating tags failed")
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		if a, err := s.store.GetAssetByID(r.Context(), id); err == nil {
+			if err := s.graph.UpsertAsset(r.Context(), a); err != nil {
+				s.log.Warn("api: graph upsert failed", "asset_id", id, "error", err)
+			}
+		}
+		e := &model.ChangeEvent{
+			AssetID: id,
+			Action:  model.ActionAssetUpdated,
+			Source:  "manual",
+			Actor:   "api",
+			Before:  map[string]any{"tags": before},
+			After:   map[string]any{"tags": after},
+		}
+		if err := s.store.CreateChangeEvent(r.Context(), e); err != nil {
+			s.log.Error("api: record tags event", "asset_id", id, "error", err)
+		}
+	}
+	writeJSON(w, r, http.StatusOK, map[string]any{"tags": after})
+}
+
+// touchRequest confirms liveness for a batch of a source's assets by
+// external ID. An omitted seen_at means "now".
+type touchRequest struct {
+	Source      string    `json:"source"`
+	ExternalIDs []string  `json:"external_ids"`
+	SeenAt      time.Time `json:"seen_at"`
+}
+
+// handleTouchAssets is the fast path for heartbeat-style sources: it
+// bumps last_seen for the named assets in one statement without running
+// the reconcile/diff pipeline.
+func (s *Server) handleTouchAssets(w http.ResponseWriter, r *http.Request) {
+	var req touchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if req.Source == "" || len(req.ExternalIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "source and external_ids are required")
+		return
+	}
+	seenAt := req.SeenAt
+	if seenAt.IsZero() {
+		seenAt = time.Now().UTC()
+	}
+	touched, err := s.store.TouchAssets(r.Context(), req.Source, req.ExternalIDs, seenAt)
+	if err != nil {
+		s.log.Error("api: touch assets", "source", req.Source, "error", err)
+		writeError(w, http.StatusInternalServerError, "touching assets failed")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, map[string]int64{"touched": touched})
+}
+
+// approveAsset promotes one discovered asset to active and records the
+// status change. It returns store.ErrNotFound for unknown IDs and a nil
+// asset when the asset was not in the discovered state.
+func (s *Server) approveAsset(ctx context.Context, id int64) (*model.Asset, error) {
+	a, err := s.store.GetAssetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != model.StatusDiscovered {
+		return nil, nil
+	}
+	a.Status = model.StatusActive
+	if err := s.store.UpdateAsset(ctx, a); err != nil {
+		return nil, err
+	}
+	e := &model.ChangeEvent{
+		AssetID: a.ID,
+		Action:  model.ActionAssetUpdated,
+		Source:  "manual",
+		Actor:   "api",
+		Before:  map[string]any{"status": model.StatusDiscovered},
+		After:   map[string]any{"status": model.StatusActive},
+	}
+	if err := s.store.CreateChangeEvent(ctx, e); err != nil {
+		s.log.Error("api: record approve event", "asset_id", a.ID, "error", err)
+	}
+	return a, nil
+}
+
+// handleApproveAsset vets one scanner-discovered asset into the real
+// inventory ("discovered" -> "active").
+func (s *Server) handleApproveAsset(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	a, err := s.approveAsset(r.Context(), id)
+	if err == store.ErrNotFound {
+		writeError(w, http.StatusNotFound, "asset not found")
+		return
+	}
+	if err != nil {
+		s.log.Error("api: approve asset", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "approving asset failed")
+		return
+	}
+	if a == nil {
+		writeError(w, http.StatusConflict, "asset is not in the discovered state")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, a)
+}
+
+// bulkApproveRequest lists the assets to promote in one call.
+type bulkApproveRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+func (s *Server) handleBulkApprove(w http.ResponseWriter, r *http.Request) {
+	var req bulkApproveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if len(req.IDs) == 0 {
+		writeError(w, http.StatusBadRequest, "ids is required")
+		return
+	}
+	var approved, skipped int64
+	for _, id := range req.IDs {
+		a, err := s.approveAsset(r.Context(), id)
+		if err == store.ErrNotFound || (err == nil && a == nil) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			s.log.Error("api: bulk approve", "id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "approving assets failed")
+			return
+		}
+		approved++
+	}
+	writeJSON(w, r, http.StatusOK, map[string]int64{"approved": approved, "skipped": skipped})
+}
+
+// handleDeleteAsset soft-deletes: the asset keeps its row and history
+// but disappears from default listings, counts and the graph until it
+// is restored (or a source reports it again). Hard removal is the
+// separate purge endpoint.
+func (s *Server) handleDeleteAsset(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	a, err := s.store.GetAssetByID(r.Context(), id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: delete asset", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "deleting asset failed")
+		return
+	}
+	if err := s.store.SoftDeleteAsset(r.Context(), id); err != nil {
+		s.log.Error("api: delete asset", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "deleting asset failed")
+		return
+	}
+	if err := s.graph.DeleteAsset(r.Context(), id); err != nil {
+		s.log.Warn("api: graph delete failed", "asset_id", id, "error", err)
+	}
+	e := &model.ChangeEvent{
+		AssetID: id,
+		Action:  model.ActionAssetRemoved,
+		Source:  "manual",
+		Actor:   "api",
+		Before:  map[string]any{"name": a.Name, "status": a.Status},
+		After:   map[string]any{"deleted": true},
+	}
+	if err := s.store.CreateChangeEvent(r.Context(), e); err != nil {
+		s.log.Error("api: record delete event", "asset_id", id, "error", err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRestoreAsset clears an asset's soft-delete marker and puts it
+// back into listings and the graph.
+func (s *Server) handleRestoreAsset(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	a, err := s.store.RestoreAsset(r.Context(), id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: restore asset", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "restoring asset failed")
+		return
+	}
+	if err := s.graph.UpsertAsset(r.Context(), a); err != nil {
+		s.log.Warn("api: graph upsert failed", "asset_id", id, "error", err)
+	}
+	e := &model.ChangeEvent{
+		AssetID: id,
+		Action:  model.ActionAssetUpdated,
+		Source:  "manual",
+		Actor:   "api",
+		Before:  map[string]any{"deleted": true},
+		After:   map[string]any{"deleted": false, "name": a.Name, "status": a.Status},
+	}
+	if err := s.store.CreateChangeEvent(r.Context(), e); err != nil {
+		s.log.Error("api: record restore event", "asset_id", id, "error", err)
+	}
+	writeJSON(w, r, http.StatusOK, a)
+}
+
+// handlePurgeAsset drops an asset's row for good — and, via cascade,
+// its change history — which is why this is a separate admin endpoint
+// rather than the default delete.
+func (s *Server) handlePurgeAsset(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	if err := s.store.DeleteAsset(r.Context(), id); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: purge asset", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "purging asset failed")
+		return
+	}
+	if err := s.graph.DeleteAsset(r.Context(), id); err != nil {
+		s.log.Warn("api: graph delete failed", "asset_id", id, "error", err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// eventListResponse is the history envelope. Total is omitted when the
+// caller opted out of the count; NextBeforeID feeds the next keyset
+// page when more events may remain.
+type eventListResponse struct {
+	Items        any    `json:"items"`
+	Total        *int64 `json:"total,omitempty"`
+	NextBeforeID int64  `json:"next_before_id,omitempty"`
+}
+
+func (s *Server) handleListAssetEvents(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	q := store.EventQuery{
+		Limit:     queryInt(r, "limit", 50),
+		Offset:    queryInt(r, "offset", 0),
+		BeforeID:  int64(queryInt(r, "before_id", 0)),
+		WithCount: r.URL.Query().Get("with_count") != "false",
+	}
+	events, total, err := s.store.ListChangeEventsByAssetID(r.Context(), id, q)
+	if err != nil {
+		s.log.Error("api: list events", "asset_id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "listing events failed")
+		return
+	}
+	if events == nil {
+		events = []*model.ChangeEvent{}
+	}
+	resp := eventListResponse{Items: events}
+	if total >= 0 {
+		resp.Total = &total
+	}
+	if limit := q.Limit; limit > 0 && len(events) == limit {
+		resp.NextBeforeID = events[len(events)-1].ID
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/reconcile"
+	"github.com/qrunner/arch/internal/store"
+)
+
+func (s *Server) handleListCollectors(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeError(w, http.StatusServiceUnavailable, "no collectors configured")
+		return
+	}
+	statuses := s.scheduler.Statuses()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	writeJSON(w, r, http.StatusOK, listResponse{Items: statuses, Total: int64(len(statuses))})
+}
+
+// handleListCollectorRuns serves a collector's persisted run history,
+// newest first — what each execution actually created, updated and
+// errored on, beyond the scheduler's last-run snapshot.
+func (s *Server) handleListCollectorRuns(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	runs, err := s.store.ListReconcileRuns(r.Context(), name, queryInt(r, "limit", 50))
+	if err != nil {
+		s.log.Error("api: list collector runs", "collector", name, "error", err)
+		writeError(w, http.StatusInternalServerError, "listing runs failed")
+		return
+	}
+	if runs == nil {
+		runs = []*store.ReconcileRun{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: runs, Total: int64(len(runs))})
+}
+
+// handleListConflicts serves the reconcile conflict log for review.
+func (s *Server) handleListConflicts(w http.ResponseWriter, r *http.Request) {
+	conflicts, total, err := s.store.ListReconcileConflicts(r.Context(),
+		queryInt(r, "limit", 50), queryInt(r, "offset", 0))
+	if err != nil {
+		s.log.Error("api: list conflicts", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing conflicts failed")
+		return
+	}
+	if conflicts == nil {
+		conflicts = []*model.ReconcileConflict{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: conflicts, Total: total})
+}
+
+// handleListQuarantine serves the quarantine queue: collector results
+// the reconciler refused to apply, held for review.
+func (s *Server) handleListQuarantine(w http.ResponseWriter, r *http.Request) {
+	runs, total, err := s.store.ListQuarantinedRuns(r.Context(),
+		queryInt(r, "limit", 50), queryInt(r, "offset", 0))
+	if err != nil {
+		s.log.Error("api: list quarantine", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing quarantine failed")
+		return
+	}
+	if runs == nil {
+		runs = []*store.QuarantinedRun{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: runs, Total: total})
+}
+
+// handleApproveQuarantined applies a quarantined result after all —
+// the operator has verified the source really did shrink (or the
+// malformation is acceptable) — and removes it from the queue.
+func (s *Server) handleApproveQuarantined(w http.ResponseWriter, r *http.Request) {
+	if s.reconciler == nil {
+		writeError(w, http.StatusServiceUnavailable, "reconciler is not configured")
+		return
+	}
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid quarantine id")
+		return
+	}
+	q, err := s.store.GetQuarantinedRun(r.Context(), id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "quarantined run not found")
+			return
+		}
+		s.log.Error("api: get quarantined run", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "loading quarantined run failed")
+		return
+	}
+	report, err := s.reconciler.Reconcile(r.Context(), q.Source, *q.Result, true)
+	if err != nil {
+		s.log.Error("api: apply quarantined run", "id", id, "source", q.Source, "error", err)
+		writeError(w, http.StatusInternalServerError, "applying quarantined run failed: "+err.Error())
+		return
+	}
+	if err := s.store.DeleteQuarantinedRun(r.Context(), id); err != nil {
+		s.log.Warn("api: delete quarantined run after approval", "id", id, "error", err)
+	}
+	writeJSON(w, r, http.StatusOK, report)
+}
+
+// handleDiscardQuarantined drops a quarantined result without applying
+// it.
+func (s *Server) handleDiscardQuarantined(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid quarantine id")
+		return
+	}
+	if err := s.store.DeleteQuarantinedRun(r.Context(), id); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "quarantined run not found")
+			return
+		}
+		s.log.Error("api: discard quarantined run", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "discarding quarantined run failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRunCollector(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeError(w, http.StatusServiceUnavailable, "no collectors configured")
+		return
+	}
+	name := chi.URLParam(r, "name")
+	report, err := s.scheduler.Trigger(r.Context(), name, r.URL.Query().Get("force") == "true")
+	if err != nil {
+		var quarantined *reconcile.QuarantinedError
+		if errors.As(err, &quarantined) {
+			writeError(w, http.StatusConflict, quarantined.Error()+" (approve it or retry with force=true)")
+			return
+		}
+		s.log.Error("api: trigger collector", "collector", name, "error", err)
+		writeError(w, http.StatusBadGateway, "collector run failed: "+err.Error())
+		return
+	}
+	writeJSON(w, r, http.StatusOK, report)
+}
+
+// handleRebuild re-runs derivation logic over the stored inventory so
+// rule changes apply without waiting for the next collection cycle. The
+// pass runs synchronously and returns its report.
+func (s *Server) handleRebuild(w http.ResponseWriter, r *http.Request) {
+	if s.reconciler == nil {
+		writeError(w, http.StatusServiceUnavailable, "reconciler is not configured")
+		return
+	}
+	report, err := s.reconciler.Rebuild(r.Context())
+	if err != nil {
+		s.log.Error("api: rebuild", "error", err)
+		writeError(w, http.StatusInternalServerError, "rebuild failed: "+err.Error())
+		return
+	}
+	writeJSON(w, r, http.StatusOK, report)
+}
+
+// handlePreviewCollector runs a collector's fetch+parse and returns the
+// raw CollectResult it would hand to the reconciler, without writing
+// anything. File-based collectors accept the report as the request
+// body.
+func (s *Server) handlePreviewCollector(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeError(w, http.StatusServiceUnavailable, "no collectors configured")
+		return
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, 64<<20))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading payload failed")
+		return
+	}
+	name := chi.URLParam(r, "name")
+	res, err := s.scheduler.Preview(r.Context(), name, payload)
+	if err != nil {
+		s.log.Error("api: preview collector", "collector", name, "error", err)
+		writeError(w, http.StatusBadGateway, "preview failed: "+err.Error())
+		return
+	}
+	writeJSON(w, r, http.StatusOK, res)
+}
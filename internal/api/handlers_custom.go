@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+func (s *Server) handleListCustomFields(w http.ResponseWriter, r *http.Request) {
+	fields, err := s.store.ListCustomFields(r.Context())
+	if err != nil {
+		s.log.Error("api: list custom fields", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing custom fields failed")
+		return
+	}
+	if fields == nil {
+		fields = []*model.CustomField{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: fields, Total: int64(len(fields))})
+}
+
+func (s *Server) handleCreateCustomField(w http.ResponseWriter, r *http.Request) {
+	var f model.CustomField
+	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if f.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if !model.ValidCustomFieldType(f.Type) {
+		writeError(w, http.StatusBadRequest, "invalid type "+string(f.Type)+", want string, number, date or enum")
+		return
+	}
+	if f.Type == model.CustomEnum && len(f.EnumValues) == 0 {
+		writeError(w, http.StatusBadRequest, "an enum field needs enum_values")
+		return
+	}
+	if f.Type != model.CustomEnum {
+		f.EnumValues = nil
+	}
+	// Check the name ourselves for a friendly error; the unique
+	// constraint still backstops a race.
+	existing, err := s.store.ListCustomFields(r.Context())
+	if err != nil {
+		s.log.Error("api: list custom fields", "error", err)
+		writeError(w, http.StatusInternalServerError, "creating custom field failed")
+		return
+	}
+	for _, e := range existing {
+		if e.Name == f.Name {
+			writeError(w, http.StatusConflict, "custom field "+f.Name+" already exists")
+			return
+		}
+	}
+	if err := s.store.CreateCustomField(r.Context(), &f); err != nil {
+		s.log.Error("api: create custom field", "name", f.Name, "error", err)
+		writeError(w, http.StatusInternalServerError, "creating custom field failed")
+		return
+	}
+	writeJSON(w, r, http.StatusCreated, &f)
+}
+
+func (s *Server) handleDeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid custom field id")
+		return
+	}
+	if err := s.store.DeleteCustomField(r.Context(), id); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "custom field not found")
+			return
+		}
+		s.log.Error("api: delete custom field", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "deleting custom field failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// customRequest edits one asset's custom field values.
+type customRequest struct {
+	Set    map[string]any `json:"set"`
+	Remove []string       `json:"remove"`
+}
+
+// handleUpdateCustom applies custom field values to one asset, after
+// validating every set value against its field definition.
+func (s *Server) handleUpdateCustom(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	var req customRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if len(req.Set) == 0 && len(req.Remove) == 0 {
+		writeError(w, http.StatusBadRequest, "nothing to do: set and remove are both empty")
+		return
+	}
+	if len(req.Set) > 0 {
+		fields, err := s.store.ListCustomFields(r.Context())
+		if err != nil {
+			s.log.Error("api: list custom fields", "error", err)
+			writeError(w, http.StatusInternalServerError, "updating custom fields failed")
+			return
+		}
+		byName := make(map[string]*model.CustomField, len(fields))
+		for _, f := range fields {
+			byName[f.Name] = f
+		}
+		for name, value := range req.Set {
+			f, ok := byName[name]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "unknown custom field "+name)
+				return
+			}
+			if err := f.Validate(value); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+	}
+	before, after, err := s.store.UpdateAssetCustom(r.Context(), id, req.Set, req.Remove)
+	if err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: update custom fields", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "updating custom fields failed")
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		e := &model.ChangeEvent{
+			AssetID: id,
+			Action:  model.ActionAssetUpdated,
+			Source:  "manual",
+			Actor:   "api",
+			Before:  map[string]any{"custom": before},
+			After:   map[string]any{"custom": after},
+		}
+		if err := s.store.CreateChangeEvent(r.Context(), e); err != nil {
+			s.log.Error("api: record custom fields event", "asset_id", id, "error", err)
+		}
+	}
+	writeJSON(w, r, http.StatusOK, map[string]any{"custom": after})
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/qrunner/arch/internal/events"
+	"github.com/qrunner/arch/internal/store"
+)
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	// recent widens or narrows the recent-changes window, e.g.
+	// recent=48h; invalid values fall back to the 24h default.
+	var recent time.Duration
+	if raw := r.URL.Query().Get("recent"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid recent window: "+raw)
+			return
+		}
+		recent = d
+	}
+	stats, err := s.store.DashboardStats(r.Context(), recent)
+	if err != nil {
+		s.log.Error("api: dashboard stats", "error", err)
+		writeError(w, http.StatusInternalServerError, "computing stats failed")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, stats)
+}
+
+// handleAssetFacets returns the asset_counts summary rows: per
+// source/type/status counts with last_seen extremes, for building
+// filter facets without scanning the assets table.
+func (s *Server) handleAssetFacets(w http.ResponseWriter, r *http.Request) {
+	counts, err := s.store.ListAssetCounts(r.Context())
+	if err != nil {
+		s.log.Error("api: asset facets", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing facets failed")
+		return
+	}
+	if counts == nil {
+		counts = []store.AssetCount{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: counts, Total: int64(len(counts))})
+}
+
+// statusResponse reports the health of archd's dependencies.
+type statusResponse struct {
+	Graph      bool           `json:"graph_enabled"`
+	Collectors int            `json:"collectors"`
+	NATS       *events.Status `json:"nats,omitempty"`
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	resp := statusResponse{Graph: s.graph != nil, NATS: s.publisher.ConnStatus()}
+	if s.scheduler != nil {
+		resp.Collectors = len(s.scheduler.Statuses())
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// exportColumn is one selectable column in a spreadsheet export.
+type exportColumn struct {
+	name  string
+	value func(a *model.Asset) string
+}
+
+// exportColumns is the default column set, in output order. Custom
+// field columns ("custom.<name>") are appended per request from the
+// live definitions.
+var exportColumns = []exportColumn{
+	{"id", func(a *model.Asset) string { return strconv.FormatInt(a.ID, 10) }},
+	{"name", func(a *model.Asset) string { return a.Name }},
+	{"display_name", func(a *model.Asset) string { return a.DisplayName }},
+	{"fqdn", func(a *model.Asset) string { return a.FQDN }},
+	{"ips", func(a *model.Asset) string { return strings.Join(a.IPs, " ") }},
+	{"macs", func(a *model.Asset) string { return strings.Join(a.MACs, " ") }},
+	{"serial_number", func(a *model.Asset) string { return a.SerialNumber }},
+	{"hardware_uuid", func(a *model.Asset) string { return a.HardwareUUID }},
+	{"asset_type", func(a *model.Asset) string { return a.AssetType }},
+	{"status", func(a *model.Asset) string { return string(a.Status) }},
+	{"source", func(a *model.Asset) string { return a.Source }},
+	{"external_id", func(a *model.Asset) string { return a.ExternalID }},
+	{"site", func(a *model.Asset) string { return a.Site }},
+	{"owner", func(a *model.Asset) string { return a.Owner }},
+	{"team", func(a *model.Asset) string { return a.Team }},
+	{"tags", func(a *model.Asset) string { return formatTags(a.Tags) }},
+	{"notes", func(a *model.Asset) string { return a.Notes }},
+	{"seen_count", func(a *model.Asset) string { return strconv.FormatInt(a.SeenCount, 10) }},
+	{"absent_count", func(a *model.Asset) string { return strconv.FormatInt(a.AbsentCount, 10) }},
+	{"first_seen", func(a *model.Asset) string { return a.FirstSeen.UTC().Format(time.RFC3339) }},
+	{"last_seen", func(a *model.Asset) string { return a.LastSeen.UTC().Format(time.RFC3339) }},
+}
+
+func formatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
+
+func customColumn(name string) exportColumn {
+	return exportColumn{"custom." + name, func(a *model.Asset) string {
+		v, ok := a.Custom[name]
+		if !ok {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}}
+}
+
+// rowWriter is the shared surface of the CSV and XLSX writers.
+type rowWriter interface {
+	WriteRow(cells []string) error
+}
+
+type csvRowWriter struct{ w *csv.Writer }
+
+func (c csvRowWriter) WriteRow(cells []string) error { return c.w.Write(cells) }
+
+// handleExportAssets streams the filtered inventory as a spreadsheet:
+// format=csv (default) or xlsx, with the same filter parameters as the
+// list endpoint and an optional columns=name,fqdn,... selection.
+// Custom field columns are included by default and selectable as
+// custom.<name>.
+func (s *Server) handleExportAssets(w http.ResponseWriter, r *http.Request) {
+	f, msg := assetFilterFromQuery(r)
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		writeError(w, http.StatusBadRequest, "invalid format "+format+", want csv or xlsx")
+		return
+	}
+
+	columns := exportColumns
+	defs, err := s.store.ListCustomFields(r.Context())
+	if err != nil {
+		s.log.Error("api: list custom fields", "error", err)
+		writeError(w, http.StatusInternalServerError, "export failed")
+		return
+	}
+	for _, d := range defs {
+		columns = append(columns, customColumn(d.Name))
+	}
+	if sel := r.URL.Query().Get("columns"); sel != "" {
+		byName := make(map[string]exportColumn, len(columns))
+		for _, c := range columns {
+			byName[c.name] = c
+		}
+		columns = columns[:0]
+		for _, name := range strings.Split(sel, ",") {
+			c, ok := byName[strings.TrimSpace(name)]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "unknown column "+name)
+				return
+			}
+			columns = append(columns, c)
+		}
+	}
+
+	// Everything below streams; errors can only be logged since the
+	// header has been sent.
+	var out rowWriter
+	var finish func() error
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="assets.csv"`)
+		cw := csv.NewWriter(w)
+		out = csvRowWriter{cw}
+		finish = func() error { cw.Flush(); return cw.Error() }
+	case "xlsx":
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", `attachment; filename="assets.xlsx"`)
+		xw, err := newXLSXWriter(w)
+		if err != nil {
+			s.log.Error("api: export assets", "error", err)
+			return
+		}
+		out = xw
+		finish = xw.Close
+	}
+
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+	if err := out.WriteRow(header); err != nil {
+		s.log.Error("api: export assets", "error", err)
+		return
+	}
+	// Page through the store rather than loading the whole inventory
+	// at once; keyset pages don't skip or repeat rows when assets
+	// churn mid-export.
+	f.Limit = 100
+	for {
+		assets, _, err := s.store.ListAssets(r.Context(), f)
+		if err != nil {
+			s.log.Error("api: export assets", "error", err)
+			return
+		}
+		for _, a := range assets {
+			row := make([]string, len(columns))
+			for i, c := range columns {
+				row[i] = c.value(a)
+			}
+			if err := out.WriteRow(row); err != nil {
+				s.log.Error("api: export assets", "error", err)
+				return
+			}
+		}
+		if len(assets) < f.Limit {
+			break
+		}
+		f.AfterID = assets[len(assets)-1].ID
+	}
+	if err := finish(); err != nil {
+		s.log.Error("api: export assets", "error", err)
+	}
+}
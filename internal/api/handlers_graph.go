@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/qrunner/arch/internal/graph"
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+func (s *Server) handleDependencies(w http.ResponseWriter, r *http.Request) {
+	s.handleTraversal(w, r, s.graph.Dependencies)
+}
+
+func (s *Server) handleImpact(w http.ResponseWriter, r *http.Request) {
+	s.handleTraversal(w, r, s.graph.Impact)
+}
+
+// handleRelationshipTypes lists the relationship types present in the
+// graph.
+func (s *Server) handleRelationshipTypes(w http.ResponseWriter, r *http.Request) {
+	if s.graph == nil {
+		writeError(w, http.StatusServiceUnavailable, "graph store is not configured")
+		return
+	}
+	types, err := s.graph.ListRelationshipTypes(r.Context())
+	if err != nil {
+		s.log.Error("api: relationship types", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing relationship types failed")
+		return
+	}
+	if types == nil {
+		types = []string{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: types, Total: int64(len(types))})
+}
+
+// handleSubgraph serves the raw Neo4j neighborhood of a root asset with
+// all node and relationship properties intact, for the graph editor.
+func (s *Server) handleSubgraph(w http.ResponseWriter, r *http.Request) {
+	if s.graph == nil {
+		writeError(w, http.StatusServiceUnavailable, "graph store is not configured")
+		return
+	}
+	rootID := int64(queryInt(r, "root", 0))
+	if rootID <= 0 {
+		writeError(w, http.StatusBadRequest, "root query parameter is required")
+		return
+	}
+	sub, err := s.graph.Subgraph(r.Context(), rootID, queryInt(r, "depth", 2))
+	if err != nil {
+		s.log.Error("api: subgraph", "root", rootID, "error", err)
+		writeError(w, http.StatusInternalServerError, "subgraph query failed")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, sub)
+}
+
+// handleIsolatedAssets lists assets with no edges in the graph,
+// cross-referencing Postgres for the full records. These are candidates
+// for topology-completeness work.
+func (s *Server) handleIsolatedAssets(w http.ResponseWriter, r *http.Request) {
+	if s.graph == nil {
+		writeError(w, http.StatusServiceUnavailable, "graph store is not configured")
+		return
+	}
+	ids, err := s.graph.IsolatedAssetIDs(r.Context(), queryInt(r, "limit", 100))
+	if err != nil {
+		s.log.Error("api: isolated assets", "error", err)
+		writeError(w, http.StatusInternalServerError, "isolated asset query failed")
+		return
+	}
+	assets := make([]*model.Asset, 0, len(ids))
+	for _, id := range ids {
+		a, err := s.store.GetAssetByID(r.Context(), id)
+		if err == store.ErrNotFound {
+			// The graph can briefly lag behind a Postgres delete.
+			continue
+		}
+		if err != nil {
+			s.log.Error("api: isolated asset lookup", "id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "isolated asset query failed")
+			return
+		}
+		assets = append(assets, a)
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: assets, Total: int64(len(assets))})
+}
+
+func (s *Server) handleTraversal(w http.ResponseWriter, r *http.Request,
+	traverse func(ctx context.Context, id int64, depth int) ([]graph.Neighbor, error)) {
+	if s.graph == nil {
+		writeError(w, http.StatusServiceUnavailable, "graph store is not configured")
+		return
+	}
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	if _, err := s.store.GetAssetByID(r.Context(), id); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: traversal asset lookup", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "traversal failed")
+		return
+	}
+	neighbors, err := traverse(r.Context(), id, queryInt(r, "depth", 3))
+	if err != nil {
+		s.log.Error("api: graph traversal", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "traversal failed")
+		return
+	}
+	if neighbors == nil {
+		neighbors = []graph.Neighbor{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: neighbors, Total: int64(len(neighbors))})
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/reconcile"
+)
+
+// importRowError reports one rejected CSV row by its line number.
+type importRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// importResponse is the per-row report of a CSV import.
+type importResponse struct {
+	Imported int               `json:"imported"`
+	Rejected int               `json:"rejected"`
+	Errors   []importRowError  `json:"errors"`
+	Report   *reconcile.Report `json:"report,omitempty"`
+}
+
+// importFields are the asset fields a CSV column can map onto. Any
+// other target is rejected up front rather than silently dropped;
+// "attr.<name>" maps into Attributes and "-" discards the column.
+var importFields = map[string]bool{
+	"name": true, "fqdn": true, "ips": true, "macs": true,
+	"serial_number": true, "hardware_uuid": true, "asset_type": true,
+	"site": true, "external_id": true,
+}
+
+// handleImportCSV accepts a CSV body and feeds the rows through the
+// reconciler as source "manual", so matching, enrichment and change
+// events apply exactly as for a collector run. The header row names
+// the columns; repeatable map=column=field parameters rename them
+// (unmapped columns must themselves be valid fields). Multi-value
+// cells (ips, macs) split on spaces or semicolons. The result is
+// partial by definition — absent manual assets stay untouched.
+func (s *Server) handleImportCSV(w http.ResponseWriter, r *http.Request) {
+	if s.reconciler == nil {
+		writeError(w, http.StatusServiceUnavailable, "reconciler is not configured")
+		return
+	}
+	mapping := map[string]string{}
+	for _, raw := range r.URL.Query()["map"] {
+		col, field, ok := strings.Cut(raw, "=")
+		if !ok || col == "" {
+			writeError(w, http.StatusBadRequest, "invalid mapping "+raw+", want column=field")
+			return
+		}
+		if field != "-" && !importFields[field] && !strings.HasPrefix(field, "attr.") {
+			writeError(w, http.StatusBadRequest, "unknown target field "+field)
+			return
+		}
+		mapping[col] = field
+	}
+
+	reader := csv.NewReader(r.Body)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading CSV header failed: "+err.Error())
+		return
+	}
+	fields := make([]string, len(header))
+	for i, col := range header {
+		col = strings.TrimSpace(col)
+		field, ok := mapping[col]
+		if !ok {
+			field = col
+		}
+		if field != "-" && !importFields[field] && !strings.HasPrefix(field, "attr.") {
+			writeError(w, http.StatusBadRequest, "column "+col+" has no valid field mapping")
+			return
+		}
+		fields[i] = field
+	}
+
+	var assets []*model.Asset
+	var rowErrs []importRowError
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			rowErrs = append(rowErrs, importRowError{Line: line, Error: err.Error()})
+			continue
+		}
+		a, err := importRow(fields, record)
+		if err != nil {
+			rowErrs = append(rowErrs, importRowError{Line: line, Error: err.Error()})
+			continue
+		}
+		assets = append(assets, a)
+	}
+
+	resp := importResponse{Rejected: len(rowErrs), Errors: rowErrs}
+	if resp.Errors == nil {
+		resp.Errors = []importRowError{}
+	}
+	if len(assets) > 0 {
+		report, err := s.reconciler.Reconcile(r.Context(), "manual", model.CollectResult{
+			Assets:  assets,
+			Stats:   model.CollectStats{Fetched: line - 1, Filtered: len(rowErrs), Emitted: len(assets)},
+			Partial: true,
+		}, false)
+		if err != nil {
+			s.log.Error("api: csv import", "error", err)
+			writeError(w, http.StatusInternalServerError, "import failed: "+err.Error())
+			return
+		}
+		resp.Imported = len(assets)
+		resp.Report = report
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+}
+
+// importRow builds one asset from a CSV record. Name is required;
+// external_id defaults to the name, which keeps re-imports of the same
+// sheet idempotent.
+func importRow(fields, record []string) (*model.Asset, error) {
+	if len(record) > len(fields) {
+		return nil, fmt.Errorf("row has %d columns, header has %d", len(record), len(fields))
+	}
+	a := &model.Asset{AssetType: "host"}
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		if value == "" || fields[i] == "-" {
+			continue
+		}
+		switch fields[i] {
+		case "name":
+			a.Name = value
+		case "fqdn":
+			a.FQDN = strings.ToLower(value)
+		case "ips":
+			for _, ip := range splitCell(value) {
+				if net.ParseIP(ip) == nil {
+					return nil, fmt.Errorf("invalid IP %q", ip)
+				}
+				a.IPs = append(a.IPs, ip)
+			}
+		case "macs":
+			a.MACs = append(a.MACs, splitCell(value)...)
+		case "serial_number":
+			a.SerialNumber = value
+		case "hardware_uuid":
+			a.HardwareUUID = value
+		case "asset_type":
+			a.AssetType = value
+		case "site":
+			a.Site = value
+		case "external_id":
+			a.ExternalID = value
+		default: // attr.<name>, validated during mapping
+			if a.Attributes == nil {
+				a.Attributes = map[string]any{}
+			}
+			a.Attributes[strings.TrimPrefix(fields[i], "attr.")] = value
+		}
+	}
+	if a.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if a.ExternalID == "" {
+		a.ExternalID = a.Name
+	}
+	return a, nil
+}
+
+func splitCell(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == ';' || r == ','
+	})
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// ingestPayload is the agent push protocol: one host reporting its own
+// inventory. Hostname is required; everything else is optional. The
+// agent's source is derived from the hostname ("agent/<hostname>"), so
+// each host forms its own snapshot and one agent going quiet never
+// marks another host absent.
+type ingestPayload struct {
+	Hostname       string            `json:"hostname"`
+	FQDN           string            `json:"fqdn,omitempty"`
+	IPs            []string          `json:"ips,omitempty"`
+	OS             string            `json:"os,omitempty"`
+	Kernel         string            `json:"kernel,omitempty"`
+	Packages       []string          `json:"packages,omitempty"`
+	ListeningPorts []int             `json:"listening_ports,omitempty"`
+	Attributes     map[string]any    `json:"attributes,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+}
+
+// handleIngest accepts a pushed inventory payload and feeds it through
+// the reconciler exactly like a pull collector's run, so matching,
+// enrichment, change events and conflict handling all apply.
+func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
+	if s.reconciler == nil {
+		writeError(w, http.StatusServiceUnavailable, "reconciler is not configured")
+		return
+	}
+	var payload ingestPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	payload.Hostname = strings.TrimSpace(strings.ToLower(payload.Hostname))
+	if payload.Hostname == "" {
+		writeError(w, http.StatusBadRequest, "hostname is required")
+		return
+	}
+
+	asset := payloadToAsset(payload)
+	source := "agent/" + payload.Hostname
+	report, err := s.reconciler.Reconcile(r.Context(), source, model.CollectResult{
+		Assets: []*model.Asset{asset},
+		Stats:  model.CollectStats{Fetched: 1, Emitted: 1},
+	}, false)
+	if err != nil {
+		s.log.Error("api: ingest", "source", source, "error", err)
+		writeError(w, http.StatusInternalServerError, "ingest failed: "+err.Error())
+		return
+	}
+	writeJSON(w, r, http.StatusOK, report)
+}
+
+func payloadToAsset(p ingestPayload) *model.Asset {
+	a := &model.Asset{
+		Name:       p.Hostname,
+		AssetType:  "host",
+		ExternalID: p.Hostname,
+		Attributes: map[string]any{},
+	}
+	if strings.Contains(p.Hostname, ".") {
+		a.FQDN = p.Hostname
+		a.Name = strings.SplitN(p.Hostname, ".", 2)[0]
+	}
+	if p.FQDN != "" {
+		a.FQDN = strings.ToLower(p.FQDN)
+	}
+	for _, ip := range p.IPs {
+		if net.ParseIP(ip) != nil {
+			a.IPs = append(a.IPs, ip)
+		}
+	}
+	// Agent-supplied attributes first, then the typed fields, so an
+	// agent cannot mask its own os/kernel report with a loose key.
+	for k, v := range p.Attributes {
+		a.Attributes[k] = v
+	}
+	if p.OS != "" {
+		a.Attributes["os"] = p.OS
+	}
+	if p.Kernel != "" {
+		a.Attributes["kernel"] = p.Kernel
+	}
+	if len(p.Packages) > 0 {
+		a.Attributes["package_count"] = len(p.Packages)
+		a.Attributes["packages"] = strings.Join(p.Packages, ",")
+	}
+	if len(p.ListeningPorts) > 0 {
+		ports := make([]string, len(p.ListeningPorts))
+		for i, port := range p.ListeningPorts {
+			ports[i] = strconv.Itoa(port)
+		}
+		a.Attributes["listening_ports"] = strings.Join(ports, ",")
+	}
+	if len(p.Labels) > 0 {
+		a.Attributes["labels"] = p.Labels
+	}
+	return a
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// windowRequest is the create/update body for a maintenance window.
+type windowRequest struct {
+	Name     string            `json:"name"`
+	StartsAt time.Time         `json:"starts_at"`
+	EndsAt   time.Time         `json:"ends_at"`
+	AssetIDs []int64           `json:"asset_ids"`
+	Selector map[string]string `json:"selector"`
+}
+
+func (r *windowRequest) validate() string {
+	if r.Name == "" {
+		return "name is required"
+	}
+	if r.StartsAt.IsZero() || r.EndsAt.IsZero() {
+		return "starts_at and ends_at are required"
+	}
+	if !r.EndsAt.After(r.StartsAt) {
+		return "ends_at must be after starts_at"
+	}
+	if len(r.AssetIDs) == 0 && len(r.Selector) == 0 {
+		return "a window needs asset_ids or a selector, otherwise it covers nothing"
+	}
+	return ""
+}
+
+func (r *windowRequest) window(id int64) *model.MaintenanceWindow {
+	return &model.MaintenanceWindow{
+		ID:       id,
+		Name:     r.Name,
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+		AssetIDs: r.AssetIDs,
+		Selector: r.Selector,
+	}
+}
+
+func (s *Server) handleListWindows(w http.ResponseWriter, r *http.Request) {
+	windows, err := s.store.ListMaintenanceWindows(r.Context())
+	if err != nil {
+		s.log.Error("api: list maintenance windows", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing maintenance windows failed")
+		return
+	}
+	if windows == nil {
+		windows = []*model.MaintenanceWindow{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: windows, Total: int64(len(windows))})
+}
+
+func (s *Server) handleGetWindow(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid maintenance window id")
+		return
+	}
+	win, err := s.store.GetMaintenanceWindow(r.Context(), id)
+	if err == store.ErrNotFound {
+		writeError(w, http.StatusNotFound, "maintenance window not found")
+		return
+	}
+	if err != nil {
+		s.log.Error("api: get maintenance window", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "fetching maintenance window failed")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, win)
+}
+
+func (s *Server) handleCreateWindow(w http.ResponseWriter, r *http.Request) {
+	var req windowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	win := req.window(0)
+	if err := s.store.CreateMaintenanceWindow(r.Context(), win); err != nil {
+		s.log.Error("api: create maintenance window", "name", req.Name, "error", err)
+		writeError(w, http.StatusInternalServerError, "creating maintenance window failed")
+		return
+	}
+	writeJSON(w, r, http.StatusCreated, win)
+}
+
+func (s *Server) handleUpdateWindow(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid maintenance window id")
+		return
+	}
+	var req windowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	win := req.window(id)
+	if err := s.store.UpdateMaintenanceWindow(r.Context(), win); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "maintenance window not found")
+			return
+		}
+		s.log.Error("api: update maintenance window", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "updating maintenance window failed")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, win)
+}
+
+func (s *Server) handleDeleteWindow(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid maintenance window id")
+		return
+	}
+	if err := s.store.DeleteMaintenanceWindow(r.Context(), id); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "maintenance window not found")
+			return
+		}
+		s.log.Error("api: delete maintenance window", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "deleting maintenance window failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
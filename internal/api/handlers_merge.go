@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// handleListDuplicates surfaces groups of assets from different sources
+// sharing an IP or FQDN — the suspected duplicates automatic matching
+// left separate, for review and manual merging.
+func (s *Server) handleListDuplicates(w http.ResponseWriter, r *http.Request) {
+	groups, err := s.store.ListDuplicateAssets(r.Context(), queryInt(r, "limit", 50))
+	if err != nil {
+		s.log.Error("api: list duplicates", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing duplicates failed")
+		return
+	}
+	if groups == nil {
+		groups = []store.DuplicateGroup{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: groups, Total: int64(len(groups))})
+}
+
+// mergeRequest names the asset to fold into the one in the URL.
+type mergeRequest struct {
+	SourceID int64 `json:"source_id"`
+}
+
+// handleMergeAsset merges the asset named in the body into the asset in
+// the URL: fields and attributes are combined onto the survivor (its
+// values win on conflicts), aliases and history move over, the graph
+// edges are rewritten, and the duplicate row is removed.
+func (s *Server) handleMergeAsset(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	var req mergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if req.SourceID == 0 || req.SourceID == id {
+		writeError(w, http.StatusBadRequest, "source_id must name a different asset")
+		return
+	}
+	dst, err := s.store.GetAssetByID(r.Context(), id)
+	if err == nil {
+		var src *model.Asset
+		if src, err = s.store.GetAssetByID(r.Context(), req.SourceID); err == nil {
+			s.mergeAsset(w, r, dst, src)
+			return
+		}
+	}
+	if err == store.ErrNotFound {
+		writeError(w, http.StatusNotFound, "asset not found")
+		return
+	}
+	s.log.Error("api: merge assets", "id", id, "source_id", req.SourceID, "error", err)
+	writeError(w, http.StatusInternalServerError, "merging assets failed")
+}
+
+func (s *Server) mergeAsset(w http.ResponseWriter, r *http.Request, dst, src *model.Asset) {
+	combineAssets(dst, src)
+	if err := s.store.MergeAssets(r.Context(), dst.ID, src.ID); err != nil {
+		s.log.Error("api: merge assets", "id", dst.ID, "source_id", src.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "merging assets failed")
+		return
+	}
+	if err := s.store.UpdateAsset(r.Context(), dst); err != nil {
+		s.log.Error("api: merge update failed", "id", dst.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "merging assets failed")
+		return
+	}
+	if err := s.graph.MergeAssets(r.Context(), src.ID, dst.ID); err != nil {
+		s.log.Warn("api: graph merge failed", "id", dst.ID, "source_id", src.ID, "error", err)
+	}
+	if err := s.graph.UpsertAsset(r.Context(), dst); err != nil {
+		s.log.Warn("api: graph upsert failed", "asset_id", dst.ID, "error", err)
+	}
+	e := &model.ChangeEvent{
+		AssetID: dst.ID,
+		Action:  model.ActionAssetMerged,
+		Source:  "manual",
+		Actor:   "api",
+		Before:  map[string]any{"merged_asset_id": src.ID, "merged_source": src.Source, "merged_external_id": src.ExternalID},
+		After:   map[string]any{"name": dst.Name, "ips": dst.IPs, "fqdn": dst.FQDN},
+	}
+	if err := s.store.CreateChangeEvent(r.Context(), e); err != nil {
+		s.log.Error("api: record merge event", "asset_id", dst.ID, "error", err)
+	}
+	writeJSON(w, r, http.StatusOK, dst)
+}
+
+// combineAssets folds src's identity and attributes onto dst. dst is
+// the survivor, so its values win wherever both assets carry one; the
+// union fields (IPs, MACs) and src-only attributes are adopted.
+func combineAssets(dst, src *model.Asset) {
+	if dst.FQDN == "" {
+		dst.FQDN = src.FQDN
+	}
+	for _, ip := range src.IPs {
+		if !dst.HasIP(ip) {
+			dst.IPs = append(dst.IPs, ip)
+		}
+	}
+	dst.MACs = model.NormalizeMACs(append(dst.MACs, src.MACs...))
+	if dst.SerialNumber == "" {
+		dst.SerialNumber = src.SerialNumber
+	}
+	if dst.HardwareUUID == "" {
+		dst.HardwareUUID = src.HardwareUUID
+	}
+	if dst.Site == "" {
+		dst.Site = src.Site
+	}
+	for k, v := range src.Attributes {
+		if _, ok := dst.Attributes[k]; !ok {
+			if dst.Attributes == nil {
+				dst.Attributes = map[string]any{}
+			}
+			dst.Attributes[k] = v
+		}
+	}
+	for k, v := range src.Tags {
+		if _, ok := dst.Tags[k]; !ok {
+			if dst.Tags == nil {
+				dst.Tags = map[string]string{}
+			}
+			dst.Tags[k] = v
+		}
+	}
+	if src.FirstSeen.Before(dst.FirstSeen) {
+		dst.FirstSeen = src.FirstSeen
+	}
+	if src.LastSeen.After(dst.LastSeen) {
+		dst.LastSeen = src.LastSeen
+	}
+}
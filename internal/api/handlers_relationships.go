@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// relationshipRequest is the body for creating or updating an edge by
+// hand. Edges written this way carry source "manual" and collectors
+// can neither overwrite nor prune them.
+type relationshipRequest struct {
+	FromID     int64          `json:"from_id"`
+	ToID       int64          `json:"to_id"`
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+}
+
+// handleUpsertRelationship creates or updates one manual edge. POST and
+// PUT behave identically — the edge is keyed by (from, to, type).
+func (s *Server) handleUpsertRelationship(w http.ResponseWriter, r *http.Request) {
+	if s.graph == nil {
+		writeError(w, http.StatusServiceUnavailable, "graph store is not configured")
+		return
+	}
+	var req relationshipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if req.FromID == 0 || req.ToID == 0 {
+		writeError(w, http.StatusBadRequest, "from_id and to_id are required")
+		return
+	}
+	if req.FromID == req.ToID {
+		writeError(w, http.StatusBadRequest, "an asset cannot relate to itself")
+		return
+	}
+	relType := model.RelationshipType(req.Type)
+	if !model.ValidRelationshipType(relType) {
+		writeError(w, http.StatusBadRequest, "invalid relationship type "+req.Type)
+		return
+	}
+	for _, id := range []int64{req.FromID, req.ToID} {
+		if _, err := s.store.GetAssetByID(r.Context(), id); err != nil {
+			if err == store.ErrNotFound {
+				writeError(w, http.StatusNotFound, "asset "+strconv.FormatInt(id, 10)+" not found")
+				return
+			}
+			s.log.Error("api: get asset", "id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "writing relationship failed")
+			return
+		}
+	}
+	if err := s.graph.UpsertRelationship(r.Context(), req.FromID, req.ToID, relType, "manual", req.Properties); err != nil {
+		s.log.Error("api: upsert relationship", "from", req.FromID, "to", req.ToID, "type", req.Type, "error", err)
+		writeError(w, http.StatusInternalServerError, "writing relationship failed")
+		return
+	}
+	status := http.StatusOK
+	if r.Method == http.MethodPost {
+		status = http.StatusCreated
+	}
+	writeJSON(w, r, status, map[string]any{
+		"from_id": req.FromID, "to_id": req.ToID, "type": req.Type, "source": "manual",
+	})
+}
+
+// handleDeleteRelationship removes one edge, addressed by from_id,
+// to_id and type query parameters. It deletes manual edges by default;
+// pass source=<collector> to drop a collector's edge (which that
+// collector will recreate on its next run if it still sees it).
+func (s *Server) handleDeleteRelationship(w http.ResponseWriter, r *http.Request) {
+	if s.graph == nil {
+		writeError(w, http.StatusServiceUnavailable, "graph store is not configured")
+		return
+	}
+	q := r.URL.Query()
+	fromID, err1 := strconv.ParseInt(q.Get("from_id"), 10, 64)
+	toID, err2 := strconv.ParseInt(q.Get("to_id"), 10, 64)
+	if err1 != nil || err2 != nil {
+		writeError(w, http.StatusBadRequest, "from_id and to_id are required")
+		return
+	}
+	relType := model.RelationshipType(q.Get("type"))
+	if !model.ValidRelationshipType(relType) {
+		writeError(w, http.StatusBadRequest, "invalid relationship type "+q.Get("type"))
+		return
+	}
+	source := q.Get("source")
+	if source == "" {
+		source = "manual"
+	}
+	if err := s.graph.DeleteRelationship(r.Context(), fromID, toID, relType, source); err != nil {
+		s.log.Error("api: delete relationship", "from", fromID, "to", toID, "type", string(relType), "error", err)
+		writeError(w, http.StatusInternalServerError, "deleting relationship failed")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/qrunner/arch/internal/store"
+)
+
+// handleSearch answers free-text lookups: every word of q must match,
+// each as a prefix, over names, FQDNs, identifiers, addresses and
+// attribute values. Matches come back ranked, best first.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "q parameter is required")
+		return
+	}
+	results, err := s.store.SearchAssets(r.Context(), q, queryInt(r, "limit", 20))
+	if err != nil {
+		s.log.Error("api: search assets", "error", err)
+		writeError(w, http.StatusInternalServerError, "search failed")
+		return
+	}
+	if results == nil {
+		results = []store.SearchResult{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: results, Total: int64(len(results))})
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// serviceRequest is the create/update body for a business service.
+type serviceRequest struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Owner       string                   `json:"owner"`
+	Criticality model.ServiceCriticality `json:"criticality"`
+	Selector    map[string]string        `json:"selector"`
+}
+
+func (r *serviceRequest) validate() string {
+	if r.Name == "" {
+		return "name is required"
+	}
+	if r.Criticality != "" && !model.ValidServiceCriticality(r.Criticality) {
+		return "invalid criticality " + string(r.Criticality) + ", want low, medium, high or critical"
+	}
+	return ""
+}
+
+func (s *Server) handleListServices(w http.ResponseWriter, r *http.Request) {
+	services, err := s.store.ListBusinessServices(r.Context())
+	if err != nil {
+		s.log.Error("api: list services", "error", err)
+		writeError(w, http.StatusInternalServerError, "listing services failed")
+		return
+	}
+	if services == nil {
+		services = []*model.BusinessService{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: services, Total: int64(len(services))})
+}
+
+func (s *Server) handleGetService(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid service id")
+		return
+	}
+	svc, err := s.store.GetBusinessService(r.Context(), id)
+	if err == store.ErrNotFound {
+		writeError(w, http.StatusNotFound, "service not found")
+		return
+	}
+	if err != nil {
+		s.log.Error("api: get service", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "fetching service failed")
+		return
+	}
+	if svc.Assets, err = s.store.ListServiceMembers(r.Context(), id); err != nil {
+		s.log.Error("api: list service members", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "fetching service failed")
+		return
+	}
+	writeJSON(w, r, http.StatusOK, svc)
+}
+
+func (s *Server) handleCreateService(w http.ResponseWriter, r *http.Request) {
+	var req serviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	svc := &model.BusinessService{
+		Name:        req.Name,
+		Description: req.Description,
+		Owner:       req.Owner,
+		Criticality: req.Criticality,
+		Selector:    req.Selector,
+	}
+	if err := s.store.CreateBusinessService(r.Context(), svc); err != nil {
+		s.log.Error("api: create service", "name", req.Name, "error", err)
+		writeError(w, http.StatusInternalServerError, "creating service failed")
+		return
+	}
+	s.syncServiceGraph(r, svc)
+	writeJSON(w, r, http.StatusCreated, svc)
+}
+
+func (s *Server) handleUpdateService(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid service id")
+		return
+	}
+	var req serviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	svc := &model.BusinessService{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		Owner:       req.Owner,
+		Criticality: req.Criticality,
+		Selector:    req.Selector,
+	}
+	if svc.Criticality == "" {
+		svc.Criticality = model.CriticalityMedium
+	}
+	if err := s.store.UpdateBusinessService(r.Context(), svc); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "service not found")
+			return
+		}
+		s.log.Error("api: update service", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "updating service failed")
+		return
+	}
+	s.syncServiceGraph(r, svc)
+	writeJSON(w, r, http.StatusOK, svc)
+}
+
+func (s *Server) handleDeleteService(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid service id")
+		return
+	}
+	if err := s.store.DeleteBusinessService(r.Context(), id); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "service not found")
+			return
+		}
+		s.log.Error("api: delete service", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "deleting service failed")
+		return
+	}
+	if err := s.graph.DeleteService(r.Context(), id); err != nil {
+		s.log.Warn("api: graph service delete failed", "service_id", id, "error", err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serviceAssetsRequest attaches and detaches explicit members.
+type serviceAssetsRequest struct {
+	Add    []int64 `json:"add"`
+	Remove []int64 `json:"remove"`
+}
+
+func (s *Server) handleUpdateServiceAssets(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid service id")
+		return
+	}
+	var req serviceAssetsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		writeError(w, http.StatusBadRequest, "nothing to do: add and remove are both empty")
+		return
+	}
+	if err := s.store.UpdateServiceAssets(r.Context(), id, req.Add, req.Remove); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "service not found")
+			return
+		}
+		s.log.Error("api: update service assets", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "updating service membership failed")
+		return
+	}
+	svc, err := s.store.GetBusinessService(r.Context(), id)
+	if err != nil {
+		s.log.Error("api: get service", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "updating service membership failed")
+		return
+	}
+	if svc.Assets, err = s.store.ListServiceMembers(r.Context(), id); err != nil {
+		s.log.Error("api: list service members", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "updating service membership failed")
+		return
+	}
+	s.syncServiceGraph(r, svc)
+	writeJSON(w, r, http.StatusOK, svc)
+}
+
+// handleAssetServices answers the impact question from the service
+// side: which business services does this asset belong to.
+func (s *Server) handleAssetServices(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	services, err := s.store.ListServicesForAsset(r.Context(), id)
+	if err != nil {
+		s.log.Error("api: list asset services", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "listing services failed")
+		return
+	}
+	if services == nil {
+		services = []*model.BusinessService{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: services, Total: int64(len(services))})
+}
+
+// syncServiceGraph mirrors a service and its resolved membership into
+// the graph. Failures are logged, not surfaced: the graph is a replica.
+func (s *Server) syncServiceGraph(r *http.Request, svc *model.BusinessService) {
+	members := svc.Assets
+	if members == nil {
+		var err error
+		if members, err = s.store.ListServiceMembers(r.Context(), svc.ID); err != nil {
+			s.log.Warn("api: resolve service members", "service_id", svc.ID, "error", err)
+			return
+		}
+	}
+	ids := make([]int64, 0, len(members))
+	for _, a := range members {
+		ids = append(ids, a.ID)
+	}
+	if err := s.graph.UpsertService(r.Context(), svc, ids); err != nil {
+		s.log.Warn("api: graph service upsert failed", "service_id", svc.ID, "error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// handleListAssetSoftware serves an asset's installed packages across
+// all reporting sources.
+func (s *Server) handleListAssetSoftware(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	if _, err := s.store.GetAssetByID(r.Context(), id); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: get asset", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "listing software failed")
+		return
+	}
+	pkgs, err := s.store.ListAssetSoftware(r.Context(), id)
+	if err != nil {
+		s.log.Error("api: list software", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "listing software failed")
+		return
+	}
+	if pkgs == nil {
+		pkgs = []model.SoftwarePackage{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: pkgs, Total: int64(len(pkgs))})
+}
+
+// handleSearchSoftware answers inventory-wide package queries like
+// "which assets have openssl below 3.0.13": name is required,
+// version_below optional.
+func (s *Server) handleSearchSoftware(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	matches, err := s.store.FindSoftware(r.Context(), name)
+	if err != nil {
+		s.log.Error("api: search software", "name", name, "error", err)
+		writeError(w, http.StatusInternalServerError, "searching software failed")
+		return
+	}
+	if below := r.URL.Query().Get("version_below"); below != "" {
+		kept := matches[:0]
+		for _, m := range matches {
+			if model.CompareVersions(m.Package.Version, below) < 0 {
+				kept = append(kept, m)
+			}
+		}
+		matches = kept
+	}
+	if matches == nil {
+		matches = []store.SoftwareMatch{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: matches, Total: int64(len(matches))})
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// handleListAssetVulnerabilities serves an asset's scanner findings,
+// open and resolved, across all reporting sources.
+func (s *Server) handleListAssetVulnerabilities(w http.ResponseWriter, r *http.Request) {
+	id, ok := idParam(r)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid asset id")
+		return
+	}
+	if _, err := s.store.GetAssetByID(r.Context(), id); err != nil {
+		if err == store.ErrNotFound {
+			writeError(w, http.StatusNotFound, "asset not found")
+			return
+		}
+		s.log.Error("api: get asset", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "listing vulnerabilities failed")
+		return
+	}
+	vulns, err := s.store.ListAssetVulnerabilities(r.Context(), id)
+	if err != nil {
+		s.log.Error("api: list vulnerabilities", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "listing vulnerabilities failed")
+		return
+	}
+	if vulns == nil {
+		vulns = []model.Vulnerability{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: vulns, Total: int64(len(vulns))})
+}
+
+// handleSearchVulnerabilities answers "which assets are exposed to this
+// CVE" across the inventory.
+func (s *Server) handleSearchVulnerabilities(w http.ResponseWriter, r *http.Request) {
+	cve := r.URL.Query().Get("cve")
+	if cve == "" {
+		writeError(w, http.StatusBadRequest, "cve is required")
+		return
+	}
+	matches, err := s.store.FindVulnerableAssets(r.Context(), cve)
+	if err != nil {
+		s.log.Error("api: search vulnerabilities", "cve", cve, "error", err)
+		writeError(w, http.StatusInternalServerError, "searching vulnerabilities failed")
+		return
+	}
+	if matches == nil {
+		matches = []store.VulnMatch{}
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{Items: matches, Total: int64(len(matches))})
+}
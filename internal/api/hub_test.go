@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bufio"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+// TestEventStreamCrossReplica verifies the HA wiring: two API server
+// instances whose hubs are both fed from a shared subject (as the NATS
+// subscription does in production), where an event originating at one
+// replica reaches an SSE client connected to the other.
+func TestEventStreamCrossReplica(t *testing.T) {
+	hub1 := NewHub()
+	hub2 := NewHub()
+	// Stands in for the shared NATS subject: every replica's
+	// subscription sees every published event.
+	broadcast := func(e *model.ChangeEvent) {
+		hub1.Publish(e)
+		hub2.Publish(e)
+	}
+
+	log := slog.New(slog.NewTextHandler(&strings.Builder{}, nil))
+	cfg := &config.Config{}
+	replica1 := httptest.NewServer(New(cfg, nil, nil, nil, nil, hub1, nil, log))
+	defer replica1.Close()
+	replica2 := httptest.NewServer(New(cfg, nil, nil, nil, nil, hub2, nil, log))
+	defer replica2.Close()
+
+	resp, err := http.Get(replica2.URL + "/api/v1/events/stream")
+	if err != nil {
+		t.Fatalf("subscribe on replica 2: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("subscribe status = %d, want 200", resp.StatusCode)
+	}
+
+	lines := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				lines <- line
+				return
+			}
+		}
+	}()
+
+	// Give the SSE handler a moment to register its client, then
+	// "reconcile" an event at replica 1.
+	time.Sleep(50 * time.Millisecond)
+	broadcast(&model.ChangeEvent{ID: 42, AssetID: 7, Action: model.ActionAssetUpdated, Source: "test"})
+
+	select {
+	case line := <-lines:
+		if !strings.Contains(line, model.ActionAssetUpdated) {
+			t.Fatalf("received %q, want an %s event", line, model.ActionAssetUpdated)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("event published at replica 1 never reached the client on replica 2")
+	}
+}
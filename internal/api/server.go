@@ -0,0 +1,219 @@
+// Package api implements the archd HTTP API under /api/v1.
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/events"
+	"github.com/qrunner/arch/internal/graph"
+	"github.com/qrunner/arch/internal/reconcile"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// Server holds the API's dependencies and its router.
+type Server struct {
+	store      store.Store
+	graph      *graph.Store
+	scheduler  *collector.Scheduler
+	reconciler *reconcile.Reconciler
+	hub        *Hub
+	publisher  *events.Publisher
+	auth       *authenticator
+	pprof      bool
+	log        *slog.Logger
+	router     chi.Router
+}
+
+// New wires up the API. graph, scheduler, rec, hub and pub may be nil.
+func New(cfg *config.Config, st store.Store, gr *graph.Store, sched *collector.Scheduler, rec *reconcile.Reconciler, hub *Hub, pub *events.Publisher, log *slog.Logger) *Server {
+	s := &Server{
+		store:      st,
+		graph:      gr,
+		scheduler:  sched,
+		reconciler: rec,
+		hub:        hub,
+		publisher:  pub,
+		auth:       newAuthenticator(cfg.Auth),
+		pprof:      cfg.Debug.EnablePprof,
+		log:        log,
+	}
+	s.routes()
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Use(requestLogger(s.log))
+
+	r.Get("/healthz", s.handleHealthz)
+
+	if s.pprof {
+		// Profiling endpoints are opt-in and admin-only: they expose
+		// internals and a CPU profile is itself a load spike.
+		r.Route("/debug/pprof", func(r chi.Router) {
+			r.Use(s.auth.require(scopeAdmin))
+			r.Get("/cmdline", pprof.Cmdline)
+			r.Get("/profile", pprof.Profile)
+			r.Get("/symbol", pprof.Symbol)
+			r.Get("/trace", pprof.Trace)
+			// Index also serves the named profiles (heap, goroutine,
+			// block, mutex, ...) by path suffix.
+			r.Get("/", pprof.Index)
+			r.Get("/*", pprof.Index)
+		})
+	}
+
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Group(func(r chi.Router) {
+			r.Use(s.auth.require(scopeRead))
+			r.Get("/assets", s.handleListAssets)
+			r.Get("/search", s.handleSearch)
+			r.Get("/assets/facets", s.handleAssetFacets)
+			r.Get("/assets/export", s.handleExportAssets)
+			r.Get("/assets/duplicates", s.handleListDuplicates)
+			r.Get("/assets/{id}", s.handleGetAsset)
+			r.Get("/assets/{id}/events", s.handleListAssetEvents)
+			r.Get("/assets/{id}/dependencies", s.handleDependencies)
+			r.Get("/assets/{id}/impact", s.handleImpact)
+			r.Get("/assets/{id}/services", s.handleAssetServices)
+			r.Get("/assets/{id}/software", s.handleListAssetSoftware)
+			r.Get("/software", s.handleSearchSoftware)
+			r.Get("/assets/{id}/vulnerabilities", s.handleListAssetVulnerabilities)
+			r.Get("/vulnerabilities", s.handleSearchVulnerabilities)
+			r.Get("/services", s.handleListServices)
+			r.Get("/services/{id}", s.handleGetService)
+			r.Get("/maintenance-windows", s.handleListWindows)
+			r.Get("/maintenance-windows/{id}", s.handleGetWindow)
+			r.Get("/graph/subgraph", s.handleSubgraph)
+			r.Get("/graph/relationship-types", s.handleRelationshipTypes)
+			r.Get("/graph/isolated-assets", s.handleIsolatedAssets)
+			r.Get("/dashboard", s.handleDashboard)
+			r.Get("/collectors", s.handleListCollectors)
+			r.Get("/collectors/{name}/runs", s.handleListCollectorRuns)
+			r.Get("/reconcile/conflicts", s.handleListConflicts)
+			r.Get("/reconcile/quarantine", s.handleListQuarantine)
+			r.Get("/custom-fields", s.handleListCustomFields)
+			r.Get("/status", s.handleStatus)
+			r.Get("/events/stream", s.handleEventStream)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(s.auth.require(scopeWrite))
+			r.Post("/assets", s.handleCreateAsset)
+			r.Post("/assets/tags/bulk", s.handleBulkTag)
+			r.Post("/assets/owner/bulk", s.handleBulkAssignOwner)
+			r.Post("/assets/touch", s.handleTouchAssets)
+			r.Post("/ingest", s.handleIngest)
+			r.Post("/import/csv", s.handleImportCSV)
+			r.Post("/assets/approve/bulk", s.handleBulkApprove)
+			r.Post("/assets/{id}/approve", s.handleApproveAsset)
+			r.Put("/assets/{id}/notes", s.handleUpdateNotes)
+			r.Put("/assets/{id}/owner", s.handleAssignOwner)
+			r.Post("/assets/{id}/tags", s.handleUpdateTags)
+			r.Post("/assets/{id}/custom", s.handleUpdateCustom)
+			r.Post("/services", s.handleCreateService)
+			r.Put("/services/{id}", s.handleUpdateService)
+			r.Delete("/services/{id}", s.handleDeleteService)
+			r.Post("/services/{id}/assets", s.handleUpdateServiceAssets)
+			r.Post("/relationships", s.handleUpsertRelationship)
+			r.Put("/relationships", s.handleUpsertRelationship)
+			r.Delete("/relationships", s.handleDeleteRelationship)
+			r.Post("/maintenance-windows", s.handleCreateWindow)
+			r.Put("/maintenance-windows/{id}", s.handleUpdateWindow)
+			r.Delete("/maintenance-windows/{id}", s.handleDeleteWindow)
+			r.Post("/collectors/{name}/run", s.handleRunCollector)
+			r.Post("/collectors/{name}/preview", s.handlePreviewCollector)
+			r.Post("/reconcile/quarantine/{id}/approve", s.handleApproveQuarantined)
+			r.Delete("/reconcile/quarantine/{id}", s.handleDiscardQuarantined)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(s.auth.require(scopeAdmin))
+			r.Delete("/assets/{id}", s.handleDeleteAsset)
+			r.Delete("/assets/{id}/purge", s.handlePurgeAsset)
+			r.Post("/assets/{id}/restore", s.handleRestoreAsset)
+			r.Post("/assets/{id}/merge", s.handleMergeAsset)
+			r.Post("/custom-fields", s.handleCreateCustomField)
+			r.Delete("/custom-fields/{id}", s.handleDeleteCustomField)
+			r.Post("/admin/reconcile/rebuild", s.handleRebuild)
+		})
+	})
+
+	s.router = r
+}
+
+func requestLogger(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+			log.Debug("api: request", "method", r.Method, "path", r.URL.Path, "status", ww.Status())
+		})
+	}
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// writeJSON renders v as the response body with the given status.
+// Output is compact by default; a pretty=true query parameter switches
+// to indented output for debugging with curl. r may be nil (always
+// compact).
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	if r != nil {
+		if loc := requestLocation(r); loc != nil {
+			applyTimezone(v, loc)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	if r != nil && r.URL.Query().Get("pretty") == "true" {
+		enc.SetIndent("", "  ")
+	}
+	enc.Encode(v)
+}
+
+// apiError is the uniform error body.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, nil, status, apiError{Error: msg})
+}
+
+// idParam parses the {id} URL parameter.
+func idParam(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// queryInt parses an integer query parameter, returning def when absent
+// or malformed.
+func queryInt(r *http.Request, name string, def int) int {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// requestLocation returns the time zone requested via the tz query
+// parameter (an IANA name like "Europe/Berlin"), or nil when absent or
+// invalid. Storage and SQL math stay UTC; the zone only affects how
+// timestamps are rendered in the response.
+func requestLocation(r *http.Request) *time.Location {
+	name := r.URL.Query().Get("tz")
+	if name == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// applyTimezone walks v and rewrites every reachable time.Time into the
+// given location, so the encoder renders RFC3339 offsets in the
+// requested zone. Only values reachable through pointers and slices are
+// rewritten, which covers the API's response shapes.
+func applyTimezone(v any, loc *time.Location) {
+	walkTimes(reflect.ValueOf(v), loc)
+}
+
+func walkTimes(rv reflect.Value, loc *time.Location) {
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !rv.IsNil() {
+			walkTimes(rv.Elem(), loc)
+		}
+	case reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			walkTimes(rv.Index(i), loc)
+		}
+	case reflect.Struct:
+		if rv.Type() == timeType {
+			if rv.CanSet() {
+				if t := rv.Interface().(time.Time); !t.IsZero() {
+					rv.Set(reflect.ValueOf(t.In(loc)))
+				}
+			}
+			return
+		}
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Type().Field(i).IsExported() {
+				walkTimes(rv.Field(i), loc)
+			}
+		}
+	}
+}
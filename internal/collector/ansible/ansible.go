@@ -0,0 +1,220 @@
+// Package ansible imports hosts from an Ansible inventory file (INI or
+// dynamic-inventory JSON) and enriches them from a jsonfile facts
+// cache. Hosts are linked to their groups and groups to their parents
+// with MEMBER_OF edges, so inventory structure carries into the graph.
+package ansible
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("ansible", New)
+}
+
+// Collector reads Ansible inventory and facts from disk.
+type Collector struct {
+	name          string
+	inventoryPath string
+	factsDir      string
+}
+
+// New builds the collector from config. Required settings:
+// inventory_path. Optional: facts_dir (a jsonfile facts cache).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	path := cfg.Settings["inventory_path"]
+	if path == "" {
+		return nil, fmt.Errorf("ansible: inventory_path setting is required")
+	}
+	return &Collector{
+		name:          cfg.Name,
+		inventoryPath: path,
+		factsDir:      cfg.Settings["facts_dir"],
+	}, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect parses the configured inventory and facts cache.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	raw, err := os.ReadFile(c.inventoryPath)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("ansible: read inventory: %w", err)
+	}
+	inv, err := parseInventory(raw)
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	return c.buildResult(inv), nil
+}
+
+// Preview implements collector.Previewer: it parses an uploaded
+// inventory instead of the configured path. The facts cache still
+// comes from disk, since facts are not part of the upload.
+func (c *Collector) Preview(ctx context.Context, payload []byte) (model.CollectResult, error) {
+	inv, err := parseInventory(payload)
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	return c.buildResult(inv), nil
+}
+
+// syntheticGroups are Ansible's implicit groups: every host belongs to
+// them, so they carry no topology worth emitting.
+var syntheticGroups = map[string]bool{"all": true, "ungrouped": true}
+
+func (c *Collector) buildResult(inv *inventory) model.CollectResult {
+	var res model.CollectResult
+	hosts := map[string]*model.Asset{}
+	for group, members := range inv.groups {
+		if !syntheticGroups[group] {
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       group,
+				AssetType:  "ansible_group",
+				ExternalID: "group/" + group,
+				Attributes: map[string]any{"host_count": len(members)},
+			})
+		}
+		for _, name := range members {
+			if hosts[name] == nil {
+				hosts[name] = c.hostAsset(name, inv.hostVars[name])
+			}
+			if !syntheticGroups[group] {
+				res.Relationships = append(res.Relationships, model.Relationship{
+					FromExternalID: name,
+					ToExternalID:   "group/" + group,
+					Type:           model.RelMemberOf,
+				})
+			}
+		}
+	}
+	for parent, kids := range inv.children {
+		if syntheticGroups[parent] {
+			continue
+		}
+		for _, child := range kids {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: "group/" + child,
+				ToExternalID:   "group/" + parent,
+				Type:           model.RelMemberOf,
+			})
+		}
+	}
+	for _, a := range hosts {
+		res.Assets = append(res.Assets, a)
+	}
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res
+}
+
+// hostAsset maps one inventory host, folding in its vars and, when a
+// facts cache is configured, its cached facts.
+func (c *Collector) hostAsset(name string, vars map[string]any) *model.Asset {
+	a := &model.Asset{
+		Name:       name,
+		AssetType:  "host",
+		ExternalID: name,
+		Attributes: map[string]any{},
+	}
+	if strings.Contains(name, ".") {
+		a.FQDN = name
+	}
+	if raw, ok := vars["ansible_host"].(string); ok && raw != "" {
+		if net.ParseIP(raw) != nil {
+			a.IPs = append(a.IPs, raw)
+		} else if a.FQDN == "" {
+			a.FQDN = raw
+		}
+	}
+	if c.factsDir != "" {
+		applyFacts(a, filepath.Join(c.factsDir, name))
+	}
+	return a
+}
+
+// applyFacts enriches an asset from one jsonfile cache entry. A missing
+// or malformed entry is not an error: the cache only covers hosts a
+// play has touched recently.
+func applyFacts(a *model.Asset, path string) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var facts map[string]any
+	if err := json.Unmarshal(raw, &facts); err != nil {
+		return
+	}
+	// Older cache plugins wrap the facts in an ansible_facts key.
+	if inner, ok := facts["ansible_facts"].(map[string]any); ok {
+		facts = inner
+	}
+	if fqdn, ok := facts["ansible_fqdn"].(string); ok && fqdn != "" {
+		a.FQDN = fqdn
+	}
+	if dist, ok := facts["ansible_distribution"].(string); ok && dist != "" {
+		os := dist
+		if ver, ok := facts["ansible_distribution_version"].(string); ok && ver != "" {
+			os += " " + ver
+		}
+		a.Attributes["os"] = os
+	}
+	if serial, ok := facts["ansible_product_serial"].(string); ok && serial != "" && serial != "NA" {
+		a.SerialNumber = serial
+	}
+	if uuid, ok := facts["ansible_product_uuid"].(string); ok && uuid != "" && uuid != "NA" {
+		a.HardwareUUID = uuid
+	}
+	if mem, ok := facts["ansible_memtotal_mb"].(float64); ok {
+		a.Attributes["memory_mb"] = int(mem)
+	}
+	if cpus, ok := facts["ansible_processor_vcpus"].(float64); ok {
+		a.Attributes["cpu_count"] = int(cpus)
+	}
+	if ifaces, ok := facts["ansible_interfaces"].([]any); ok {
+		var names []string
+		for _, i := range ifaces {
+			if s, ok := i.(string); ok && s != "lo" {
+				names = append(names, s)
+			}
+		}
+		if len(names) > 0 {
+			a.Attributes["interfaces"] = names
+		}
+	}
+	if addrs, ok := facts["ansible_all_ipv4_addresses"].([]any); ok {
+		for _, addr := range addrs {
+			if s, ok := addr.(string); ok && s != "" && !contains(a.IPs, s) {
+				a.IPs = append(a.IPs, s)
+			}
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "inventory_path", Type: collector.TypeString, Required: true, Description: "path to the Ansible inventory file"},
+		{Name: "facts_dir", Type: collector.TypeString, Description: "directory of cached setup-module fact files"},
+	}
+}
@@ -0,0 +1,94 @@
+package ansible
+
+import "testing"
+
+const sampleINI = `
+# web tier
+[web]
+web1.example.com ansible_host=10.0.0.10
+web2.example.com
+
+[db]
+db1 ansible_host=10.0.0.20
+
+[db:vars]
+ansible_user=postgres
+
+[prod:children]
+web
+db
+`
+
+const sampleJSON = `{
+  "web": {"hosts": ["web1.example.com", "web2.example.com"]},
+  "db": {"hosts": ["db1"]},
+  "prod": {"children": ["web", "db"]},
+  "_meta": {"hostvars": {"web1.example.com": {"ansible_host": "10.0.0.10"}}}
+}`
+
+func TestParseINIAndJSONAgree(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		raw  string
+	}{
+		{"ini", sampleINI},
+		{"json", sampleJSON},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			inv, err := parseInventory([]byte(tc.raw))
+			if err != nil {
+				t.Fatalf("parseInventory: %v", err)
+			}
+			if got := inv.groups["web"]; len(got) != 2 || got[0] != "web1.example.com" {
+				t.Errorf("web hosts = %v, want [web1.example.com web2.example.com]", got)
+			}
+			if got := inv.children["prod"]; len(got) != 2 {
+				t.Errorf("prod children = %v, want [web db]", got)
+			}
+			if got := inv.hostVars["web1.example.com"]["ansible_host"]; got != "10.0.0.10" {
+				t.Errorf("ansible_host = %v, want 10.0.0.10", got)
+			}
+		})
+	}
+}
+
+func TestINIGroupVars(t *testing.T) {
+	inv, err := parseInventory([]byte(sampleINI))
+	if err != nil {
+		t.Fatalf("parseInventory: %v", err)
+	}
+	if got := inv.hostVars["db1"]["ansible_user"]; got != "postgres" {
+		t.Errorf("db1 ansible_user = %v, want postgres", got)
+	}
+	// The host-level var must survive group vars.
+	if got := inv.hostVars["db1"]["ansible_host"]; got != "10.0.0.20" {
+		t.Errorf("db1 ansible_host = %v, want 10.0.0.20", got)
+	}
+}
+
+func TestBuildResultEmitsMembership(t *testing.T) {
+	inv, err := parseInventory([]byte(sampleINI))
+	if err != nil {
+		t.Fatalf("parseInventory: %v", err)
+	}
+	c := &Collector{name: "ansible"}
+	res := c.buildResult(inv)
+	// 3 groups + 3 hosts.
+	if len(res.Assets) != 6 {
+		t.Fatalf("got %d assets, want 6", len(res.Assets))
+	}
+	var hostEdges, groupEdges int
+	for _, rel := range res.Relationships {
+		if rel.Type != "MEMBER_OF" {
+			t.Errorf("unexpected relationship type %q", rel.Type)
+		}
+		if rel.FromExternalID == "group/web" || rel.FromExternalID == "group/db" {
+			groupEdges++
+		} else {
+			hostEdges++
+		}
+	}
+	if hostEdges != 3 || groupEdges != 2 {
+		t.Errorf("got %d host and %d group edges, want 3 and 2", hostEdges, groupEdges)
+	}
+}
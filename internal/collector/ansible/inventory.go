@@ -0,0 +1,159 @@
+package ansible
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// inventory is the parser-neutral view of an Ansible inventory; both
+// the INI and JSON parsers produce it.
+type inventory struct {
+	// groups maps a group name to its directly listed hosts.
+	groups map[string][]string
+	// children maps a group name to its child groups.
+	children map[string][]string
+	// hostVars holds per-host variables (ansible_host and friends).
+	hostVars map[string]map[string]any
+}
+
+func newInventory() *inventory {
+	return &inventory{
+		groups:   map[string][]string{},
+		children: map[string][]string{},
+		hostVars: map[string]map[string]any{},
+	}
+}
+
+func (inv *inventory) setVar(host, key string, value any) {
+	if inv.hostVars[host] == nil {
+		inv.hostVars[host] = map[string]any{}
+	}
+	inv.hostVars[host][key] = value
+}
+
+// parseInventory dispatches to the JSON or INI parser by sniffing the
+// content; Ansible itself distinguishes the two the same way.
+func parseInventory(raw []byte) (*inventory, error) {
+	trimmed := bytes.TrimLeft(raw, " \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseJSONInventory(raw)
+	}
+	return parseINIInventory(raw)
+}
+
+// parseJSONInventory reads the dynamic-inventory JSON format: one key
+// per group with hosts/children/vars, plus _meta.hostvars.
+func parseJSONInventory(raw []byte) (*inventory, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("ansible: parse JSON inventory: %w", err)
+	}
+	inv := newInventory()
+	for name, rawGroup := range doc {
+		if name == "_meta" {
+			var meta struct {
+				HostVars map[string]map[string]any `json:"hostvars"`
+			}
+			if err := json.Unmarshal(rawGroup, &meta); err != nil {
+				return nil, fmt.Errorf("ansible: parse _meta: %w", err)
+			}
+			for host, vars := range meta.HostVars {
+				for k, v := range vars {
+					inv.setVar(host, k, v)
+				}
+			}
+			continue
+		}
+		// A group may be a full object or, in older scripts, a bare
+		// host list.
+		var hosts []string
+		if err := json.Unmarshal(rawGroup, &hosts); err == nil {
+			inv.groups[name] = hosts
+			continue
+		}
+		var group struct {
+			Hosts    []string       `json:"hosts"`
+			Children []string       `json:"children"`
+			Vars     map[string]any `json:"vars"`
+		}
+		if err := json.Unmarshal(rawGroup, &group); err != nil {
+			return nil, fmt.Errorf("ansible: parse group %q: %w", name, err)
+		}
+		inv.groups[name] = group.Hosts
+		inv.children[name] = group.Children
+	}
+	return inv, nil
+}
+
+// parseINIInventory reads the classic INI format: [group] host lines
+// with optional key=value vars, [group:children] and [group:vars]
+// sections. Hosts before the first section land in "ungrouped".
+func parseINIInventory(raw []byte) (*inventory, error) {
+	inv := newInventory()
+	section, kind := "ungrouped", ""
+	groupVars := map[string]map[string]any{}
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("ansible: inventory line %d: malformed section %q", lineNo, line)
+			}
+			section, kind = strings.Trim(line, "[]"), ""
+			if name, suffix, ok := strings.Cut(section, ":"); ok {
+				section, kind = name, suffix
+			}
+			// A group mentioned only via :children or :vars still
+			// exists.
+			if _, ok := inv.groups[section]; !ok {
+				inv.groups[section] = nil
+			}
+			continue
+		}
+		switch kind {
+		case "children":
+			inv.children[section] = append(inv.children[section], line)
+		case "vars":
+			k, v, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("ansible: inventory line %d: malformed var %q", lineNo, line)
+			}
+			if groupVars[section] == nil {
+				groupVars[section] = map[string]any{}
+			}
+			groupVars[section][strings.TrimSpace(k)] = strings.TrimSpace(v)
+		default:
+			fields := strings.Fields(line)
+			host := fields[0]
+			inv.groups[section] = append(inv.groups[section], host)
+			for _, f := range fields[1:] {
+				k, v, ok := strings.Cut(f, "=")
+				if !ok {
+					return nil, fmt.Errorf("ansible: inventory line %d: malformed var %q", lineNo, f)
+				}
+				inv.setVar(host, k, v)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("ansible: read inventory: %w", err)
+	}
+	// Group vars apply to the group's hosts unless the host overrides
+	// them; that is all the precedence the collector needs.
+	for group, vars := range groupVars {
+		for _, host := range inv.groups[group] {
+			for k, v := range vars {
+				if _, ok := inv.hostVars[host][k]; !ok {
+					inv.setVar(host, k, v)
+				}
+			}
+		}
+	}
+	return inv, nil
+}
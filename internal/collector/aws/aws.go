@@ -0,0 +1,331 @@
+// Package aws imports EC2 instances, VPCs, subnets, load balancers and
+// RDS instances from one AWS account across one or more regions. VPC
+// containment is modelled with CONTAINS edges (VPC > subnet > instance)
+// and load balancers are linked to their registered instance targets
+// with LOAD_BALANCES edges.
+//
+// One collector instance covers one account; estates with several
+// accounts run several instances with different profiles or role ARNs,
+// which is how the rest of the collector config works too.
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("aws", New)
+}
+
+// Collector enumerates one AWS account.
+type Collector struct {
+	name    string
+	regions []string
+	profile string
+	roleARN string
+}
+
+// New builds the collector from config. Required settings: regions (a
+// comma-separated list). Optional: profile (a shared-config profile)
+// and assume_role_arn (a role to assume after the base credentials
+// resolve; this is the usual cross-account pattern).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:    cfg.Name,
+		profile: cfg.Settings["profile"],
+		roleARN: cfg.Settings["assume_role_arn"],
+	}
+	for _, r := range strings.Split(cfg.Settings["regions"], ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			c.regions = append(c.regions, r)
+		}
+	}
+	if len(c.regions) == 0 {
+		return nil, fmt.Errorf("aws: regions setting is required")
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect walks every configured region. External IDs are the AWS
+// resource IDs (i-..., vpc-..., subnet-...) and ARNs for load
+// balancers and RDS, which is what operators paste into the console.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if c.profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(c.profile))
+	}
+	base, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("aws: load credentials: %w", err)
+	}
+	if c.roleARN != "" {
+		base.Credentials = aws.NewCredentialsCache(
+			stscreds.NewAssumeRoleProvider(sts.NewFromConfig(base), c.roleARN))
+	}
+	var res model.CollectResult
+	for _, region := range c.regions {
+		cfg := base.Copy()
+		cfg.Region = region
+		if err := c.collectRegion(ctx, cfg, region, &res); err != nil {
+			return model.CollectResult{}, fmt.Errorf("aws: region %s: %w", region, err)
+		}
+	}
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func (c *Collector) collectRegion(ctx context.Context, cfg aws.Config, region string, res *model.CollectResult) error {
+	ec2c := ec2.NewFromConfig(cfg)
+
+	vpcs := ec2.NewDescribeVpcsPaginator(ec2c, &ec2.DescribeVpcsInput{})
+	for vpcs.HasMorePages() {
+		page, err := vpcs.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("describe vpcs: %w", err)
+		}
+		for _, vpc := range page.Vpcs {
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       nameOrID(vpc.Tags, aws.ToString(vpc.VpcId)),
+				AssetType:  "network",
+				ExternalID: aws.ToString(vpc.VpcId),
+				Attributes: map[string]any{
+					"cidr":   aws.ToString(vpc.CidrBlock),
+					"region": region,
+				},
+			})
+		}
+	}
+
+	subnets := ec2.NewDescribeSubnetsPaginator(ec2c, &ec2.DescribeSubnetsInput{})
+	for subnets.HasMorePages() {
+		page, err := subnets.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("describe subnets: %w", err)
+		}
+		for _, sn := range page.Subnets {
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       nameOrID(sn.Tags, aws.ToString(sn.SubnetId)),
+				AssetType:  "subnet",
+				ExternalID: aws.ToString(sn.SubnetId),
+				Attributes: map[string]any{
+					"cidr":              aws.ToString(sn.CidrBlock),
+					"availability_zone": aws.ToString(sn.AvailabilityZone),
+					"region":            region,
+				},
+			})
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: aws.ToString(sn.VpcId),
+				ToExternalID:   aws.ToString(sn.SubnetId),
+				Type:           model.RelContains,
+			})
+		}
+	}
+
+	instances := ec2.NewDescribeInstancesPaginator(ec2c, &ec2.DescribeInstancesInput{})
+	for instances.HasMorePages() {
+		page, err := instances.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("describe instances: %w", err)
+		}
+		for _, rsv := range page.Reservations {
+			for _, inst := range rsv.Instances {
+				if inst.State != nil && inst.State.Name == ec2types.InstanceStateNameTerminated {
+					continue
+				}
+				res.Assets = append(res.Assets, instanceToAsset(inst, region))
+				if id := aws.ToString(inst.SubnetId); id != "" {
+					res.Relationships = append(res.Relationships, model.Relationship{
+						FromExternalID: id,
+						ToExternalID:   aws.ToString(inst.InstanceId),
+						Type:           model.RelContains,
+					})
+				}
+			}
+		}
+	}
+
+	if err := c.collectLoadBalancers(ctx, cfg, region, res); err != nil {
+		return err
+	}
+	return c.collectRDS(ctx, cfg, region, res)
+}
+
+func (c *Collector) collectLoadBalancers(ctx context.Context, cfg aws.Config, region string, res *model.CollectResult) error {
+	elbc := elbv2.NewFromConfig(cfg)
+	lbs := elbv2.NewDescribeLoadBalancersPaginator(elbc, &elbv2.DescribeLoadBalancersInput{})
+	for lbs.HasMorePages() {
+		page, err := lbs.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("describe load balancers: %w", err)
+		}
+		for _, lb := range page.LoadBalancers {
+			arn := aws.ToString(lb.LoadBalancerArn)
+			a := &model.Asset{
+				Name:       aws.ToString(lb.LoadBalancerName),
+				AssetType:  "load_balancer",
+				ExternalID: arn,
+				FQDN:       aws.ToString(lb.DNSName),
+				Attributes: map[string]any{
+					"lb_type": string(lb.Type),
+					"scheme":  string(lb.Scheme),
+					"region":  region,
+				},
+			}
+			res.Assets = append(res.Assets, a)
+			if id := aws.ToString(lb.VpcId); id != "" {
+				res.Relationships = append(res.Relationships, model.Relationship{
+					FromExternalID: id,
+					ToExternalID:   arn,
+					Type:           model.RelContains,
+				})
+			}
+			// Registered instance targets become LOAD_BALANCES edges;
+			// IP targets are skipped since they need not be assets.
+			tgs, err := elbc.DescribeTargetGroups(ctx, &elbv2.DescribeTargetGroupsInput{LoadBalancerArn: lb.LoadBalancerArn})
+			if err != nil {
+				return fmt.Errorf("describe target groups: %w", err)
+			}
+			for _, tg := range tgs.TargetGroups {
+				if string(tg.TargetType) != "instance" {
+					continue
+				}
+				health, err := elbc.DescribeTargetHealth(ctx, &elbv2.DescribeTargetHealthInput{TargetGroupArn: tg.TargetGroupArn})
+				if err != nil {
+					return fmt.Errorf("describe target health: %w", err)
+				}
+				for _, th := range health.TargetHealthDescriptions {
+					if th.Target == nil {
+						continue
+					}
+					res.Relationships = append(res.Relationships, model.Relationship{
+						FromExternalID: arn,
+						ToExternalID:   aws.ToString(th.Target.Id),
+						Type:           model.RelLoadBalances,
+						Properties:     map[string]any{"target_group": aws.ToString(tg.TargetGroupName)},
+					})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Collector) collectRDS(ctx context.Context, cfg aws.Config, region string, res *model.CollectResult) error {
+	rdsc := rds.NewFromConfig(cfg)
+	dbs := rds.NewDescribeDBInstancesPaginator(rdsc, &rds.DescribeDBInstancesInput{})
+	for dbs.HasMorePages() {
+		page, err := dbs.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("describe db instances: %w", err)
+		}
+		for _, db := range page.DBInstances {
+			a := &model.Asset{
+				Name:       aws.ToString(db.DBInstanceIdentifier),
+				AssetType:  "database",
+				ExternalID: aws.ToString(db.DBInstanceArn),
+				Attributes: map[string]any{
+					"engine":         aws.ToString(db.Engine),
+					"engine_version": aws.ToString(db.EngineVersion),
+					"instance_class": aws.ToString(db.DBInstanceClass),
+					"status":         aws.ToString(db.DBInstanceStatus),
+					"region":         region,
+				},
+			}
+			if db.Endpoint != nil {
+				a.FQDN = aws.ToString(db.Endpoint.Address)
+			}
+			res.Assets = append(res.Assets, a)
+			if db.DBSubnetGroup != nil {
+				if id := aws.ToString(db.DBSubnetGroup.VpcId); id != "" {
+					res.Relationships = append(res.Relationships, model.Relationship{
+						FromExternalID: id,
+						ToExternalID:   a.ExternalID,
+						Type:           model.RelContains,
+					})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func instanceToAsset(inst ec2types.Instance, region string) *model.Asset {
+	id := aws.ToString(inst.InstanceId)
+	a := &model.Asset{
+		Name:       nameOrID(inst.Tags, id),
+		AssetType:  "vm",
+		ExternalID: id,
+		Attributes: map[string]any{
+			"instance_type": string(inst.InstanceType),
+			"region":        region,
+		},
+	}
+	if inst.State != nil {
+		a.Attributes["state"] = string(inst.State.Name)
+	}
+	if inst.Placement != nil {
+		a.Attributes["availability_zone"] = aws.ToString(inst.Placement.AvailabilityZone)
+	}
+	if ip := aws.ToString(inst.PrivateIpAddress); ip != "" {
+		a.IPs = append(a.IPs, ip)
+	}
+	if ip := aws.ToString(inst.PublicIpAddress); ip != "" {
+		a.IPs = append(a.IPs, ip)
+	}
+	if fqdn := aws.ToString(inst.PrivateDnsName); fqdn != "" {
+		a.FQDN = fqdn
+	}
+	if tags := tagMap(inst.Tags); len(tags) > 0 {
+		a.Attributes["tags"] = tags
+	}
+	return a
+}
+
+// nameOrID prefers the Name tag, the convention AWS consoles follow.
+func nameOrID(tags []ec2types.Tag, id string) string {
+	for _, t := range tags {
+		if aws.ToString(t.Key) == "Name" && aws.ToString(t.Value) != "" {
+			return aws.ToString(t.Value)
+		}
+	}
+	return id
+}
+
+func tagMap(tags []ec2types.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(tags))
+	for _, t := range tags {
+		m[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return m
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "regions", Type: collector.TypeList, Required: true, Description: "AWS regions to enumerate"},
+		{Name: "profile", Type: collector.TypeString, Description: "shared-config profile to authenticate with"},
+		{Name: "assume_role_arn", Type: collector.TypeString, Description: "role to assume before collecting"},
+	}
+}
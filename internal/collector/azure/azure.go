@@ -0,0 +1,279 @@
+// Package azure imports VMs, virtual networks, load balancers and AKS
+// clusters via Azure Resource Graph, which answers one KQL query
+// across every subscription the credential can see instead of walking
+// per-service APIs. Azure resource IDs become external IDs, resource
+// groups become container assets, and NICs (fetched but not emitted)
+// tie VMs to their VNets.
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("azure", New)
+}
+
+// Collector queries Azure Resource Graph.
+type Collector struct {
+	name          string
+	subscriptions []string
+}
+
+// New builds the collector from config. Optional settings:
+// subscriptions (a comma-separated list of subscription IDs; empty
+// queries everything the credential can read). Credentials come from
+// the environment via azidentity's default chain (service principal
+// env vars, workload or managed identity, az CLI).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{name: cfg.Name}
+	for _, s := range strings.Split(cfg.Settings["subscriptions"], ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			c.subscriptions = append(c.subscriptions, s)
+		}
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// resourceQuery projects the fields the mapper needs for the resource
+// kinds we track; NICs ride along only to resolve VM addressing and
+// VNet membership.
+const resourceQuery = `Resources
+| where type in~ (
+    'microsoft.compute/virtualmachines',
+    'microsoft.network/virtualnetworks',
+    'microsoft.network/loadbalancers',
+    'microsoft.containerservice/managedclusters',
+    'microsoft.network/networkinterfaces')
+| project id, name, type, location, resourceGroup, subscriptionId, properties, tags`
+
+// resource is one projected Resource Graph row.
+type resource struct {
+	ID             string
+	Name           string
+	Type           string
+	Location       string
+	ResourceGroup  string
+	SubscriptionID string
+	Properties     map[string]any
+	Tags           map[string]any
+}
+
+// Collect pages through the Resource Graph answer and maps rows to
+// assets. External IDs are lowercased resource IDs; Azure is
+// case-insensitive about them but not case-consistent, and lowering
+// both sides keeps edge endpoints matching.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("azure: credentials: %w", err)
+	}
+	client, err := armresourcegraph.NewClient(cred, nil)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("azure: resource graph client: %w", err)
+	}
+	rows, err := c.queryAll(ctx, client)
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	return buildResult(rows), nil
+}
+
+func (c *Collector) queryAll(ctx context.Context, client *armresourcegraph.Client) ([]resource, error) {
+	var rows []resource
+	var skipToken *string
+	for {
+		req := armresourcegraph.QueryRequest{
+			Query: to.Ptr(resourceQuery),
+			Options: &armresourcegraph.QueryRequestOptions{
+				ResultFormat: to.Ptr(armresourcegraph.ResultFormatObjectArray),
+				SkipToken:    skipToken,
+			},
+		}
+		if len(c.subscriptions) > 0 {
+			req.Subscriptions = to.SliceOfPtrs(c.subscriptions...)
+		}
+		resp, err := client.Resources(ctx, req, nil)
+		if err != nil {
+			return nil, fmt.Errorf("azure: resource graph query: %w", err)
+		}
+		page, ok := resp.Data.([]any)
+		if !ok {
+			return nil, fmt.Errorf("azure: unexpected result shape %T", resp.Data)
+		}
+		for _, raw := range page {
+			m, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			rows = append(rows, rowToResource(m))
+		}
+		if resp.SkipToken == nil || *resp.SkipToken == "" {
+			return rows, nil
+		}
+		skipToken = resp.SkipToken
+	}
+}
+
+func rowToResource(m map[string]any) resource {
+	r := resource{
+		ID:             strings.ToLower(str(m["id"])),
+		Name:           str(m["name"]),
+		Type:           strings.ToLower(str(m["type"])),
+		Location:       str(m["location"]),
+		ResourceGroup:  strings.ToLower(str(m["resourceGroup"])),
+		SubscriptionID: str(m["subscriptionId"]),
+	}
+	r.Properties, _ = m["properties"].(map[string]any)
+	r.Tags, _ = m["tags"].(map[string]any)
+	return r
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// assetTypes maps Azure resource types to our asset vocabulary.
+var assetTypes = map[string]string{
+	"microsoft.compute/virtualmachines":          "vm",
+	"microsoft.network/virtualnetworks":          "network",
+	"microsoft.network/loadbalancers":            "load_balancer",
+	"microsoft.containerservice/managedclusters": "cluster",
+}
+
+func buildResult(rows []resource) model.CollectResult {
+	var res model.CollectResult
+	res.Stats.Fetched = len(rows)
+	// vmID -> asset, filled first so NIC rows can attach addresses.
+	vms := map[string]*model.Asset{}
+	groups := map[string]bool{}
+	var nics []resource
+	for _, r := range rows {
+		if r.Type == "microsoft.network/networkinterfaces" {
+			nics = append(nics, r)
+			res.Stats.Filtered++
+			continue
+		}
+		a := &model.Asset{
+			Name:       r.Name,
+			AssetType:  assetTypes[r.Type],
+			ExternalID: r.ID,
+			Attributes: map[string]any{
+				"location":       r.Location,
+				"resource_group": r.ResourceGroup,
+				"subscription":   r.SubscriptionID,
+			},
+		}
+		if len(r.Tags) > 0 {
+			a.Attributes["tags"] = r.Tags
+		}
+		switch r.Type {
+		case "microsoft.compute/virtualmachines":
+			if size := dig(r.Properties, "hardwareProfile", "vmSize"); size != "" {
+				a.Attributes["vm_size"] = size
+			}
+			vms[r.ID] = a
+		case "microsoft.containerservice/managedclusters":
+			if v := dig(r.Properties, "kubernetesVersion"); v != "" {
+				a.Attributes["kubernetes_version"] = v
+			}
+			if fqdn := dig(r.Properties, "fqdn"); fqdn != "" {
+				a.FQDN = fqdn
+			}
+		}
+		res.Assets = append(res.Assets, a)
+		// Every resource sits in a resource group; the group asset is
+		// synthesized once per (subscription, group).
+		groupID := fmt.Sprintf("/subscriptions/%s/resourcegroups/%s", r.SubscriptionID, r.ResourceGroup)
+		if !groups[groupID] {
+			groups[groupID] = true
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       r.ResourceGroup,
+				AssetType:  "resource_group",
+				ExternalID: groupID,
+				Attributes: map[string]any{"subscription": r.SubscriptionID},
+			})
+		}
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: groupID,
+			ToExternalID:   r.ID,
+			Type:           model.RelContains,
+		})
+	}
+	res.Relationships = append(res.Relationships, applyNICs(nics, vms)...)
+	res.Stats.Emitted = len(res.Assets)
+	return res
+}
+
+// applyNICs attaches private IPs to their VMs and derives VNet
+// membership: a NIC names its owning VM and its subnet, and the subnet
+// ID embeds the VNet ID.
+func applyNICs(nics []resource, vms map[string]*model.Asset) []model.Relationship {
+	var rels []model.Relationship
+	seen := map[string]bool{}
+	for _, nic := range nics {
+		vmID := strings.ToLower(dig(nic.Properties, "virtualMachine", "id"))
+		vm := vms[vmID]
+		ipConfigs, _ := nic.Properties["ipConfigurations"].([]any)
+		for _, raw := range ipConfigs {
+			cfg, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			props, _ := cfg["properties"].(map[string]any)
+			if vm != nil {
+				if ip := dig(props, "privateIPAddress"); ip != "" {
+					vm.IPs = append(vm.IPs, ip)
+				}
+			}
+			subnetID := strings.ToLower(dig(props, "subnet", "id"))
+			vnetID, _, ok := strings.Cut(subnetID, "/subnets/")
+			if !ok || vm == nil || seen[vnetID+vmID] {
+				continue
+			}
+			seen[vnetID+vmID] = true
+			rels = append(rels, model.Relationship{
+				FromExternalID: vnetID,
+				ToExternalID:   vmID,
+				Type:           model.RelContains,
+			})
+		}
+	}
+	return rels
+}
+
+// dig walks nested string-keyed maps and returns the string leaf, or
+// "" anywhere along the way.
+func dig(m map[string]any, path ...string) string {
+	for i, key := range path {
+		if m == nil {
+			return ""
+		}
+		if i == len(path)-1 {
+			return str(m[key])
+		}
+		m, _ = m[key].(map[string]any)
+	}
+	return ""
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "subscriptions", Type: collector.TypeList, Description: "subscription IDs; empty means all visible"},
+	}
+}
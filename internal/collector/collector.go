@@ -0,0 +1,156 @@
+// Package collector defines the collector contract and the scheduler
+// that runs configured collectors on their intervals. Concrete
+// collectors live in subpackages and register a factory in init().
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+// Collector fetches inventory from one source. Collect must be safe to
+// call repeatedly and should honor ctx cancellation.
+type Collector interface {
+	// Name is the configured instance name, used as the asset source.
+	Name() string
+	Collect(ctx context.Context) (model.CollectResult, error)
+	// Schema describes the settings the collector understands, for
+	// validation and for the collectors API.
+	Schema() Schema
+}
+
+// SettingType is the value type of one setting, used to validate its
+// string form.
+type SettingType string
+
+const (
+	// TypeString accepts any value.
+	TypeString SettingType = "string"
+	// TypeInt must parse as an integer.
+	TypeInt SettingType = "int"
+	// TypeBool must be "true" or "false".
+	TypeBool SettingType = "bool"
+	// TypeDuration must parse as a Go duration ("30s", "5m").
+	TypeDuration SettingType = "duration"
+	// TypeList is a comma-separated list; elements are not typed.
+	TypeList SettingType = "list"
+)
+
+// Setting describes one entry of a collector's Settings map.
+type Setting struct {
+	Name string      `json:"name"`
+	Type SettingType `json:"type"`
+	// Required settings must be present and non-empty. Conditional
+	// requirements ("one of X and Y") are left false here and enforced
+	// by the collector's constructor.
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// Schema is the full settings description of one collector type.
+type Schema []Setting
+
+// ValidateSettings checks a settings map against a schema: required
+// entries must be present, every key must be known, and typed values
+// must parse. All problems are reported at once.
+func ValidateSettings(s Schema, settings map[string]string) error {
+	known := make(map[string]Setting, len(s))
+	var problems []string
+	for _, def := range s {
+		known[def.Name] = def
+		if def.Required && settings[def.Name] == "" {
+			problems = append(problems, fmt.Sprintf("missing required setting %q", def.Name))
+		}
+	}
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		def, ok := known[k]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown setting %q", k))
+			continue
+		}
+		v := settings[k]
+		if v == "" {
+			continue
+		}
+		switch def.Type {
+		case TypeInt:
+			if _, err := strconv.Atoi(v); err != nil {
+				problems = append(problems, fmt.Sprintf("setting %q: %q is not an integer", k, v))
+			}
+		case TypeBool:
+			if v != "true" && v != "false" {
+				problems = append(problems, fmt.Sprintf("setting %q: %q is not true or false", k, v))
+			}
+		case TypeDuration:
+			if _, err := time.ParseDuration(v); err != nil {
+				problems = append(problems, fmt.Sprintf("setting %q: %q is not a duration", k, v))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid settings: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Previewer is implemented by file-based collectors that can parse an
+// uploaded payload directly, so configurations can be validated against
+// a sample report without touching the configured path.
+type Previewer interface {
+	Preview(ctx context.Context, payload []byte) (model.CollectResult, error)
+}
+
+// Factory builds a collector instance from its configuration.
+type Factory func(cfg config.CollectorConfig) (Collector, error)
+
+var factories = map[string]Factory{}
+
+// Register makes a collector type available to the scheduler. It is
+// called from init() in each collector subpackage and panics on
+// duplicate registration.
+func Register(typ string, f Factory) {
+	if _, dup := factories[typ]; dup {
+		panic(fmt.Sprintf("collector: duplicate registration of type %q", typ))
+	}
+	factories[typ] = f
+}
+
+// Build instantiates a collector from config and validates its
+// settings against the collector's schema, so typos and type mistakes
+// fail at load instead of mid-run.
+func Build(cfg config.CollectorConfig) (Collector, error) {
+	f, ok := factories[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown collector type %q", cfg.Type)
+	}
+	c, err := f(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateSettings(c.Schema(), cfg.Settings); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Types returns the registered collector type names, sorted.
+func Types() []string {
+	out := make([]string, 0, len(factories))
+	for t := range factories {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,36 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSettings(t *testing.T) {
+	schema := Schema{
+		{Name: "url", Type: TypeString, Required: true},
+		{Name: "timeout", Type: TypeDuration},
+		{Name: "insecure", Type: TypeBool},
+		{Name: "limit", Type: TypeInt},
+	}
+	if err := ValidateSettings(schema, map[string]string{"url": "https://x", "timeout": "30s"}); err != nil {
+		t.Fatalf("valid settings rejected: %v", err)
+	}
+	err := ValidateSettings(schema, map[string]string{
+		"timout":   "30s",
+		"insecure": "yes",
+		"limit":    "ten",
+	})
+	if err == nil {
+		t.Fatal("invalid settings accepted")
+	}
+	for _, want := range []string{
+		`missing required setting "url"`,
+		`unknown setting "timout"`,
+		`"yes" is not true or false`,
+		`"ten" is not an integer`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Package consul imports the Consul service catalog: nodes become
+// host assets, services become service assets with RUNS_ON edges to
+// every node running an instance, and aggregated health-check state
+// lands in the service attributes. When Connect sidecar proxies are
+// registered, their upstream lists become DEPENDS_ON edges between
+// services — the closest thing to a declared dependency graph most
+// estates have.
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("consul", New)
+}
+
+// Collector reads one Consul datacenter.
+type Collector struct {
+	name   string
+	url    string
+	token  string
+	dc     string
+	client *http.Client
+}
+
+// New builds the collector from config. Required setting: url (the
+// HTTP API address, e.g. http://consul.service:8500). Optional: token
+// (an ACL token) and datacenter (defaults to the agent's own).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:   cfg.Name,
+		url:    strings.TrimSuffix(cfg.Settings["url"], "/"),
+		token:  cfg.Settings["token"],
+		dc:     cfg.Settings["datacenter"],
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+	if c.url == "" {
+		return nil, fmt.Errorf("consul: url setting is required")
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) get(ctx context.Context, path string, out any) error {
+	u := c.url + path
+	if c.dc != "" {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		u += sep + "dc=" + url.QueryEscape(c.dc)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("consul: %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("consul: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// serviceEntry is one instance from /v1/health/service/<name>.
+type serviceEntry struct {
+	Node struct {
+		Node    string            `json:"Node"`
+		Address string            `json:"Address"`
+		Meta    map[string]string `json:"Meta"`
+	} `json:"Node"`
+	Service struct {
+		Service string   `json:"Service"`
+		Port    int      `json:"Port"`
+		Tags    []string `json:"Tags"`
+		Proxy   struct {
+			DestinationServiceName string `json:"DestinationServiceName"`
+			Upstreams              []struct {
+				DestinationName string `json:"DestinationName"`
+			} `json:"Upstreams"`
+		} `json:"Proxy"`
+	} `json:"Service"`
+	Checks []struct {
+		Status string `json:"Status"`
+	} `json:"Checks"`
+}
+
+// Collect lists the catalog services and walks each one's health
+// endpoint, which returns node, instance and check state together.
+// External IDs are "node/<name>" and "service/<name>"; Consul names
+// are unique per datacenter.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	var services map[string][]string
+	if err := c.get(ctx, "/v1/catalog/services", &services); err != nil {
+		return model.CollectResult{}, err
+	}
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nodes := map[string]bool{}
+	// seen dedups RUNS_ON edges when a node runs several instances of
+	// the same service.
+	seen := map[string]bool{}
+	for _, name := range names {
+		var entries []serviceEntry
+		if err := c.get(ctx, "/v1/health/service/"+url.PathEscape(name), &entries); err != nil {
+			return model.CollectResult{}, err
+		}
+		res.Stats.Fetched += len(entries)
+
+		// Sidecar proxies carry the dependency data but are not
+		// services in their own right; fold their upstreams onto the
+		// service they front.
+		isProxy := len(entries) > 0 && entries[0].Service.Proxy.DestinationServiceName != ""
+		svcName := name
+		if isProxy {
+			svcName = entries[0].Service.Proxy.DestinationServiceName
+		}
+		svcID := "service/" + svcName
+
+		var passing, total int
+		upstreams := map[string]bool{}
+		for _, e := range entries {
+			total++
+			healthy := true
+			for _, chk := range e.Checks {
+				if chk.Status != "passing" {
+					healthy = false
+				}
+			}
+			if healthy {
+				passing++
+			}
+			for _, up := range e.Service.Proxy.Upstreams {
+				upstreams[up.DestinationName] = true
+			}
+			if e.Node.Node == "" {
+				continue
+			}
+			nodeID := "node/" + e.Node.Node
+			if !nodes[e.Node.Node] {
+				nodes[e.Node.Node] = true
+				node := &model.Asset{
+					Name:       e.Node.Node,
+					AssetType:  "host",
+					ExternalID: nodeID,
+					Attributes: map[string]any{},
+				}
+				if e.Node.Address != "" {
+					node.IPs = []string{e.Node.Address}
+				}
+				if os := e.Node.Meta["os"]; os != "" {
+					node.Attributes["os"] = os
+				}
+				res.Assets = append(res.Assets, node)
+			}
+			if !isProxy && !seen[svcID+"|"+nodeID] {
+				seen[svcID+"|"+nodeID] = true
+				res.Relationships = append(res.Relationships, model.Relationship{
+					FromExternalID: svcID,
+					ToExternalID:   nodeID,
+					Type:           model.RelRunsOn,
+					Properties:     map[string]any{"port": entries[0].Service.Port},
+				})
+			}
+		}
+
+		if isProxy {
+			for up := range upstreams {
+				key := svcID + "->" + up
+				if !seen[key] {
+					seen[key] = true
+					res.Relationships = append(res.Relationships, model.Relationship{
+						FromExternalID: svcID,
+						ToExternalID:   "service/" + up,
+						Type:           model.RelDependsOn,
+					})
+				}
+			}
+			res.Stats.Filtered += len(entries)
+			continue
+		}
+
+		svc := &model.Asset{
+			Name:       svcName,
+			AssetType:  "service",
+			ExternalID: svcID,
+			Attributes: map[string]any{
+				"instances": total,
+				"passing":   passing,
+			},
+		}
+		if len(services[name]) > 0 {
+			svc.Attributes["tags"] = strings.Join(services[name], ",")
+		}
+		res.Assets = append(res.Assets, svc)
+	}
+
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "Consul HTTP API base URL"},
+		{Name: "token", Type: collector.TypeString, Description: "ACL token"},
+		{Name: "datacenter", Type: collector.TypeString, Description: "datacenter to query; empty means the agent's own"},
+	}
+}
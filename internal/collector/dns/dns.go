@@ -0,0 +1,203 @@
+// Package dns imports zones as dns_record assets, either by zone
+// transfer (AXFR) from a configured server or by parsing BIND zone
+// files. Records of one name and type are folded into a single asset
+// whose FQDN and IPs are populated from the record data, so the
+// reconciler's IP/FQDN matching ties them to the hosts other sources
+// reported — a changed or removed record then surfaces as a change
+// event next to the host.
+package dns
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("dns", New)
+}
+
+// Collector transfers or reads one or more zones.
+type Collector struct {
+	name    string
+	zones   []string
+	server  string
+	zoneDir string
+}
+
+// New builds the collector from config. Required settings: zones (a
+// comma-separated list) and exactly one of server (host:port, AXFR
+// mode) or zone_dir (a directory holding "<zone>.zone" files).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:    cfg.Name,
+		server:  cfg.Settings["server"],
+		zoneDir: cfg.Settings["zone_dir"],
+	}
+	for _, z := range strings.Split(cfg.Settings["zones"], ",") {
+		if z = strings.TrimSpace(z); z != "" {
+			c.zones = append(c.zones, dns.Fqdn(z))
+		}
+	}
+	if len(c.zones) == 0 {
+		return nil, fmt.Errorf("dns: zones setting is required")
+	}
+	if (c.server == "") == (c.zoneDir == "") {
+		return nil, fmt.Errorf("dns: exactly one of server and zone_dir is required")
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect walks every configured zone.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	records := map[string]*record{}
+	for _, zone := range c.zones {
+		var err error
+		if c.server != "" {
+			err = c.transferZone(ctx, zone, records)
+		} else {
+			err = c.parseZoneFile(zone, records)
+		}
+		if err != nil {
+			return model.CollectResult{}, err
+		}
+	}
+	return buildResult(records), nil
+}
+
+// record accumulates the values of one (name, type) pair; DNS allows
+// several A records per name and one asset per value would churn.
+type record struct {
+	zone   string
+	name   string
+	rtype  string
+	ttl    uint32
+	values []string
+}
+
+func (c *Collector) transferZone(ctx context.Context, zone string, records map[string]*record) error {
+	t := &dns.Transfer{}
+	msg := &dns.Msg{}
+	msg.SetAxfr(zone)
+	envelopes, err := t.In(msg, c.server)
+	if err != nil {
+		return fmt.Errorf("dns: axfr %s from %s: %w", zone, c.server, err)
+	}
+	for env := range envelopes {
+		if env.Error != nil {
+			return fmt.Errorf("dns: axfr %s: %w", zone, env.Error)
+		}
+		for _, rr := range env.RR {
+			addRR(records, zone, rr)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (c *Collector) parseZoneFile(zone string, records map[string]*record) error {
+	path := filepath.Join(c.zoneDir, strings.TrimSuffix(zone, ".")+".zone")
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("dns: zone file: %w", err)
+	}
+	defer f.Close()
+	zp := dns.NewZoneParser(f, zone, path)
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		addRR(records, zone, rr)
+	}
+	if err := zp.Err(); err != nil {
+		return fmt.Errorf("dns: parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// addRR folds one resource record in; only record types that identify
+// hosts are kept (A, AAAA, CNAME).
+func addRR(records map[string]*record, zone string, rr dns.RR) {
+	hdr := rr.Header()
+	var value string
+	switch v := rr.(type) {
+	case *dns.A:
+		value = v.A.String()
+	case *dns.AAAA:
+		value = v.AAAA.String()
+	case *dns.CNAME:
+		value = strings.TrimSuffix(v.Target, ".")
+	default:
+		return
+	}
+	name := strings.TrimSuffix(hdr.Name, ".")
+	rtype := dns.TypeToString[hdr.Rrtype]
+	key := name + "/" + rtype
+	rec := records[key]
+	if rec == nil {
+		rec = &record{zone: strings.TrimSuffix(zone, "."), name: name, rtype: rtype, ttl: hdr.Ttl}
+		records[key] = rec
+	}
+	rec.values = append(rec.values, value)
+}
+
+func buildResult(records map[string]*record) model.CollectResult {
+	var res model.CollectResult
+	for key, rec := range records {
+		sort.Strings(rec.values)
+		a := &model.Asset{
+			Name:       rec.name,
+			FQDN:       rec.name,
+			AssetType:  "dns_record",
+			ExternalID: key,
+			Attributes: map[string]any{
+				"zone":        rec.zone,
+				"record_type": rec.rtype,
+				"ttl":         int(rec.ttl),
+				"values":      rec.values,
+			},
+		}
+		switch rec.rtype {
+		case "A", "AAAA":
+			a.IPs = rec.values
+		case "CNAME":
+			a.Attributes["target"] = rec.values[0]
+		}
+		res.Assets = append(res.Assets, a)
+		// A CNAME whose target lives in one of the collected zones is
+		// also linked explicitly.
+		if rec.rtype == "CNAME" {
+			if _, ok := records[rec.values[0]+"/A"]; ok {
+				res.Relationships = append(res.Relationships, model.Relationship{
+					FromExternalID: key,
+					ToExternalID:   rec.values[0] + "/A",
+					Type:           model.RelConnectsTo,
+					Properties:     map[string]any{"via": "cname"},
+				})
+			}
+		}
+	}
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "zones", Type: collector.TypeList, Required: true, Description: "zones to import"},
+		{Name: "server", Type: collector.TypeString, Description: "DNS server for zone transfers (one of server and zone_dir)"},
+		{Name: "zone_dir", Type: collector.TypeString, Description: "directory of zone files (one of server and zone_dir)"},
+	}
+}
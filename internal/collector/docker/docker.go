@@ -0,0 +1,276 @@
+// Package docker imports containers, images, networks and volumes from
+// a standalone Docker host via the Engine REST API, reached over the
+// local unix socket or TCP with mutual TLS. Each run also emits the
+// host itself, and containers are linked to it with RUNS_ON edges —
+// this is the inventory path for hosts that are not in Kubernetes.
+package docker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("docker", New)
+}
+
+// Collector talks to one Docker engine.
+type Collector struct {
+	name   string
+	client *http.Client
+	// base is the URL prefix requests are made against; for unix
+	// sockets it is a placeholder host the transport ignores.
+	base string
+}
+
+// New builds the collector from config. Optional settings: host
+// ("unix:///var/run/docker.sock", the default, or "tcp://host:2376"),
+// and for TCP with TLS: tls_cert, tls_key, tls_ca (paths, all three
+// required together, matching dockerd's --tlsverify layout).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	host := cfg.Settings["host"]
+	if host == "" {
+		host = "unix:///var/run/docker.sock"
+	}
+	c := &Collector{name: cfg.Name}
+	switch {
+	case strings.HasPrefix(host, "unix://"):
+		path := strings.TrimPrefix(host, "unix://")
+		c.base = "http://docker"
+		c.client = &http.Client{
+			Timeout: 60 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", path)
+				},
+			},
+		}
+	case strings.HasPrefix(host, "tcp://"):
+		addr := strings.TrimPrefix(host, "tcp://")
+		transport := &http.Transport{}
+		scheme := "http"
+		if cfg.Settings["tls_cert"] != "" || cfg.Settings["tls_key"] != "" || cfg.Settings["tls_ca"] != "" {
+			tlsCfg, err := loadTLS(cfg.Settings["tls_cert"], cfg.Settings["tls_key"], cfg.Settings["tls_ca"])
+			if err != nil {
+				return nil, fmt.Errorf("docker: %w", err)
+			}
+			transport.TLSClientConfig = tlsCfg
+			scheme = "https"
+		}
+		c.base = scheme + "://" + addr
+		c.client = &http.Client{Timeout: 60 * time.Second, Transport: transport}
+	default:
+		return nil, fmt.Errorf("docker: unsupported host %q (want unix:// or tcp://)", host)
+	}
+	return c, nil
+}
+
+func loadTLS(certPath, keyPath, caPath string) (*tls.Config, error) {
+	if certPath == "" || keyPath == "" || caPath == "" {
+		return nil, fmt.Errorf("tls_cert, tls_key and tls_ca must all be set")
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load client cert: %w", err)
+	}
+	ca, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("read ca: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("ca file %s contains no certificates", caPath)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, RootCAs: pool}, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("docker: %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("docker: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+type dockerInfo struct {
+	ID            string `json:"ID"`
+	Name          string `json:"Name"`
+	ServerVersion string `json:"ServerVersion"`
+	OS            string `json:"OperatingSystem"`
+	NCPU          int    `json:"NCPU"`
+	MemTotal      int64  `json:"MemTotal"`
+}
+
+type dockerContainer struct {
+	ID              string            `json:"Id"`
+	Names           []string          `json:"Names"`
+	Image           string            `json:"Image"`
+	State           string            `json:"State"`
+	Status          string            `json:"Status"`
+	Labels          map[string]string `json:"Labels"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string `json:"IPAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+type dockerImage struct {
+	ID       string   `json:"Id"`
+	RepoTags []string `json:"RepoTags"`
+	Size     int64    `json:"Size"`
+}
+
+type dockerNetwork struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Driver string `json:"Driver"`
+	Scope  string `json:"Scope"`
+}
+
+type dockerVolume struct {
+	Name   string `json:"Name"`
+	Driver string `json:"Driver"`
+}
+
+// Collect enumerates the engine. External IDs are prefixed with the
+// engine ID so containers on different hosts never collide; the host
+// itself uses the bare engine ID.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var info dockerInfo
+	if err := c.get(ctx, "/info", &info); err != nil {
+		return model.CollectResult{}, err
+	}
+	var res model.CollectResult
+	res.Assets = append(res.Assets, &model.Asset{
+		Name:       info.Name,
+		AssetType:  "host",
+		ExternalID: info.ID,
+		Attributes: map[string]any{
+			"docker_version": info.ServerVersion,
+			"os":             info.OS,
+			"cpu_count":      info.NCPU,
+			"memory_bytes":   info.MemTotal,
+		},
+	})
+	extID := func(kind, id string) string { return info.ID + "/" + kind + "/" + id }
+
+	var containers []dockerContainer
+	if err := c.get(ctx, "/containers/json?all=1", &containers); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, ct := range containers {
+		name := strings.TrimPrefix(ct.ID[:12], "/")
+		if len(ct.Names) > 0 {
+			name = strings.TrimPrefix(ct.Names[0], "/")
+		}
+		a := &model.Asset{
+			Name:       name,
+			AssetType:  "container",
+			ExternalID: extID("container", ct.ID),
+			Attributes: map[string]any{
+				"image":  ct.Image,
+				"state":  ct.State,
+				"status": ct.Status,
+			},
+		}
+		if len(ct.Labels) > 0 {
+			a.Attributes["labels"] = ct.Labels
+		}
+		for _, n := range ct.NetworkSettings.Networks {
+			if n.IPAddress != "" {
+				a.IPs = append(a.IPs, n.IPAddress)
+			}
+		}
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: a.ExternalID,
+			ToExternalID:   info.ID,
+			Type:           model.RelRunsOn,
+		})
+	}
+
+	var images []dockerImage
+	if err := c.get(ctx, "/images/json", &images); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, img := range images {
+		name := img.ID
+		if len(img.RepoTags) > 0 && img.RepoTags[0] != "<none>:<none>" {
+			name = img.RepoTags[0]
+		}
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       name,
+			AssetType:  "container_image",
+			ExternalID: extID("image", img.ID),
+			Attributes: map[string]any{"size_bytes": img.Size, "tags": img.RepoTags},
+		})
+	}
+
+	var networks []dockerNetwork
+	if err := c.get(ctx, "/networks", &networks); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, n := range networks {
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       n.Name,
+			AssetType:  "network",
+			ExternalID: extID("network", n.ID),
+			Attributes: map[string]any{"driver": n.Driver, "scope": n.Scope},
+		})
+	}
+
+	var volumes struct {
+		Volumes []dockerVolume `json:"Volumes"`
+	}
+	if err := c.get(ctx, "/volumes", &volumes); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, v := range volumes.Volumes {
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       v.Name,
+			AssetType:  "volume",
+			ExternalID: extID("volume", v.Name),
+			Attributes: map[string]any{"driver": v.Driver},
+		})
+	}
+
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "host", Type: collector.TypeString, Description: "daemon address; empty means the local socket"},
+		{Name: "tls_ca", Type: collector.TypeString, Description: "CA bundle for TLS-protected daemons"},
+	}
+}
@@ -0,0 +1,204 @@
+// Package elasticsearch imports an Elasticsearch or OpenSearch
+// cluster: the cluster, its nodes and its indices become assets with
+// CONTAINS edges, carrying shard and size attributes. The _cat APIs
+// are used because both products serve them identically, so one
+// collector covers either. System indices (dot-prefixed) are skipped.
+package elasticsearch
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("elasticsearch", New)
+}
+
+// Collector reads one cluster.
+type Collector struct {
+	name     string
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+// New builds the collector from config. Required setting: url.
+// Optional: username, password (basic auth) and insecure ("true"
+// skips TLS verification for self-signed cluster certificates).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		url:      strings.TrimSuffix(cfg.Settings["url"], "/"),
+		username: cfg.Settings["username"],
+		password: cfg.Settings["password"],
+	}
+	if c.url == "" {
+		return nil, fmt.Errorf("elasticsearch: url setting is required")
+	}
+	transport := &http.Transport{}
+	if cfg.Settings["insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c.client = &http.Client{Timeout: 60 * time.Second, Transport: transport}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("elasticsearch: %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("elasticsearch: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Collect reads the root endpoint for the cluster identity, then
+// _cat/nodes and _cat/indices. External IDs are prefixed with the
+// cluster name, so several clusters can share one collector source
+// name without colliding.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	var root struct {
+		ClusterName string `json:"cluster_name"`
+		Version     struct {
+			Number       string `json:"number"`
+			Distribution string `json:"distribution"`
+		} `json:"version"`
+	}
+	if err := c.get(ctx, "/", &root); err != nil {
+		return model.CollectResult{}, err
+	}
+	cluster := root.ClusterName
+	if cluster == "" {
+		cluster = c.name
+	}
+	distribution := root.Version.Distribution
+	if distribution == "" {
+		distribution = "elasticsearch"
+	}
+	clusterID := cluster + "/cluster"
+	res.Assets = append(res.Assets, &model.Asset{
+		Name:       cluster,
+		AssetType:  "search_cluster",
+		ExternalID: clusterID,
+		Attributes: map[string]any{
+			"distribution": distribution,
+			"version":      root.Version.Number,
+		},
+	})
+
+	var nodes []struct {
+		Name    string `json:"name"`
+		IP      string `json:"ip"`
+		Version string `json:"version"`
+		Role    string `json:"node.role"`
+		Master  string `json:"master"`
+	}
+	if err := c.get(ctx, "/_cat/nodes?format=json&h=name,ip,version,node.role,master", &nodes); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, n := range nodes {
+		id := cluster + "/node/" + n.Name
+		a := &model.Asset{
+			Name:       n.Name,
+			AssetType:  "search_node",
+			ExternalID: id,
+			Attributes: map[string]any{
+				"version": n.Version,
+				"roles":   n.Role,
+				"master":  n.Master == "*",
+			},
+		}
+		if net.ParseIP(n.IP) != nil {
+			a.IPs = []string{n.IP}
+		}
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: clusterID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	var indices []struct {
+		Index     string `json:"index"`
+		Health    string `json:"health"`
+		Status    string `json:"status"`
+		Pri       string `json:"pri"`
+		Rep       string `json:"rep"`
+		DocsCount string `json:"docs.count"`
+		StoreSize string `json:"store.size"`
+	}
+	if err := c.get(ctx, "/_cat/indices?format=json&h=index,health,status,pri,rep,docs.count,store.size", &indices); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, idx := range indices {
+		res.Stats.Fetched++
+		if strings.HasPrefix(idx.Index, ".") {
+			res.Stats.Filtered++
+			continue
+		}
+		id := cluster + "/index/" + idx.Index
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       idx.Index,
+			AssetType:  "search_index",
+			ExternalID: id,
+			Attributes: map[string]any{
+				"health":         idx.Health,
+				"status":         idx.Status,
+				"primary_shards": idx.Pri,
+				"replicas":       idx.Rep,
+				"docs":           idx.DocsCount,
+				"size":           idx.StoreSize,
+			},
+		})
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: clusterID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	res.Stats.Fetched += len(nodes) + 1
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "cluster base URL"},
+		{Name: "username", Type: collector.TypeString, Description: "basic-auth user"},
+		{Name: "password", Type: collector.TypeString, Description: "basic-auth password"},
+		{Name: "insecure", Type: collector.TypeBool, Description: "skip TLS verification"},
+	}
+}
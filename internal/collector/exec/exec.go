@@ -0,0 +1,158 @@
+// Package exec runs external collector plugins, so teams can feed
+// custom sources into the inventory without forking the repo. A plugin
+// is any program that emits a CollectResult as JSON: one-shot plugins
+// are executed per run and write the payload to stdout; long-running
+// plugins serve it over gRPC and the collector dials them. Both speak
+// the same versioned payload (see payload), so a plugin can start as a
+// shell script and graduate to a service without changing shape.
+package exec
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("exec", New)
+}
+
+// protocolVersion is the plugin payload version this build speaks.
+// Plugins must echo it back so a stale plugin fails loudly instead of
+// feeding subtly wrong data.
+const protocolVersion = 1
+
+// payload is the JSON a plugin produces: a CollectResult plus the
+// protocol handshake.
+type payload struct {
+	ProtocolVersion int                  `json:"protocol_version"`
+	Assets          []*model.Asset       `json:"assets"`
+	Relationships   []model.Relationship `json:"relationships,omitempty"`
+	Stats           model.CollectStats   `json:"stats"`
+	Partial         bool                 `json:"partial,omitempty"`
+}
+
+// result validates the handshake and maps the payload to a
+// CollectResult, filling in stats a minimal plugin didn't bother with.
+func (p *payload) result() (model.CollectResult, error) {
+	if p.ProtocolVersion != protocolVersion {
+		return model.CollectResult{}, fmt.Errorf("exec: plugin speaks protocol version %d, want %d", p.ProtocolVersion, protocolVersion)
+	}
+	res := model.CollectResult{
+		Assets:        p.Assets,
+		Relationships: p.Relationships,
+		Stats:         p.Stats,
+		Partial:       p.Partial,
+	}
+	if res.Stats.Fetched == 0 && res.Stats.Emitted == 0 {
+		res.Stats.Fetched = len(res.Assets)
+		res.Stats.Emitted = len(res.Assets)
+	}
+	return res, nil
+}
+
+// Collector runs one plugin.
+type Collector struct {
+	name    string
+	command string
+	args    []string
+	timeout time.Duration
+	address string
+
+	grpc *grpcClient
+}
+
+// New builds the collector from config. The mode setting selects the
+// plugin style: "oneshot" (the default) requires command (the binary
+// or script to run; optional args are split on whitespace, optional
+// timeout defaults to 5m), "grpc" requires address (a running plugin
+// server, "host:port" or "unix:///path").
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:    cfg.Name,
+		command: cfg.Settings["command"],
+		args:    strings.Fields(cfg.Settings["args"]),
+		timeout: 5 * time.Minute,
+		address: cfg.Settings["address"],
+	}
+	mode := cfg.Settings["mode"]
+	switch mode {
+	case "", "oneshot":
+		if c.command == "" {
+			return nil, fmt.Errorf("exec: command setting is required")
+		}
+		c.address = ""
+	case "grpc":
+		if c.address == "" {
+			return nil, fmt.Errorf("exec: address setting is required for grpc mode")
+		}
+	default:
+		return nil, fmt.Errorf("exec: unknown mode %q (want oneshot or grpc)", mode)
+	}
+	if raw := cfg.Settings["timeout"]; raw != "" {
+		t, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("exec: invalid timeout %q: %w", raw, err)
+		}
+		c.timeout = t
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect runs the plugin and parses its payload.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	if c.address != "" {
+		return c.collectGRPC(ctx)
+	}
+	return c.collectOneshot(ctx)
+}
+
+// collectOneshot executes the plugin and reads the payload from its
+// stdout. The collector name and protocol version are passed in the
+// environment so one plugin binary can serve several configured
+// instances.
+func (c *Collector) collectOneshot(ctx context.Context) (model.CollectResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	cmd := osexec.CommandContext(ctx, c.command, c.args...)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("ARCH_PLUGIN_PROTOCOL=%d", protocolVersion),
+		"ARCH_COLLECTOR_NAME="+c.name,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return model.CollectResult{}, fmt.Errorf("exec: plugin failed: %s", msg)
+		}
+		return model.CollectResult{}, fmt.Errorf("exec: plugin failed: %w", err)
+	}
+	var p payload
+	if err := jsonUnmarshal(out, &p); err != nil {
+		return model.CollectResult{}, fmt.Errorf("exec: decode plugin output: %w", err)
+	}
+	return p.result()
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "mode", Type: collector.TypeString, Description: "\"oneshot\" (default) or \"grpc\""},
+		{Name: "command", Type: collector.TypeString, Description: "plugin to run (required in oneshot mode)"},
+		{Name: "args", Type: collector.TypeString, Description: "extra plugin arguments, split on whitespace"},
+		{Name: "timeout", Type: collector.TypeDuration, Description: "oneshot run timeout (default 5m)"},
+		{Name: "address", Type: collector.TypeString, Description: "plugin server address (required in grpc mode)"},
+	}
+}
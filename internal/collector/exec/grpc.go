@@ -0,0 +1,80 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// The gRPC variant uses a JSON codec over a single full method, so
+// plugins don't need our proto files or generated stubs — any gRPC
+// server that registers arch.plugin.v1.Collector/Collect and speaks
+// the JSON payload works, in whatever language.
+const collectMethod = "/arch.plugin.v1.Collector/Collect"
+
+// codecName is the content-subtype both sides must use.
+const codecName = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec marshals gRPC messages as plain JSON.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return codecName }
+
+// jsonUnmarshal is the oneshot path's decoder, aliased here so both
+// plugin styles visibly share one wire format.
+func jsonUnmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// collectRequest is what the plugin receives per run.
+type collectRequest struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	Collector       string `json:"collector"`
+}
+
+// grpcClient holds the lazily-dialled connection to a long-running
+// plugin, reused across runs.
+type grpcClient struct {
+	mu   sync.Mutex
+	conn *grpc.ClientConn
+}
+
+// collectGRPC calls the plugin's Collect method, dialling on first
+// use.
+func (c *Collector) collectGRPC(ctx context.Context) (model.CollectResult, error) {
+	if c.grpc == nil {
+		c.grpc = &grpcClient{}
+	}
+	c.grpc.mu.Lock()
+	if c.grpc.conn == nil {
+		conn, err := grpc.Dial(c.address,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)),
+		)
+		if err != nil {
+			c.grpc.mu.Unlock()
+			return model.CollectResult{}, fmt.Errorf("exec: dial plugin: %w", err)
+		}
+		c.grpc.conn = conn
+	}
+	conn := c.grpc.conn
+	c.grpc.mu.Unlock()
+
+	req := collectRequest{ProtocolVersion: protocolVersion, Collector: c.name}
+	var p payload
+	if err := conn.Invoke(ctx, collectMethod, &req, &p); err != nil {
+		return model.CollectResult{}, fmt.Errorf("exec: plugin collect: %w", err)
+	}
+	return p.result()
+}
@@ -0,0 +1,112 @@
+package extdns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+const cloudflareBase = "https://api.cloudflare.com/client/v4"
+
+// cfPage runs one paginated v4 API listing, invoking handle per page
+// until the result_info says the pages are exhausted.
+func (c *Collector) cfPage(ctx context.Context, path string, handle func(json.RawMessage) error) error {
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s%s?per_page=100&page=%d", cloudflareBase, path, page)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("extdns: cloudflare request: %w", err)
+		}
+		var body struct {
+			Success    bool              `json:"success"`
+			Result     []json.RawMessage `json:"result"`
+			ResultInfo struct {
+				Page       int `json:"page"`
+				TotalPages int `json:"total_pages"`
+			} `json:"result_info"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("extdns: cloudflare %s returned status %d", path, resp.StatusCode)
+		}
+		if err != nil {
+			return fmt.Errorf("extdns: decode cloudflare %s: %w", path, err)
+		}
+		if !body.Success {
+			return fmt.Errorf("extdns: cloudflare %s reported failure", path)
+		}
+		for _, raw := range body.Result {
+			if err := handle(raw); err != nil {
+				return err
+			}
+		}
+		if body.ResultInfo.Page >= body.ResultInfo.TotalPages {
+			return nil
+		}
+	}
+}
+
+// collectCloudflare lists every zone the token can see, then each
+// zone's records.
+func (c *Collector) collectCloudflare(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	type zone struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	var zones []zone
+	err := c.cfPage(ctx, "/zones", func(raw json.RawMessage) error {
+		var z zone
+		if err := json.Unmarshal(raw, &z); err != nil {
+			return err
+		}
+		zones = append(zones, z)
+		return nil
+	})
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+
+	for _, z := range zones {
+		err := c.cfPage(ctx, "/zones/"+z.ID+"/dns_records", func(raw json.RawMessage) error {
+			var rec struct {
+				Type    string `json:"type"`
+				Name    string `json:"name"`
+				Content string `json:"content"`
+				Proxied bool   `json:"proxied"`
+				TTL     int    `json:"ttl"`
+			}
+			if err := json.Unmarshal(raw, &rec); err != nil {
+				return err
+			}
+			res.Stats.Fetched++
+			switch rec.Type {
+			case "A", "AAAA", "CNAME":
+			default:
+				res.Stats.Filtered++
+				return nil
+			}
+			attrs := map[string]any{"zone": z.Name}
+			if rec.Proxied {
+				attrs["proxied"] = true
+			}
+			emitRecord(&res, "cloudflare", rec.Name, rec.Type, []string{rec.Content}, attrs)
+			return nil
+		})
+		if err != nil {
+			return model.CollectResult{}, err
+		}
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
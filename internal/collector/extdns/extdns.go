@@ -0,0 +1,115 @@
+// Package extdns imports DNS records from a public DNS provider —
+// Cloudflare or Route53 — so the externally visible names pointing at
+// the estate show up in the graph. Records carry their target IPs,
+// letting the reconciler's fuzzy matcher link them to the internal
+// hosts they expose; a record nothing internal matches is exactly the
+// kind of forgotten external surface this collector exists to
+// surface. Cloudflare's proxied records are flagged, since their
+// public address is Cloudflare's and the origin IP is the record
+// content.
+package extdns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("extdns", New)
+}
+
+// Collector reads one provider account through a backend-specific
+// API client.
+type Collector struct {
+	name    string
+	backend string
+	// Cloudflare uses an API token; Route53 the shared AWS config.
+	apiToken string
+	profile  string
+	client   *http.Client
+}
+
+// New builds the collector from config. Required setting: backend
+// ("cloudflare" or "route53"). Cloudflare needs api_token (a token
+// with Zone.DNS read). Route53 optionally takes profile, matching the
+// aws collector's credential settings.
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		backend:  cfg.Settings["backend"],
+		apiToken: cfg.Settings["api_token"],
+		profile:  cfg.Settings["profile"],
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+	switch c.backend {
+	case "cloudflare":
+		if c.apiToken == "" {
+			return nil, fmt.Errorf("extdns: cloudflare backend requires the api_token setting")
+		}
+	case "route53":
+	default:
+		return nil, fmt.Errorf("extdns: backend setting must be \"cloudflare\" or \"route53\", got %q", c.backend)
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect dispatches to the configured backend.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	switch c.backend {
+	case "cloudflare":
+		return c.collectCloudflare(ctx)
+	default:
+		return c.collectRoute53(ctx)
+	}
+}
+
+// emitRecord appends one dns_record asset in the same shape the
+// internal dns collector produces, so internal and external views of
+// a name reconcile against hosts the same way. A and AAAA content
+// becomes IPs; CNAME targets stay an attribute.
+func emitRecord(res *model.CollectResult, provider, fqdn, rtype string, values []string, attrs map[string]any) {
+	fqdn = strings.TrimSuffix(strings.ToLower(fqdn), ".")
+	a := &model.Asset{
+		Name:       fqdn,
+		AssetType:  "dns_record",
+		ExternalID: fqdn + "/" + rtype,
+		FQDN:       fqdn,
+		Attributes: map[string]any{"type": rtype, "provider": provider},
+	}
+	for k, v := range attrs {
+		a.Attributes[k] = v
+	}
+	switch rtype {
+	case "A", "AAAA":
+		for _, v := range values {
+			if net.ParseIP(v) != nil {
+				a.IPs = append(a.IPs, v)
+			}
+		}
+	case "CNAME":
+		if len(values) > 0 {
+			a.Attributes["target"] = strings.TrimSuffix(values[0], ".")
+		}
+	}
+	res.Assets = append(res.Assets, a)
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "backend", Type: collector.TypeString, Required: true, Description: "\"cloudflare\" or \"route53\""},
+		{Name: "api_token", Type: collector.TypeString, Description: "Cloudflare API token (cloudflare backend)"},
+		{Name: "profile", Type: collector.TypeString, Description: "AWS shared-config profile (route53 backend)"},
+	}
+}
@@ -0,0 +1,84 @@
+package extdns
+
+import (
+	"context"
+	"fmt"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// collectRoute53 walks every public hosted zone's record sets.
+// Private zones are skipped — internal names are the dns collector's
+// territory; this source is about what the internet can resolve.
+func (c *Collector) collectRoute53(ctx context.Context) (model.CollectResult, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if c.profile != "" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(c.profile))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("extdns: load credentials: %w", err)
+	}
+	client := route53.NewFromConfig(cfg)
+
+	var res model.CollectResult
+	zones := route53.NewListHostedZonesPaginator(client, &route53.ListHostedZonesInput{})
+	for zones.HasMorePages() {
+		page, err := zones.NextPage(ctx)
+		if err != nil {
+			return model.CollectResult{}, fmt.Errorf("extdns: list hosted zones: %w", err)
+		}
+		for _, z := range page.HostedZones {
+			if z.Config != nil && z.Config.PrivateZone {
+				continue
+			}
+			if err := c.collectZone(ctx, client, z, &res); err != nil {
+				return model.CollectResult{}, err
+			}
+		}
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func (c *Collector) collectZone(ctx context.Context, client *route53.Client, z r53types.HostedZone, res *model.CollectResult) error {
+	input := &route53.ListResourceRecordSetsInput{HostedZoneId: z.Id}
+	for {
+		page, err := client.ListResourceRecordSets(ctx, input)
+		if err != nil {
+			return fmt.Errorf("extdns: list records for %s: %w", *z.Name, err)
+		}
+		for _, rs := range page.ResourceRecordSets {
+			res.Stats.Fetched++
+			rtype := string(rs.Type)
+			switch rtype {
+			case "A", "AAAA", "CNAME":
+			default:
+				res.Stats.Filtered++
+				continue
+			}
+			attrs := map[string]any{"zone": *z.Name}
+			var values []string
+			for _, rr := range rs.ResourceRecords {
+				values = append(values, *rr.Value)
+			}
+			// Alias records have no record values; keep the alias
+			// target (typically an ELB or CloudFront name) as an
+			// attribute instead.
+			if rs.AliasTarget != nil {
+				attrs["alias_target"] = *rs.AliasTarget.DNSName
+			}
+			emitRecord(res, "route53", *rs.Name, rtype, values, attrs)
+		}
+		if !page.IsTruncated {
+			return nil
+		}
+		input.StartRecordName = page.NextRecordName
+		input.StartRecordType = page.NextRecordType
+		input.StartRecordIdentifier = page.NextRecordIdentifier
+	}
+}
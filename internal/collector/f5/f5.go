@@ -0,0 +1,237 @@
+// Package f5 imports F5 BIG-IP configuration via the iControl REST
+// API: virtual servers, pools, pool members and client-SSL profiles.
+// The topology mirrors the netscaler collector's: virtual servers
+// LOAD_BALANCE their pools and members are MEMBER_OF the pools that
+// reference them.
+package f5
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("f5", New)
+}
+
+// Collector connects to one BIG-IP device.
+type Collector struct {
+	name     string
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+// New builds the collector from config. Required settings: url,
+// username, password. Optional: insecure ("true" skips TLS
+// verification).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		url:      cfg.Settings["url"],
+		username: cfg.Settings["username"],
+		password: cfg.Settings["password"],
+	}
+	if c.url == "" || c.username == "" || c.password == "" {
+		return nil, fmt.Errorf("f5: url, username and password settings are required")
+	}
+	transport := &http.Transport{}
+	if cfg.Settings["insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c.client = &http.Client{Timeout: 60 * time.Second, Transport: transport}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("f5: %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("f5: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+type virtualServer struct {
+	FullPath    string `json:"fullPath"`
+	Name        string `json:"name"`
+	Destination string `json:"destination"`
+	Pool        string `json:"pool"`
+	Enabled     bool   `json:"enabled"`
+}
+
+type pool struct {
+	FullPath         string `json:"fullPath"`
+	Name             string `json:"name"`
+	Monitor          string `json:"monitor"`
+	MembersReference struct {
+		Items []poolMember `json:"items"`
+	} `json:"membersReference"`
+}
+
+type poolMember struct {
+	Name    string `json:"name"` // "node:port"
+	Address string `json:"address"`
+	State   string `json:"state"`
+	Session string `json:"session"`
+}
+
+type sslProfile struct {
+	FullPath string `json:"fullPath"`
+	Name     string `json:"name"`
+	Cert     string `json:"cert"`
+	Key      string `json:"key"`
+}
+
+// Collect pulls the LTM objects. External IDs are the objects' full
+// paths ("/Common/web-pool"), which are unique per device and what
+// tmsh shows; nodes use "node/<address>".
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	var virtuals struct {
+		Items []virtualServer `json:"items"`
+	}
+	if err := c.get(ctx, "/mgmt/tm/ltm/virtual", &virtuals); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, vs := range virtuals.Items {
+		a := &model.Asset{
+			Name:       vs.Name,
+			AssetType:  "lb_vserver",
+			ExternalID: vs.FullPath,
+			Attributes: map[string]any{
+				"destination": vs.Destination,
+				"enabled":     vs.Enabled,
+			},
+		}
+		if ip := destinationIP(vs.Destination); ip != "" {
+			a.IPs = []string{ip}
+		}
+		res.Assets = append(res.Assets, a)
+		if vs.Pool != "" {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: vs.FullPath,
+				ToExternalID:   vs.Pool,
+				Type:           model.RelLoadBalances,
+			})
+		}
+	}
+
+	var pools struct {
+		Items []pool `json:"items"`
+	}
+	if err := c.get(ctx, "/mgmt/tm/ltm/pool?expandSubcollections=true", &pools); err != nil {
+		return model.CollectResult{}, err
+	}
+	nodes := map[string]bool{}
+	for _, p := range pools.Items {
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       p.Name,
+			AssetType:  "lb_pool",
+			ExternalID: p.FullPath,
+			Attributes: map[string]any{"monitor": strings.TrimSpace(p.Monitor)},
+		})
+		for _, m := range p.MembersReference.Items {
+			nodeID := "node/" + m.Address
+			if !nodes[nodeID] {
+				nodes[nodeID] = true
+				res.Assets = append(res.Assets, &model.Asset{
+					Name:       memberNode(m.Name),
+					AssetType:  "host",
+					ExternalID: nodeID,
+					IPs:        []string{m.Address},
+					Attributes: map[string]any{},
+				})
+			}
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: nodeID,
+				ToExternalID:   p.FullPath,
+				Type:           model.RelMemberOf,
+				Properties: map[string]any{
+					"port":    memberPort(m.Name),
+					"state":   m.State,
+					"session": m.Session,
+				},
+			})
+		}
+	}
+
+	var profiles struct {
+		Items []sslProfile `json:"items"`
+	}
+	if err := c.get(ctx, "/mgmt/tm/ltm/profile/client-ssl", &profiles); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, p := range profiles.Items {
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       p.Name,
+			AssetType:  "ssl_profile",
+			ExternalID: p.FullPath,
+			Attributes: map[string]any{"cert": p.Cert, "key": p.Key},
+		})
+	}
+
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// destinationIP extracts the address from a virtual server destination
+// like "/Common/10.0.0.5:443"; "any" and wildcard destinations yield
+// "".
+func destinationIP(dest string) string {
+	if i := strings.LastIndexByte(dest, '/'); i >= 0 {
+		dest = dest[i+1:]
+	}
+	ip, _, ok := strings.Cut(dest, ":")
+	if !ok || ip == "" || ip == "any" || strings.HasPrefix(ip, "0.0.0.0") {
+		return ""
+	}
+	return ip
+}
+
+// memberNode strips the port from a member name ("web1:80" -> "web1").
+func memberNode(name string) string {
+	node, _, _ := strings.Cut(name, ":")
+	return node
+}
+
+func memberPort(name string) string {
+	_, port, _ := strings.Cut(name, ":")
+	return port
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "iControl REST base URL"},
+		{Name: "username", Type: collector.TypeString, Required: true, Description: "API user"},
+		{Name: "password", Type: collector.TypeString, Required: true, Description: "API password"},
+		{Name: "insecure", Type: collector.TypeBool, Description: "skip TLS verification"},
+	}
+}
@@ -0,0 +1,136 @@
+// Package firewall imports perimeter devices — FortiGate via the
+// FortiOS REST API, or Palo Alto via the PAN-OS XML API. The device,
+// its interfaces and its address objects become assets, and each
+// VIP or destination-NAT rule becomes a CONNECTS_TO edge from the
+// externally published service to the internal address it forwards
+// to, so the graph answers "what does this public IP actually hit?".
+// The internal side is emitted as a thin host carrying only the
+// mapped IP; richer sources fill it in through the reconciler.
+package firewall
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("firewall", New)
+}
+
+// Collector reads one firewall through a backend-specific API client.
+type Collector struct {
+	name    string
+	backend string
+	url     string
+	// FortiOS uses a bearer token; PAN-OS an API key.
+	token  string
+	apiKey string
+	client *http.Client
+}
+
+// New builds the collector from config. Required settings: backend
+// ("fortios" or "panos") and url. FortiOS needs token (a REST API
+// admin token); PAN-OS needs api_key. Optional: insecure ("true"
+// skips TLS verification — firewall management interfaces almost
+// always present self-signed certificates).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:    cfg.Name,
+		backend: cfg.Settings["backend"],
+		url:     strings.TrimSuffix(cfg.Settings["url"], "/"),
+		token:   cfg.Settings["token"],
+		apiKey:  cfg.Settings["api_key"],
+	}
+	if c.url == "" {
+		return nil, fmt.Errorf("firewall: url setting is required")
+	}
+	switch c.backend {
+	case "fortios":
+		if c.token == "" {
+			return nil, fmt.Errorf("firewall: fortios backend requires the token setting")
+		}
+	case "panos":
+		if c.apiKey == "" {
+			return nil, fmt.Errorf("firewall: panos backend requires the api_key setting")
+		}
+	default:
+		return nil, fmt.Errorf("firewall: backend setting must be \"fortios\" or \"panos\", got %q", c.backend)
+	}
+	transport := &http.Transport{}
+	if cfg.Settings["insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c.client = &http.Client{Timeout: 60 * time.Second, Transport: transport}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect dispatches to the configured backend.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	switch c.backend {
+	case "fortios":
+		return c.collectFortiOS(ctx)
+	default:
+		return c.collectPANOS(ctx)
+	}
+}
+
+// natEdge emits the published-service-to-internal-host pair for one
+// VIP or NAT rule. hosts dedups the thin internal assets when several
+// rules forward to the same address.
+func natEdge(res *model.CollectResult, hosts map[string]bool, deviceID, rule, externalIP, internalIP string, props map[string]any) {
+	svcID := deviceID + "/nat/" + rule
+	svc := &model.Asset{
+		Name:       rule,
+		AssetType:  "published_service",
+		ExternalID: svcID,
+		Attributes: map[string]any{},
+	}
+	if externalIP != "" {
+		svc.IPs = []string{externalIP}
+	}
+	res.Assets = append(res.Assets, svc)
+
+	hostID := deviceID + "/host/" + internalIP
+	if !hosts[hostID] {
+		hosts[hostID] = true
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       internalIP,
+			AssetType:  "host",
+			ExternalID: hostID,
+			IPs:        []string{internalIP},
+			Attributes: map[string]any{},
+		})
+	}
+	if props == nil {
+		props = map[string]any{}
+	}
+	props["via"] = "nat"
+	res.Relationships = append(res.Relationships, model.Relationship{
+		FromExternalID: svcID,
+		ToExternalID:   hostID,
+		Type:           model.RelConnectsTo,
+		Properties:     props,
+	})
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "backend", Type: collector.TypeString, Required: true, Description: "\"fortios\" or \"panos\""},
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "device API base URL"},
+		{Name: "token", Type: collector.TypeString, Description: "API token (fortios backend)"},
+		{Name: "api_key", Type: collector.TypeString, Description: "API key (panos backend)"},
+		{Name: "insecure", Type: collector.TypeBool, Description: "skip TLS verification"},
+	}
+}
@@ -0,0 +1,166 @@
+package firewall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+func (c *Collector) fortiGet(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("firewall: fortios request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("firewall: fortios %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("firewall: decode fortios %s: %w", path, err)
+	}
+	return nil
+}
+
+// collectFortiOS reads the device identity, interfaces, VIPs and
+// address objects over the REST API.
+func (c *Collector) collectFortiOS(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	var status struct {
+		Serial  string `json:"serial"`
+		Version string `json:"version"`
+		Results struct {
+			Hostname  string `json:"hostname"`
+			ModelName string `json:"model_name"`
+		} `json:"results"`
+	}
+	if err := c.fortiGet(ctx, "/api/v2/monitor/system/status", &status); err != nil {
+		return model.CollectResult{}, err
+	}
+	deviceID := "firewall/" + status.Serial
+	if status.Serial == "" {
+		deviceID = "firewall/" + status.Results.Hostname
+	}
+	res.Assets = append(res.Assets, &model.Asset{
+		Name:       status.Results.Hostname,
+		AssetType:  "firewall",
+		ExternalID: deviceID,
+		Attributes: map[string]any{
+			"vendor":  "fortinet",
+			"model":   status.Results.ModelName,
+			"version": status.Version,
+			"serial":  status.Serial,
+		},
+	})
+
+	var interfaces struct {
+		Results []struct {
+			Name   string `json:"name"`
+			IP     string `json:"ip"` // "10.0.0.1 255.255.255.0"
+			Status string `json:"status"`
+			Type   string `json:"type"`
+			VDOM   string `json:"vdom"`
+		} `json:"results"`
+	}
+	if err := c.fortiGet(ctx, "/api/v2/cmdb/system/interface", &interfaces); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, intf := range interfaces.Results {
+		id := deviceID + "/intf/" + intf.Name
+		a := &model.Asset{
+			Name:       intf.Name,
+			AssetType:  "network_interface",
+			ExternalID: id,
+			Attributes: map[string]any{
+				"status": intf.Status,
+				"type":   intf.Type,
+				"vdom":   intf.VDOM,
+			},
+		}
+		if intf.IP != "" && !strings.HasPrefix(intf.IP, "0.0.0.0") {
+			a.Attributes["address"] = intf.IP
+		}
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: deviceID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	var vips struct {
+		Results []struct {
+			Name     string `json:"name"`
+			ExtIP    string `json:"extip"`
+			ExtPort  string `json:"extport"`
+			MapPort  string `json:"mappedport"`
+			MappedIP []struct {
+				Range string `json:"range"`
+			} `json:"mappedip"`
+		} `json:"results"`
+	}
+	if err := c.fortiGet(ctx, "/api/v2/cmdb/firewall/vip", &vips); err != nil {
+		return model.CollectResult{}, err
+	}
+	hosts := map[string]bool{}
+	for _, vip := range vips.Results {
+		for _, mapped := range vip.MappedIP {
+			// Ranges are "a.b.c.d" or "a.b.c.d-a.b.c.e"; keep the first
+			// address, which is where single-host VIPs point.
+			internal, _, _ := strings.Cut(mapped.Range, "-")
+			if internal == "" {
+				continue
+			}
+			props := map[string]any{}
+			if vip.ExtPort != "" && vip.ExtPort != "0" {
+				props["external_port"] = vip.ExtPort
+			}
+			if vip.MapPort != "" && vip.MapPort != "0" {
+				props["internal_port"] = vip.MapPort
+			}
+			natEdge(&res, hosts, deviceID, vip.Name, vip.ExtIP, internal, props)
+		}
+	}
+
+	var addresses struct {
+		Results []struct {
+			Name   string `json:"name"`
+			Subnet string `json:"subnet"` // "ip mask"
+			Type   string `json:"type"`
+		} `json:"results"`
+	}
+	if err := c.fortiGet(ctx, "/api/v2/cmdb/firewall/address", &addresses); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, addr := range addresses.Results {
+		id := deviceID + "/addr/" + addr.Name
+		a := &model.Asset{
+			Name:       addr.Name,
+			AssetType:  "address_object",
+			ExternalID: id,
+			Attributes: map[string]any{"type": addr.Type},
+		}
+		if addr.Subnet != "" {
+			a.Attributes["subnet"] = addr.Subnet
+		}
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: deviceID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
@@ -0,0 +1,194 @@
+package firewall
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// panGet runs one XML API call and decodes the response envelope.
+func (c *Collector) panGet(ctx context.Context, params url.Values, out any) error {
+	params.Set("key", c.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/api/?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("firewall: panos request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("firewall: panos returned status %d", resp.StatusCode)
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("firewall: decode panos response: %w", err)
+	}
+	return nil
+}
+
+func panOp(cmd string) url.Values {
+	return url.Values{"type": {"op"}, "cmd": {cmd}}
+}
+
+func panConfig(xpath string) url.Values {
+	return url.Values{"type": {"config"}, "action": {"get"}, "xpath": {xpath}}
+}
+
+// vsysXPath addresses the default vsys on a standalone firewall,
+// which is where single-vsys deployments keep everything.
+const vsysXPath = "/config/devices/entry[@name='localhost.localdomain']/vsys/entry[@name='vsys1']"
+
+// collectPANOS reads the device identity, interfaces, NAT rules and
+// address objects over the XML API.
+func (c *Collector) collectPANOS(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	var info struct {
+		Status string `xml:"status,attr"`
+		System struct {
+			Hostname  string `xml:"hostname"`
+			IPAddress string `xml:"ip-address"`
+			Serial    string `xml:"serial"`
+			Model     string `xml:"model"`
+			SWVersion string `xml:"sw-version"`
+		} `xml:"result>system"`
+	}
+	if err := c.panGet(ctx, panOp("<show><system><info></info></system></show>"), &info); err != nil {
+		return model.CollectResult{}, err
+	}
+	if info.Status != "success" {
+		return model.CollectResult{}, fmt.Errorf("firewall: panos system info reported status %q", info.Status)
+	}
+	deviceID := "firewall/" + info.System.Serial
+	device := &model.Asset{
+		Name:       info.System.Hostname,
+		AssetType:  "firewall",
+		ExternalID: deviceID,
+		Attributes: map[string]any{
+			"vendor":  "paloalto",
+			"model":   info.System.Model,
+			"version": info.System.SWVersion,
+			"serial":  info.System.Serial,
+		},
+	}
+	if info.System.IPAddress != "" {
+		device.IPs = []string{info.System.IPAddress}
+	}
+	res.Assets = append(res.Assets, device)
+
+	var ifaces struct {
+		Entries []struct {
+			Name string `xml:"name"`
+			IP   string `xml:"ip"`
+			Zone string `xml:"zone"`
+		} `xml:"result>ifnet>entry"`
+	}
+	if err := c.panGet(ctx, panOp("<show><interface>all</interface></show>"), &ifaces); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, intf := range ifaces.Entries {
+		id := deviceID + "/intf/" + intf.Name
+		a := &model.Asset{
+			Name:       intf.Name,
+			AssetType:  "network_interface",
+			ExternalID: id,
+			Attributes: map[string]any{"zone": intf.Zone},
+		}
+		if intf.IP != "" && intf.IP != "N/A" {
+			a.Attributes["address"] = intf.IP
+		}
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: deviceID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	var nat struct {
+		Rules []struct {
+			Name        string   `xml:"name,attr"`
+			Destination []string `xml:"destination>member"`
+			Service     string   `xml:"service"`
+			DstXlate    struct {
+				TranslatedAddress string `xml:"translated-address"`
+				TranslatedPort    string `xml:"translated-port"`
+			} `xml:"destination-translation"`
+		} `xml:"result>rules>entry"`
+	}
+	if err := c.panGet(ctx, panConfig(vsysXPath+"/rulebase/nat/rules"), &nat); err != nil {
+		return model.CollectResult{}, err
+	}
+	hosts := map[string]bool{}
+	for _, rule := range nat.Rules {
+		res.Stats.Fetched++
+		if rule.DstXlate.TranslatedAddress == "" {
+			// Source-NAT rules describe outbound masquerading, not
+			// published services.
+			res.Stats.Filtered++
+			continue
+		}
+		external := ""
+		if len(rule.Destination) > 0 && rule.Destination[0] != "any" {
+			external = stripCIDR(rule.Destination[0])
+		}
+		props := map[string]any{}
+		if rule.Service != "" && rule.Service != "any" {
+			props["service"] = rule.Service
+		}
+		if rule.DstXlate.TranslatedPort != "" {
+			props["internal_port"] = rule.DstXlate.TranslatedPort
+		}
+		natEdge(&res, hosts, deviceID, rule.Name, external, stripCIDR(rule.DstXlate.TranslatedAddress), props)
+	}
+
+	var addresses struct {
+		Entries []struct {
+			Name      string `xml:"name,attr"`
+			IPNetmask string `xml:"ip-netmask"`
+			FQDN      string `xml:"fqdn"`
+		} `xml:"result>address>entry"`
+	}
+	if err := c.panGet(ctx, panConfig(vsysXPath+"/address"), &addresses); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, addr := range addresses.Entries {
+		id := deviceID + "/addr/" + addr.Name
+		a := &model.Asset{
+			Name:       addr.Name,
+			AssetType:  "address_object",
+			ExternalID: id,
+			Attributes: map[string]any{},
+		}
+		if addr.IPNetmask != "" {
+			a.Attributes["subnet"] = addr.IPNetmask
+		}
+		if addr.FQDN != "" {
+			a.Attributes["fqdn"] = addr.FQDN
+		}
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: deviceID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	res.Stats.Fetched += len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// stripCIDR drops a /32-style suffix from an address object value.
+func stripCIDR(addr string) string {
+	if i := strings.IndexByte(addr, '/'); i >= 0 {
+		return addr[:i]
+	}
+	return addr
+}
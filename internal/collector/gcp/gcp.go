@@ -0,0 +1,249 @@
+// Package gcp imports Compute Engine instances, VPC networks,
+// forwarding rules and GKE clusters from one or more Google Cloud
+// projects. Networks contain their instances and clusters; GKE
+// clusters are linked to their node instances (recognized by the
+// goog-k8s-cluster-name label Google applies to every node) with
+// RUNS_ON edges.
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+	"google.golang.org/api/option"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("gcp", New)
+}
+
+// Collector enumerates Google Cloud projects.
+type Collector struct {
+	name     string
+	projects []string
+	credFile string
+}
+
+// New builds the collector from config. Required settings: projects (a
+// comma-separated list of project IDs). Optional: credentials_file (a
+// service account key; empty uses application default credentials).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{name: cfg.Name, credFile: cfg.Settings["credentials_file"]}
+	for _, p := range strings.Split(cfg.Settings["projects"], ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			c.projects = append(c.projects, p)
+		}
+	}
+	if len(c.projects) == 0 {
+		return nil, fmt.Errorf("gcp: projects setting is required")
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect walks every configured project. External IDs are the
+// resources' self-link paths without the API prefix
+// ("projects/p/zones/z/instances/i"), which are unique across projects
+// and stable for the resource's lifetime.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var opts []option.ClientOption
+	if c.credFile != "" {
+		opts = append(opts, option.WithCredentialsFile(c.credFile))
+	}
+	computeSvc, err := compute.NewService(ctx, opts...)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("gcp: compute client: %w", err)
+	}
+	containerSvc, err := container.NewService(ctx, opts...)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("gcp: container client: %w", err)
+	}
+	var res model.CollectResult
+	for _, project := range c.projects {
+		if err := c.collectProject(ctx, computeSvc, containerSvc, project, &res); err != nil {
+			return model.CollectResult{}, fmt.Errorf("gcp: project %s: %w", project, err)
+		}
+	}
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func (c *Collector) collectProject(ctx context.Context, computeSvc *compute.Service, containerSvc *container.Service, project string, res *model.CollectResult) error {
+	err := computeSvc.Networks.List(project).Pages(ctx, func(page *compute.NetworkList) error {
+		for _, n := range page.Items {
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       n.Name,
+				AssetType:  "network",
+				ExternalID: selfPath(n.SelfLink),
+				Attributes: map[string]any{"project": project},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("list networks: %w", err)
+	}
+
+	// clusterIDs maps cluster name to external ID so node instances can
+	// be linked after both sides are known.
+	clusterIDs := map[string]string{}
+	clusters, err := containerSvc.Projects.Locations.Clusters.
+		List(fmt.Sprintf("projects/%s/locations/-", project)).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("list gke clusters: %w", err)
+	}
+	for _, cl := range clusters.Clusters {
+		id := fmt.Sprintf("projects/%s/locations/%s/clusters/%s", project, cl.Location, cl.Name)
+		clusterIDs[cl.Name] = id
+		a := &model.Asset{
+			Name:       cl.Name,
+			AssetType:  "cluster",
+			ExternalID: id,
+			Attributes: map[string]any{
+				"project":            project,
+				"location":           cl.Location,
+				"kubernetes_version": cl.CurrentMasterVersion,
+				"node_count":         cl.CurrentNodeCount,
+				"status":             cl.Status,
+			},
+		}
+		if cl.Endpoint != "" {
+			a.IPs = []string{cl.Endpoint}
+		}
+		if len(cl.ResourceLabels) > 0 {
+			a.Attributes["labels"] = cl.ResourceLabels
+		}
+		res.Assets = append(res.Assets, a)
+		if cl.Network != "" {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: fmt.Sprintf("projects/%s/global/networks/%s", project, cl.Network),
+				ToExternalID:   id,
+				Type:           model.RelContains,
+			})
+		}
+	}
+
+	err = computeSvc.Instances.AggregatedList(project).Pages(ctx, func(page *compute.InstanceAggregatedList) error {
+		for _, scoped := range page.Items {
+			for _, inst := range scoped.Instances {
+				c.addInstance(inst, project, clusterIDs, res)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("list instances: %w", err)
+	}
+
+	err = computeSvc.ForwardingRules.AggregatedList(project).Pages(ctx, func(page *compute.ForwardingRuleAggregatedList) error {
+		for _, scoped := range page.Items {
+			for _, fr := range scoped.ForwardingRules {
+				a := &model.Asset{
+					Name:       fr.Name,
+					AssetType:  "load_balancer",
+					ExternalID: selfPath(fr.SelfLink),
+					Attributes: map[string]any{
+						"project":               project,
+						"load_balancing_scheme": fr.LoadBalancingScheme,
+						"port_range":            fr.PortRange,
+					},
+				}
+				if fr.IPAddress != "" {
+					a.IPs = []string{fr.IPAddress}
+				}
+				res.Assets = append(res.Assets, a)
+				if fr.Network != "" {
+					res.Relationships = append(res.Relationships, model.Relationship{
+						FromExternalID: selfPath(fr.Network),
+						ToExternalID:   a.ExternalID,
+						Type:           model.RelContains,
+					})
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("list forwarding rules: %w", err)
+	}
+	return nil
+}
+
+func (c *Collector) addInstance(inst *compute.Instance, project string, clusterIDs map[string]string, res *model.CollectResult) {
+	id := selfPath(inst.SelfLink)
+	a := &model.Asset{
+		Name:       inst.Name,
+		AssetType:  "vm",
+		ExternalID: id,
+		Attributes: map[string]any{
+			"project":      project,
+			"machine_type": lastSegment(inst.MachineType),
+			"zone":         lastSegment(inst.Zone),
+			"status":       inst.Status,
+		},
+	}
+	if len(inst.Labels) > 0 {
+		a.Attributes["labels"] = inst.Labels
+	}
+	for _, ni := range inst.NetworkInterfaces {
+		if ni.NetworkIP != "" {
+			a.IPs = append(a.IPs, ni.NetworkIP)
+		}
+		for _, ac := range ni.AccessConfigs {
+			if ac.NatIP != "" {
+				a.IPs = append(a.IPs, ac.NatIP)
+			}
+		}
+		if ni.Network != "" {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: selfPath(ni.Network),
+				ToExternalID:   id,
+				Type:           model.RelContains,
+			})
+		}
+	}
+	res.Assets = append(res.Assets, a)
+	// GKE labels every node with its cluster; the cluster runs on its
+	// nodes.
+	if cluster, ok := clusterIDs[inst.Labels["goog-k8s-cluster-name"]]; ok {
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: cluster,
+			ToExternalID:   id,
+			Type:           model.RelRunsOn,
+		})
+	}
+}
+
+// selfPath strips the API host prefix from a self link, leaving the
+// stable "projects/..." path.
+func selfPath(link string) string {
+	if _, path, ok := strings.Cut(link, "/projects/"); ok {
+		return "projects/" + path
+	}
+	return link
+}
+
+func lastSegment(link string) string {
+	if i := strings.LastIndexByte(link, '/'); i >= 0 {
+		return link[i+1:]
+	}
+	return link
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "projects", Type: collector.TypeList, Required: true, Description: "project IDs to enumerate"},
+		{Name: "credentials_file", Type: collector.TypeString, Description: "service-account key file; empty uses default credentials"},
+	}
+}
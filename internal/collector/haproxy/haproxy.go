@@ -0,0 +1,218 @@
+// Package haproxy imports frontends, backends and servers from a
+// HAProxy instance, read either from the runtime stats socket ("show
+// stat") or from the HTTP stats endpoint in CSV form. Servers are
+// MEMBER_OF their backend and each backend LOAD_BALANCES its servers,
+// so open-source LB topologies sit alongside the netscaler and f5
+// collectors' output. The stats CSV does not expose which frontend
+// routes to which backend (that is use_backend logic in the config),
+// so no frontend-to-backend edges are emitted.
+package haproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("haproxy", New)
+}
+
+// Collector reads one HAProxy instance.
+type Collector struct {
+	name     string
+	socket   string
+	statsURL string
+	username string
+	password string
+	client   *http.Client
+}
+
+// New builds the collector from config. Exactly one of the settings
+// socket (a unix path like /run/haproxy/admin.sock, or host:port for a
+// TCP stats socket) and stats_url (the stats page; ";csv" is appended
+// if missing) is required. Optional for stats_url: username, password.
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		socket:   cfg.Settings["socket"],
+		statsURL: cfg.Settings["stats_url"],
+		username: cfg.Settings["username"],
+		password: cfg.Settings["password"],
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+	if (c.socket == "") == (c.statsURL == "") {
+		return nil, fmt.Errorf("haproxy: exactly one of socket and stats_url is required")
+	}
+	if c.statsURL != "" && !strings.HasSuffix(c.statsURL, ";csv") {
+		c.statsURL += ";csv"
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect fetches and parses the stats CSV.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var raw []byte
+	var err error
+	if c.socket != "" {
+		raw, err = c.readSocket(ctx)
+	} else {
+		raw, err = c.readHTTP(ctx)
+	}
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	return parseStats(raw)
+}
+
+// Preview implements collector.Previewer: it parses an uploaded stats
+// CSV instead of contacting the instance.
+func (c *Collector) Preview(ctx context.Context, payload []byte) (model.CollectResult, error) {
+	return parseStats(payload)
+}
+
+func (c *Collector) readSocket(ctx context.Context) ([]byte, error) {
+	network := "unix"
+	if strings.Contains(c.socket, ":") {
+		network = "tcp"
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, c.socket)
+	if err != nil {
+		return nil, fmt.Errorf("haproxy: dial stats socket: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(30 * time.Second))
+	}
+	if _, err := io.WriteString(conn, "show stat\n"); err != nil {
+		return nil, fmt.Errorf("haproxy: write command: %w", err)
+	}
+	raw, err := io.ReadAll(conn)
+	if err != nil {
+		return nil, fmt.Errorf("haproxy: read stats: %w", err)
+	}
+	return raw, nil
+}
+
+func (c *Collector) readHTTP(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.statsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("haproxy: stats page returned status %d", resp.StatusCode)
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("haproxy: read stats: %w", err)
+	}
+	return raw, nil
+}
+
+// parseStats reads the stats CSV. The first line is a header starting
+// with "# "; columns are addressed by name since their order has grown
+// over HAProxy versions.
+func parseStats(raw []byte) (model.CollectResult, error) {
+	var res model.CollectResult
+	lines := strings.Split(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "# ") {
+		return model.CollectResult{}, fmt.Errorf("haproxy: unexpected stats format (missing header)")
+	}
+	cols := map[string]int{}
+	for i, name := range strings.Split(strings.TrimPrefix(lines[0], "# "), ",") {
+		cols[name] = i
+	}
+	field := func(fields []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return fields[i]
+	}
+	for _, line := range lines[1:] {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		px, sv := field(fields, "pxname"), field(fields, "svname")
+		if px == "" || sv == "" {
+			continue
+		}
+		res.Stats.Fetched++
+		switch sv {
+		case "FRONTEND":
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       px,
+				AssetType:  "lb_frontend",
+				ExternalID: "frontend/" + px,
+				Attributes: map[string]any{"status": field(fields, "status")},
+			})
+		case "BACKEND":
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       px,
+				AssetType:  "lb_backend",
+				ExternalID: "backend/" + px,
+				Attributes: map[string]any{"status": field(fields, "status")},
+			})
+		default:
+			serverID := "server/" + px + "/" + sv
+			a := &model.Asset{
+				Name:       sv,
+				AssetType:  "host",
+				ExternalID: serverID,
+				Attributes: map[string]any{"status": field(fields, "status")},
+			}
+			if addr := field(fields, "addr"); addr != "" {
+				if ip, _, ok := strings.Cut(addr, ":"); ok && net.ParseIP(ip) != nil {
+					a.IPs = []string{ip}
+				}
+			}
+			res.Assets = append(res.Assets, a)
+			res.Relationships = append(res.Relationships,
+				model.Relationship{
+					FromExternalID: serverID,
+					ToExternalID:   "backend/" + px,
+					Type:           model.RelMemberOf,
+				},
+				model.Relationship{
+					FromExternalID: "backend/" + px,
+					ToExternalID:   serverID,
+					Type:           model.RelLoadBalances,
+				})
+		}
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "socket", Type: collector.TypeString, Description: "stats socket path (one of socket and stats_url)"},
+		{Name: "stats_url", Type: collector.TypeString, Description: "stats endpoint URL (one of socket and stats_url)"},
+		{Name: "username", Type: collector.TypeString, Description: "stats endpoint user"},
+		{Name: "password", Type: collector.TypeString, Description: "stats endpoint password"},
+	}
+}
@@ -0,0 +1,45 @@
+package haproxy
+
+import "testing"
+
+const sampleStats = `# pxname,svname,status,addr
+www,FRONTEND,OPEN,
+app,web1,UP,10.0.0.11:8080
+app,web2,DOWN,10.0.0.12:8080
+app,BACKEND,UP,
+`
+
+func TestParseStats(t *testing.T) {
+	res, err := parseStats([]byte(sampleStats))
+	if err != nil {
+		t.Fatalf("parseStats: %v", err)
+	}
+	// 1 frontend + 1 backend + 2 servers.
+	if len(res.Assets) != 4 {
+		t.Fatalf("got %d assets, want 4: %+v", len(res.Assets), res.Assets)
+	}
+	byID := map[string]bool{}
+	for _, a := range res.Assets {
+		byID[a.ExternalID] = true
+		if a.ExternalID == "server/app/web1" {
+			if len(a.IPs) != 1 || a.IPs[0] != "10.0.0.11" {
+				t.Errorf("web1 ips = %v, want [10.0.0.11]", a.IPs)
+			}
+		}
+	}
+	for _, id := range []string{"frontend/www", "backend/app", "server/app/web1", "server/app/web2"} {
+		if !byID[id] {
+			t.Errorf("missing asset %s", id)
+		}
+	}
+	// Each server gets a MEMBER_OF and a LOAD_BALANCES edge.
+	if len(res.Relationships) != 4 {
+		t.Fatalf("got %d relationships, want 4", len(res.Relationships))
+	}
+}
+
+func TestParseStatsRejectsGarbage(t *testing.T) {
+	if _, err := parseStats([]byte("<html>")); err == nil {
+		t.Fatal("expected an error for non-CSV input")
+	}
+}
@@ -0,0 +1,247 @@
+// Package helm lists installed Helm releases, giving the Kubernetes
+// domain an application-level inventory above raw pods. Helm 3 stores
+// each release revision as a Secret of type helm.sh/release.v1, so no
+// Helm binary or SDK is needed: the collector reads the secrets, keeps
+// the latest revision per release and emits a helm_release asset with
+// CONTAINS edges to the Deployments, StatefulSets and DaemonSets the
+// release owns (matched via the meta.helm.sh annotations Helm stamps
+// on everything it installs).
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("helm", New)
+}
+
+// Collector lists releases in one cluster.
+type Collector struct {
+	name    string
+	cluster string
+	client  kubernetes.Interface
+}
+
+// New builds the collector from config. Settings match the k8s
+// collector: kubeconfig (path; empty means in-cluster config) and
+// cluster (a label used to prefix external IDs, defaulting to the
+// collector name).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	var (
+		restCfg *rest.Config
+		err     error
+	)
+	if path := cfg.Settings["kubeconfig"]; path != "" {
+		restCfg, err = clientcmd.BuildConfigFromFlags("", path)
+	} else {
+		restCfg, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("helm: load config: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, fmt.Errorf("helm: build client: %w", err)
+	}
+	cluster := cfg.Settings["cluster"]
+	if cluster == "" {
+		cluster = cfg.Name
+	}
+	return &Collector{name: cfg.Name, cluster: cluster, client: client}, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// release is the part of Helm's stored release payload the collector
+// keeps.
+type release struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status       string `json:"status"`
+		LastDeployed string `json:"last_deployed"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+// decodeRelease unwraps the storage encoding: the secret's release key
+// is base64-encoded gzipped JSON.
+func decodeRelease(data []byte) (*release, error) {
+	raw := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+	n, err := base64.StdEncoding.Decode(raw, data)
+	if err != nil {
+		return nil, fmt.Errorf("base64: %w", err)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(raw[:n]))
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	defer gz.Close()
+	payload, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("gunzip: %w", err)
+	}
+	var rel release
+	if err := json.Unmarshal(payload, &rel); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+	return &rel, nil
+}
+
+func (c *Collector) releaseID(namespace, name string) string {
+	return fmt.Sprintf("%s/release/%s/%s", c.cluster, namespace, name)
+}
+
+// Collect reads the release secrets, then walks the workload types
+// Helm typically owns and attaches each to its release.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	secrets, err := c.client.CoreV1().Secrets("").List(ctx, metav1.ListOptions{
+		FieldSelector: "type=helm.sh/release.v1",
+	})
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("helm: list release secrets: %w", err)
+	}
+	res.Stats.Fetched = len(secrets.Items)
+
+	// Keep the newest revision of each release; superseded revisions
+	// are history, not inventory.
+	latest := map[string]*release{}
+	for _, sec := range secrets.Items {
+		data, ok := sec.Data["release"]
+		if !ok {
+			res.Stats.Filtered++
+			continue
+		}
+		rel, err := decodeRelease(data)
+		if err != nil {
+			return model.CollectResult{}, fmt.Errorf("helm: decode release %s/%s: %w", sec.Namespace, sec.Name, err)
+		}
+		key := rel.Namespace + "/" + rel.Name
+		if cur := latest[key]; cur == nil || rel.Version > cur.Version {
+			if cur != nil {
+				res.Stats.Filtered++
+			}
+			latest[key] = rel
+		} else {
+			res.Stats.Filtered++
+		}
+	}
+
+	for _, rel := range latest {
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       rel.Name,
+			AssetType:  "helm_release",
+			ExternalID: c.releaseID(rel.Namespace, rel.Name),
+			Attributes: map[string]any{
+				"cluster":       c.cluster,
+				"namespace":     rel.Namespace,
+				"chart":         rel.Chart.Metadata.Name,
+				"chart_version": rel.Chart.Metadata.Version,
+				"app_version":   rel.Chart.Metadata.AppVersion,
+				"revision":      rel.Version,
+				"status":        rel.Info.Status,
+			},
+		})
+	}
+
+	if err := c.collectWorkloads(ctx, latest, &res); err != nil {
+		return model.CollectResult{}, err
+	}
+
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// collectWorkloads emits the release-owned workloads and their
+// CONTAINS edges. Workloads are matched by the meta.helm.sh/
+// release-name and release-namespace annotations.
+func (c *Collector) collectWorkloads(ctx context.Context, releases map[string]*release, res *model.CollectResult) error {
+	emit := func(kind, namespace, name string, annotations map[string]string) {
+		relName := annotations["meta.helm.sh/release-name"]
+		relNS := annotations["meta.helm.sh/release-namespace"]
+		if relName == "" {
+			return
+		}
+		if relNS == "" {
+			relNS = namespace
+		}
+		if releases[relNS+"/"+relName] == nil {
+			return
+		}
+		id := fmt.Sprintf("%s/%s/%s/%s", c.cluster, kind, namespace, name)
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       name,
+			AssetType:  "k8s_workload",
+			ExternalID: id,
+			Attributes: map[string]any{
+				"cluster":   c.cluster,
+				"kind":      kind,
+				"namespace": namespace,
+			},
+		})
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: c.releaseID(relNS, relName),
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	deployments, err := c.client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("helm: list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		emit("deployment", d.Namespace, d.Name, d.Annotations)
+	}
+
+	statefulsets, err := c.client.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("helm: list statefulsets: %w", err)
+	}
+	for _, s := range statefulsets.Items {
+		emit("statefulset", s.Namespace, s.Name, s.Annotations)
+	}
+
+	daemonsets, err := c.client.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("helm: list daemonsets: %w", err)
+	}
+	for _, d := range daemonsets.Items {
+		emit("daemonset", d.Namespace, d.Name, d.Annotations)
+	}
+	return nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "kubeconfig", Type: collector.TypeString, Description: "path; empty means in-cluster config"},
+		{Name: "cluster", Type: collector.TypeString, Description: "label prefixed to external IDs, defaults to the collector name"},
+	}
+}
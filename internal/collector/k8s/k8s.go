@@ -0,0 +1,256 @@
+// Package k8s imports Kubernetes clusters: nodes, namespaces, pods and
+// services become assets, with RUNS_ON edges from pods to their nodes
+// and CONTAINS edges from namespaces to the workloads inside them. A
+// streaming mode follows shared informers and reports only what
+// changed between periodic full baselines, for clusters too large to
+// relist every interval.
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("k8s", New)
+}
+
+// Collector lists cluster objects through the Kubernetes API.
+type Collector struct {
+	name          string
+	cluster       string
+	client        kubernetes.Interface
+	streaming     bool
+	resync        time.Duration
+	fullSyncEvery int
+
+	mu   sync.Mutex
+	strm *stream
+}
+
+// New builds the collector from config. Settings: kubeconfig (path;
+// empty means in-cluster config) and cluster (a label used to prefix
+// external IDs, defaulting to the collector name). Optional: stream
+// ("true" follows informers instead of relisting each interval),
+// resync (the informers' safety relist period, Go duration, default
+// 10m) and full_sync_every (how many streaming runs between full
+// baselines, default 10).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	var (
+		restCfg *rest.Config
+		err     error
+	)
+	if path := cfg.Settings["kubeconfig"]; path != "" {
+		restCfg, err = clientcmd.BuildConfigFromFlags("", path)
+	} else {
+		restCfg, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("k8s: load config: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return nil, fmt.Errorf("k8s: build client: %w", err)
+	}
+	cluster := cfg.Settings["cluster"]
+	if cluster == "" {
+		cluster = cfg.Name
+	}
+	c := &Collector{
+		name:          cfg.Name,
+		cluster:       cluster,
+		client:        client,
+		streaming:     cfg.Settings["stream"] == "true",
+		resync:        10 * time.Minute,
+		fullSyncEvery: 10,
+	}
+	if raw := cfg.Settings["resync"]; raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("k8s: invalid resync %q: %w", raw, err)
+		}
+		c.resync = d
+	}
+	if raw := cfg.Settings["full_sync_every"]; raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("k8s: invalid full_sync_every %q", raw)
+		}
+		c.fullSyncEvery = n
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) extID(kind, namespace, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s/%s", c.cluster, kind, name)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", c.cluster, kind, namespace, name)
+}
+
+// Collect lists nodes, namespaces, pods and services. In streaming
+// mode the listing is replaced by draining the informers' changes.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	if c.streaming {
+		return c.collectStream(ctx)
+	}
+	var res model.CollectResult
+
+	nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return res, fmt.Errorf("k8s: list nodes: %w", err)
+	}
+	for i := range nodes.Items {
+		res.Assets = append(res.Assets, c.nodeAsset(&nodes.Items[i]))
+	}
+
+	namespaces, err := c.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return res, fmt.Errorf("k8s: list namespaces: %w", err)
+	}
+	for i := range namespaces.Items {
+		res.Assets = append(res.Assets, c.namespaceAsset(&namespaces.Items[i]))
+	}
+
+	pods, err := c.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return res, fmt.Errorf("k8s: list pods: %w", err)
+	}
+	for i := range pods.Items {
+		a, rels := c.podAsset(&pods.Items[i])
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, rels...)
+	}
+
+	services, err := c.client.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return res, fmt.Errorf("k8s: list services: %w", err)
+	}
+	for i := range services.Items {
+		a, rels := c.serviceAsset(&services.Items[i])
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, rels...)
+	}
+
+	res.Stats.Fetched = len(nodes.Items) + len(namespaces.Items) + len(pods.Items) + len(services.Items)
+	res.Stats.Emitted = len(res.Assets)
+	res.Stats.Filtered = res.Stats.Fetched - res.Stats.Emitted
+	return res, nil
+}
+
+func (c *Collector) nodeAsset(n *corev1.Node) *model.Asset {
+	a := &model.Asset{
+		Name:       n.Name,
+		AssetType:  "k8s_node",
+		ExternalID: c.extID("node", "", n.Name),
+		Attributes: map[string]any{
+			"cluster":         c.cluster,
+			"kubelet_version": n.Status.NodeInfo.KubeletVersion,
+			"os_image":        n.Status.NodeInfo.OSImage,
+			"labels":          n.Labels,
+		},
+	}
+	for _, addr := range n.Status.Addresses {
+		switch addr.Type {
+		case "InternalIP", "ExternalIP":
+			a.IPs = append(a.IPs, addr.Address)
+		case "Hostname":
+			if a.FQDN == "" {
+				a.FQDN = addr.Address
+			}
+		}
+	}
+	return a
+}
+
+func (c *Collector) namespaceAsset(ns *corev1.Namespace) *model.Asset {
+	return &model.Asset{
+		Name:       ns.Name,
+		AssetType:  "k8s_namespace",
+		ExternalID: c.extID("namespace", "", ns.Name),
+		Attributes: map[string]any{"cluster": c.cluster, "labels": ns.Labels},
+	}
+}
+
+func (c *Collector) podAsset(p *corev1.Pod) (*model.Asset, []model.Relationship) {
+	id := c.extID("pod", p.Namespace, p.Name)
+	a := &model.Asset{
+		Name:       p.Name,
+		AssetType:  "k8s_pod",
+		ExternalID: id,
+		Attributes: map[string]any{
+			"cluster":   c.cluster,
+			"namespace": p.Namespace,
+			"phase":     string(p.Status.Phase),
+			"node":      p.Spec.NodeName,
+			"labels":    p.Labels,
+		},
+	}
+	if p.Status.PodIP != "" {
+		a.IPs = append(a.IPs, p.Status.PodIP)
+	}
+	var rels []model.Relationship
+	if p.Spec.NodeName != "" {
+		rels = append(rels, model.Relationship{
+			FromExternalID: id,
+			ToExternalID:   c.extID("node", "", p.Spec.NodeName),
+			Type:           model.RelRunsOn,
+		})
+	}
+	rels = append(rels, model.Relationship{
+		FromExternalID: c.extID("namespace", "", p.Namespace),
+		ToExternalID:   id,
+		Type:           model.RelContains,
+	})
+	return a, rels
+}
+
+func (c *Collector) serviceAsset(svc *corev1.Service) (*model.Asset, []model.Relationship) {
+	id := c.extID("service", svc.Namespace, svc.Name)
+	a := &model.Asset{
+		Name:       svc.Name,
+		AssetType:  "k8s_service",
+		ExternalID: id,
+		Attributes: map[string]any{
+			"cluster":   c.cluster,
+			"namespace": svc.Namespace,
+			"type":      string(svc.Spec.Type),
+		},
+	}
+	if svc.Spec.ClusterIP != "" && svc.Spec.ClusterIP != "None" {
+		a.IPs = append(a.IPs, svc.Spec.ClusterIP)
+	}
+	rels := []model.Relationship{{
+		FromExternalID: c.extID("namespace", "", svc.Namespace),
+		ToExternalID:   id,
+		Type:           model.RelContains,
+	}}
+	return a, rels
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "kubeconfig", Type: collector.TypeString, Description: "path; empty means in-cluster config"},
+		{Name: "cluster", Type: collector.TypeString, Description: "label prefixed to external IDs, defaults to the collector name"},
+		{Name: "stream", Type: collector.TypeBool, Description: "follow informers instead of relisting each interval"},
+		{Name: "resync", Type: collector.TypeDuration, Description: "informer safety relist period (default 10m)"},
+		{Name: "full_sync_every", Type: collector.TypeInt, Description: "streaming runs between full baselines (default 10)"},
+	}
+}
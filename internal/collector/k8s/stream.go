@@ -0,0 +1,235 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// objKey identifies one changed object in the pending set.
+type objKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// stream is the informer machinery streaming mode keeps running
+// between Collect calls. Event handlers record which objects changed;
+// each run drains that set and reads the current state from the
+// informer caches, so the API server is never relisted outside the
+// informers' own resync.
+type stream struct {
+	factory    informers.SharedInformerFactory
+	nodes      corelisters.NodeLister
+	namespaces corelisters.NamespaceLister
+	pods       corelisters.PodLister
+	services   corelisters.ServiceLister
+	stop       chan struct{}
+
+	mu      sync.Mutex
+	pending map[objKey]bool
+	// runs counts streaming runs since the last baseline, so the
+	// collector periodically re-reports the full cache in case an
+	// event was missed.
+	runs int
+}
+
+// enqueue records a changed object; it also handles the tombstones
+// DeleteFunc receives when a watch missed the actual deletion.
+func (s *stream) enqueue(kind string, obj any) {
+	o, ok := obj.(metav1.Object)
+	if !ok {
+		tomb, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if o, ok = tomb.Obj.(metav1.Object); !ok {
+			return
+		}
+	}
+	s.mu.Lock()
+	s.pending[objKey{kind: kind, namespace: o.GetNamespace(), name: o.GetName()}] = true
+	s.mu.Unlock()
+}
+
+// drain empties the pending set.
+func (s *stream) drain() []objKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]objKey, 0, len(s.pending))
+	for k := range s.pending {
+		keys = append(keys, k)
+	}
+	s.pending = map[objKey]bool{}
+	return keys
+}
+
+// startStream builds the informer factory, hooks the change handlers
+// and waits for the caches to fill.
+func (c *Collector) startStream(ctx context.Context) (*stream, error) {
+	s := &stream{
+		factory: informers.NewSharedInformerFactory(c.client, c.resync),
+		stop:    make(chan struct{}),
+		pending: map[objKey]bool{},
+	}
+	nodes := s.factory.Core().V1().Nodes()
+	namespaces := s.factory.Core().V1().Namespaces()
+	pods := s.factory.Core().V1().Pods()
+	services := s.factory.Core().V1().Services()
+	s.nodes = nodes.Lister()
+	s.namespaces = namespaces.Lister()
+	s.pods = pods.Lister()
+	s.services = services.Lister()
+
+	synced := make([]cache.InformerSynced, 0, 4)
+	for kind, informer := range map[string]cache.SharedIndexInformer{
+		"node":      nodes.Informer(),
+		"namespace": namespaces.Informer(),
+		"pod":       pods.Informer(),
+		"service":   services.Informer(),
+	} {
+		kind := kind
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj any) { s.enqueue(kind, obj) },
+			UpdateFunc: func(_, obj any) { s.enqueue(kind, obj) },
+			DeleteFunc: func(obj any) { s.enqueue(kind, obj) },
+		})
+		if err != nil {
+			return nil, fmt.Errorf("k8s: add event handler: %w", err)
+		}
+		synced = append(synced, informer.HasSynced)
+	}
+	s.factory.Start(s.stop)
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		close(s.stop)
+		return nil, fmt.Errorf("k8s: informer cache sync interrupted: %w", ctx.Err())
+	}
+	return s, nil
+}
+
+// collectStream reports what changed since the last run, read from the
+// informer caches. The first run and every fullSyncEvery-th run emit
+// the full cache as a non-partial baseline so absence tracking still
+// sees departures.
+func (c *Collector) collectStream(ctx context.Context) (model.CollectResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.strm == nil {
+		s, err := c.startStream(ctx)
+		if err != nil {
+			return model.CollectResult{}, err
+		}
+		c.strm = s
+	}
+	s := c.strm
+	if s.runs >= c.fullSyncEvery || s.runs == 0 {
+		s.drain() // superseded by the snapshot
+		s.runs = 1
+		return c.cacheSnapshot(s)
+	}
+	s.runs++
+
+	var res model.CollectResult
+	res.Partial = true
+	keys := s.drain()
+	res.Stats.Fetched = len(keys)
+	for _, k := range keys {
+		if !c.emitCached(s, k, &res) {
+			// Gone from the cache: deleted. Departures surface as
+			// absences at the next baseline; a partial run cannot
+			// report them.
+			res.Stats.Filtered++
+		}
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// emitCached looks one changed object up in the informer caches and
+// appends its asset and edges; it reports false when the object no
+// longer exists.
+func (c *Collector) emitCached(s *stream, k objKey, res *model.CollectResult) bool {
+	switch k.kind {
+	case "node":
+		n, err := s.nodes.Get(k.name)
+		if err != nil {
+			return false
+		}
+		res.Assets = append(res.Assets, c.nodeAsset(n))
+	case "namespace":
+		ns, err := s.namespaces.Get(k.name)
+		if err != nil {
+			return false
+		}
+		res.Assets = append(res.Assets, c.namespaceAsset(ns))
+	case "pod":
+		p, err := s.pods.Pods(k.namespace).Get(k.name)
+		if err != nil {
+			return false
+		}
+		a, rels := c.podAsset(p)
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, rels...)
+	case "service":
+		svc, err := s.services.Services(k.namespace).Get(k.name)
+		if err != nil {
+			return false
+		}
+		a, rels := c.serviceAsset(svc)
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, rels...)
+	}
+	return true
+}
+
+// cacheSnapshot emits everything currently in the informer caches as a
+// full baseline.
+func (c *Collector) cacheSnapshot(s *stream) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	nodes, err := s.nodes.List(labels.Everything())
+	if err != nil {
+		return res, fmt.Errorf("k8s: list cached nodes: %w", err)
+	}
+	for _, n := range nodes {
+		res.Assets = append(res.Assets, c.nodeAsset(n))
+	}
+	namespaces, err := s.namespaces.List(labels.Everything())
+	if err != nil {
+		return res, fmt.Errorf("k8s: list cached namespaces: %w", err)
+	}
+	for _, ns := range namespaces {
+		res.Assets = append(res.Assets, c.namespaceAsset(ns))
+	}
+	pods, err := s.pods.List(labels.Everything())
+	if err != nil {
+		return res, fmt.Errorf("k8s: list cached pods: %w", err)
+	}
+	for _, p := range pods {
+		a, rels := c.podAsset(p)
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, rels...)
+	}
+	services, err := s.services.List(labels.Everything())
+	if err != nil {
+		return res, fmt.Errorf("k8s: list cached services: %w", err)
+	}
+	for _, svc := range services {
+		a, rels := c.serviceAsset(svc)
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, rels...)
+	}
+
+	res.Stats.Fetched = len(nodes) + len(namespaces) + len(pods) + len(services)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
@@ -0,0 +1,197 @@
+// Package kafka imports one Kafka cluster: brokers, topics and
+// consumer groups become assets under a cluster asset with CONTAINS
+// edges. Internal topics (the __consumer_offsets family) are skipped;
+// they are plumbing, not inventory. Broker hosts carry their
+// advertised addresses so they fold onto the machines other sources
+// already know.
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/IBM/sarama"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("kafka", New)
+}
+
+// Collector reads one cluster through the admin API.
+type Collector struct {
+	name    string
+	brokers []string
+	cluster string
+	saramaC *sarama.Config
+}
+
+// New builds the collector from config. Required setting: brokers (a
+// comma-separated bootstrap list). Optional: cluster (a label used to
+// prefix external IDs, defaulting to the collector name), username
+// and password (SASL/PLAIN).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:    cfg.Name,
+		cluster: cfg.Settings["cluster"],
+	}
+	for _, b := range strings.Split(cfg.Settings["brokers"], ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			c.brokers = append(c.brokers, b)
+		}
+	}
+	if len(c.brokers) == 0 {
+		return nil, fmt.Errorf("kafka: brokers setting is required")
+	}
+	if c.cluster == "" {
+		c.cluster = cfg.Name
+	}
+	sc := sarama.NewConfig()
+	sc.ClientID = "arch"
+	if user := cfg.Settings["username"]; user != "" {
+		sc.Net.SASL.Enable = true
+		sc.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		sc.Net.SASL.User = user
+		sc.Net.SASL.Password = cfg.Settings["password"]
+	}
+	c.saramaC = sc
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) extID(kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s", c.cluster, kind, name)
+}
+
+// Collect connects to the cluster and lists brokers, topics and
+// consumer groups. The context is honored between calls; sarama's own
+// dial and request timeouts bound the individual operations.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	admin, err := sarama.NewClusterAdmin(c.brokers, c.saramaC)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("kafka: connect: %w", err)
+	}
+	defer admin.Close()
+
+	var res model.CollectResult
+	clusterID := c.extID("cluster", c.cluster)
+	res.Assets = append(res.Assets, &model.Asset{
+		Name:       c.cluster,
+		AssetType:  "kafka_cluster",
+		ExternalID: clusterID,
+		Attributes: map[string]any{"bootstrap": strings.Join(c.brokers, ",")},
+	})
+
+	brokers, controllerID, err := admin.DescribeCluster()
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("kafka: describe cluster: %w", err)
+	}
+	for _, b := range brokers {
+		if err := ctx.Err(); err != nil {
+			return model.CollectResult{}, err
+		}
+		id := c.extID("broker", fmt.Sprint(b.ID()))
+		a := &model.Asset{
+			Name:       b.Addr(),
+			AssetType:  "kafka_broker",
+			ExternalID: id,
+			Attributes: map[string]any{
+				"broker_id":  b.ID(),
+				"controller": b.ID() == controllerID,
+			},
+		}
+		if host, _, err := net.SplitHostPort(b.Addr()); err == nil {
+			a.Name = host
+			if net.ParseIP(host) != nil {
+				a.IPs = []string{host}
+			} else if strings.Contains(host, ".") {
+				a.FQDN = host
+			}
+		}
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: clusterID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	topics, err := admin.ListTopics()
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("kafka: list topics: %w", err)
+	}
+	names := make([]string, 0, len(topics))
+	for name := range topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		res.Stats.Fetched++
+		if strings.HasPrefix(name, "__") {
+			res.Stats.Filtered++
+			continue
+		}
+		detail := topics[name]
+		id := c.extID("topic", name)
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       name,
+			AssetType:  "kafka_topic",
+			ExternalID: id,
+			Attributes: map[string]any{
+				"partitions":         detail.NumPartitions,
+				"replication_factor": detail.ReplicationFactor,
+			},
+		})
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: clusterID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	groups, err := admin.ListConsumerGroups()
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("kafka: list consumer groups: %w", err)
+	}
+	groupNames := make([]string, 0, len(groups))
+	for name := range groups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		id := c.extID("group", name)
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       name,
+			AssetType:  "kafka_consumer_group",
+			ExternalID: id,
+			Attributes: map[string]any{"protocol_type": groups[name]},
+		})
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: clusterID,
+			ToExternalID:   id,
+			Type:           model.RelContains,
+		})
+	}
+
+	res.Stats.Fetched += len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "brokers", Type: collector.TypeList, Required: true, Description: "bootstrap brokers"},
+		{Name: "cluster", Type: collector.TypeString, Description: "label prefixed to external IDs, defaults to the collector name"},
+		{Name: "username", Type: collector.TypeString, Description: "SASL/PLAIN user"},
+		{Name: "password", Type: collector.TypeString, Description: "SASL/PLAIN password"},
+	}
+}
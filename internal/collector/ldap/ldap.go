@@ -0,0 +1,205 @@
+// Package ldap imports computer objects from Active Directory or
+// OpenLDAP as host assets. The directory's structure is preserved:
+// each computer is linked to its organizational unit, OUs to their
+// parents, and group memberships (memberOf) become MEMBER_OF edges to
+// group assets.
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ldapv3 "github.com/go-ldap/ldap/v3"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("ldap", New)
+}
+
+// Collector binds to one directory server.
+type Collector struct {
+	name     string
+	url      string
+	bindDN   string
+	bindPass string
+	baseDN   string
+	filter   string
+}
+
+// New builds the collector from config. Required settings: url
+// (ldap:// or ldaps://), bind_dn, bind_password, base_dn. Optional:
+// filter (defaults to AD's "(objectClass=computer)"; OpenLDAP setups
+// typically want "(objectClass=device)" or similar).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		url:      cfg.Settings["url"],
+		bindDN:   cfg.Settings["bind_dn"],
+		bindPass: cfg.Settings["bind_password"],
+		baseDN:   cfg.Settings["base_dn"],
+		filter:   cfg.Settings["filter"],
+	}
+	if c.url == "" || c.bindDN == "" || c.bindPass == "" || c.baseDN == "" {
+		return nil, fmt.Errorf("ldap: url, bind_dn, bind_password and base_dn settings are required")
+	}
+	if c.filter == "" {
+		c.filter = "(objectClass=computer)"
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// computerAttrs are the attributes requested per computer object.
+var computerAttrs = []string{
+	"cn", "dNSHostName", "operatingSystem", "operatingSystemVersion",
+	"description", "memberOf",
+}
+
+// Collect binds and pages through matching computer objects. External
+// IDs are lowercased DNs, which are unique within the directory and
+// stable until an object moves.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	conn, err := ldapv3.DialURL(c.url)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("ldap: dial: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.Bind(c.bindDN, c.bindPass); err != nil {
+		return model.CollectResult{}, fmt.Errorf("ldap: bind: %w", err)
+	}
+	req := ldapv3.NewSearchRequest(
+		c.baseDN, ldapv3.ScopeWholeSubtree, ldapv3.NeverDerefAliases,
+		0, 0, false, c.filter, computerAttrs, nil)
+	sr, err := conn.SearchWithPaging(req, 500)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("ldap: search: %w", err)
+	}
+
+	var res model.CollectResult
+	res.Stats.Fetched = len(sr.Entries)
+	// containers tracks emitted OU and group assets by lowercased DN.
+	containers := map[string]bool{}
+	for _, entry := range sr.Entries {
+		a := entryToAsset(entry)
+		res.Assets = append(res.Assets, a)
+		c.linkContainers(entry, a, containers, &res)
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func entryToAsset(entry *ldapv3.Entry) *model.Asset {
+	a := &model.Asset{
+		Name:       entry.GetAttributeValue("cn"),
+		AssetType:  "host",
+		ExternalID: strings.ToLower(entry.DN),
+		Attributes: map[string]any{},
+	}
+	if fqdn := entry.GetAttributeValue("dNSHostName"); fqdn != "" {
+		a.FQDN = fqdn
+		a.Name = fqdn
+	}
+	if os := entry.GetAttributeValue("operatingSystem"); os != "" {
+		if v := entry.GetAttributeValue("operatingSystemVersion"); v != "" {
+			os += " " + v
+		}
+		a.Attributes["os"] = os
+	}
+	if desc := entry.GetAttributeValue("description"); desc != "" {
+		a.Attributes["description"] = desc
+	}
+	return a
+}
+
+// linkContainers emits the OU chain above the computer and its group
+// memberships. Each container asset is emitted once per run.
+func (c *Collector) linkContainers(entry *ldapv3.Entry, a *model.Asset, containers map[string]bool, res *model.CollectResult) {
+	child := a.ExternalID
+	for _, ou := range ouChain(entry.DN) {
+		id := strings.ToLower(ou.dn)
+		if !containers[id] {
+			containers[id] = true
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       ou.name,
+				AssetType:  "organizational_unit",
+				ExternalID: id,
+				Attributes: map[string]any{},
+			})
+		}
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: child,
+			ToExternalID:   id,
+			Type:           model.RelMemberOf,
+		})
+		child = id
+	}
+	for _, groupDN := range entry.GetAttributeValues("memberOf") {
+		id := strings.ToLower(groupDN)
+		if !containers[id] {
+			containers[id] = true
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       firstRDNValue(groupDN),
+				AssetType:  "directory_group",
+				ExternalID: id,
+				Attributes: map[string]any{},
+			})
+		}
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: a.ExternalID,
+			ToExternalID:   id,
+			Type:           model.RelMemberOf,
+		})
+	}
+}
+
+type ouRef struct {
+	name string
+	dn   string
+}
+
+// ouChain returns the OUs above a DN, innermost first, each with the
+// full DN it sits at. Domain components terminate the chain.
+func ouChain(dn string) []ouRef {
+	parsed, err := ldapv3.ParseDN(dn)
+	if err != nil {
+		return nil
+	}
+	var chain []ouRef
+	for i, rdn := range parsed.RDNs {
+		if len(rdn.Attributes) == 0 || !strings.EqualFold(rdn.Attributes[0].Type, "OU") {
+			continue
+		}
+		var parts []string
+		for _, r := range parsed.RDNs[i:] {
+			parts = append(parts, r.String())
+		}
+		chain = append(chain, ouRef{name: rdn.Attributes[0].Value, dn: strings.Join(parts, ",")})
+	}
+	return chain
+}
+
+func firstRDNValue(dn string) string {
+	parsed, err := ldapv3.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return dn
+	}
+	return parsed.RDNs[0].Attributes[0].Value
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "directory URL"},
+		{Name: "bind_dn", Type: collector.TypeString, Required: true, Description: "bind DN"},
+		{Name: "bind_password", Type: collector.TypeString, Required: true, Description: "bind password"},
+		{Name: "base_dn", Type: collector.TypeString, Required: true, Description: "search base"},
+		{Name: "filter", Type: collector.TypeString, Description: "search filter override"},
+	}
+}
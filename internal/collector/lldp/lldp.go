@@ -0,0 +1,279 @@
+// Package lldp reads LLDP and CDP neighbor tables from switches over
+// SNMP and turns them into CONNECTS_TO edges between network devices
+// and whatever answers on the far end of each port — other switches,
+// routers or server NICs. This is what puts physical topology behind
+// the graph endpoints; the snmp collector only sees who a device has
+// exchanged packets with, not what is actually cabled where.
+package lldp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("lldp", New)
+}
+
+// Collector polls neighbor tables from a fixed list of switches.
+type Collector struct {
+	name      string
+	devices   []string
+	community string
+	timeout   time.Duration
+}
+
+// New builds the collector from config. Required settings: devices (a
+// comma-separated list of host or host:port) and community (v2c).
+// Optional: timeout (Go duration, default 5s).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:      cfg.Name,
+		community: cfg.Settings["community"],
+		timeout:   5 * time.Second,
+	}
+	for _, d := range strings.Split(cfg.Settings["devices"], ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			c.devices = append(c.devices, d)
+		}
+	}
+	if len(c.devices) == 0 || c.community == "" {
+		return nil, fmt.Errorf("lldp: devices and community settings are required")
+	}
+	if raw := cfg.Settings["timeout"]; raw != "" {
+		t, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("lldp: invalid timeout %q: %w", raw, err)
+		}
+		c.timeout = t
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+const (
+	oidSysName = ".1.3.6.1.2.1.1.5.0"
+	// LLDP-MIB lldpRemTable columns, indexed by
+	// (timeMark, localPortNum, remIndex).
+	oidLldpRemPortID   = ".1.0.8802.1.1.2.1.4.1.1.7"
+	oidLldpRemPortDesc = ".1.0.8802.1.1.2.1.4.1.1.8"
+	oidLldpRemSysName  = ".1.0.8802.1.1.2.1.4.1.1.9"
+	// CISCO-CDP-MIB cdpCacheTable columns, indexed by
+	// (ifIndex, deviceIndex).
+	oidCdpCacheDeviceID   = ".1.3.6.1.4.1.9.9.23.1.2.1.1.6"
+	oidCdpCacheDevicePort = ".1.3.6.1.4.1.9.9.23.1.2.1.1.7"
+)
+
+// neighbor is one row of a device's LLDP or CDP table.
+type neighbor struct {
+	sysName    string
+	remotePort string
+	localPort  string
+	protocol   string
+}
+
+// Collect polls every device, preferring LLDP and falling back to CDP
+// on Cisco gear with LLDP disabled. Devices and neighbors are keyed by
+// system name, which is what both protocols advertise; neighbors are
+// emitted as thin assets so richer sources can fill them in.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+	res.Stats.Fetched = len(c.devices)
+	emitted := map[string]bool{}
+	var lastErr error
+	for _, device := range c.devices {
+		if err := ctx.Err(); err != nil {
+			return model.CollectResult{}, err
+		}
+		if err := c.collectDevice(device, emitted, &res); err != nil {
+			res.Stats.Filtered++
+			lastErr = fmt.Errorf("lldp: %s: %w", device, err)
+		}
+	}
+	if res.Stats.Filtered == len(c.devices) {
+		return model.CollectResult{}, lastErr
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func (c *Collector) collectDevice(device string, emitted map[string]bool, res *model.CollectResult) error {
+	host, port := device, uint16(161)
+	if h, p, err := net.SplitHostPort(device); err == nil {
+		host = h
+		if n, err := strconv.Atoi(p); err == nil {
+			port = uint16(n)
+		}
+	}
+	client := &gosnmp.GoSNMP{
+		Target:    host,
+		Port:      port,
+		Community: c.community,
+		Version:   gosnmp.Version2c,
+		Timeout:   c.timeout,
+		Retries:   1,
+	}
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Conn.Close()
+
+	sys, err := client.Get([]string{oidSysName})
+	if err != nil || len(sys.Variables) == 0 {
+		return fmt.Errorf("sysName: %w", err)
+	}
+	sysName := pduString(sys.Variables[0])
+	if sysName == "" {
+		sysName = host
+	}
+	if !emitted[sysName] {
+		emitted[sysName] = true
+		a := &model.Asset{
+			Name:       sysName,
+			AssetType:  "switch",
+			ExternalID: sysName,
+			Attributes: map[string]any{},
+		}
+		if net.ParseIP(host) != nil {
+			a.IPs = []string{host}
+		}
+		res.Assets = append(res.Assets, a)
+	}
+
+	neighbors, err := walkLLDP(client)
+	if err != nil || len(neighbors) == 0 {
+		neighbors, err = walkCDP(client)
+		if err != nil {
+			return fmt.Errorf("neighbor tables: %w", err)
+		}
+	}
+	for _, n := range neighbors {
+		if n.sysName == "" {
+			continue
+		}
+		if !emitted[n.sysName] {
+			emitted[n.sysName] = true
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       n.sysName,
+				AssetType:  "host",
+				ExternalID: n.sysName,
+				Attributes: map[string]any{},
+			})
+		}
+		props := map[string]any{"protocol": n.protocol}
+		if n.localPort != "" {
+			props["local_port"] = n.localPort
+		}
+		if n.remotePort != "" {
+			props["remote_port"] = n.remotePort
+		}
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: sysName,
+			ToExternalID:   n.sysName,
+			Type:           model.RelConnectsTo,
+			Properties:     props,
+		})
+	}
+	return nil
+}
+
+// walkLLDP folds the lldpRemTable columns into neighbors keyed by
+// their (localPort, remIndex) row index.
+func walkLLDP(client *gosnmp.GoSNMP) (map[string]*neighbor, error) {
+	neighbors := map[string]*neighbor{}
+	at := func(oid, column string) *neighbor {
+		key := strings.TrimPrefix(oid, column)
+		if neighbors[key] == nil {
+			neighbors[key] = &neighbor{protocol: "lldp"}
+			// The middle index component is the local port number.
+			if parts := strings.Split(strings.Trim(key, "."), "."); len(parts) == 3 {
+				neighbors[key].localPort = parts[1]
+			}
+		}
+		return neighbors[key]
+	}
+	err := client.Walk(oidLldpRemSysName, func(pdu gosnmp.SnmpPDU) error {
+		at(pdu.Name, oidLldpRemSysName).sysName = pduString(pdu)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	client.Walk(oidLldpRemPortDesc, func(pdu gosnmp.SnmpPDU) error {
+		at(pdu.Name, oidLldpRemPortDesc).remotePort = pduString(pdu)
+		return nil
+	})
+	client.Walk(oidLldpRemPortID, func(pdu gosnmp.SnmpPDU) error {
+		n := at(pdu.Name, oidLldpRemPortID)
+		if n.remotePort == "" {
+			n.remotePort = pduString(pdu)
+		}
+		return nil
+	})
+	return neighbors, nil
+}
+
+// walkCDP folds the cdpCacheTable columns into neighbors keyed by
+// their (ifIndex, deviceIndex) row index.
+func walkCDP(client *gosnmp.GoSNMP) (map[string]*neighbor, error) {
+	neighbors := map[string]*neighbor{}
+	at := func(oid, column string) *neighbor {
+		key := strings.TrimPrefix(oid, column)
+		if neighbors[key] == nil {
+			neighbors[key] = &neighbor{protocol: "cdp"}
+			if parts := strings.Split(strings.Trim(key, "."), "."); len(parts) == 2 {
+				neighbors[key].localPort = parts[0]
+			}
+		}
+		return neighbors[key]
+	}
+	err := client.Walk(oidCdpCacheDeviceID, func(pdu gosnmp.SnmpPDU) error {
+		// CDP advertises "name(serial)" on some platforms; keep the
+		// name.
+		name := pduString(pdu)
+		if i := strings.IndexByte(name, '('); i > 0 {
+			name = name[:i]
+		}
+		at(pdu.Name, oidCdpCacheDeviceID).sysName = name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	client.Walk(oidCdpCacheDevicePort, func(pdu gosnmp.SnmpPDU) error {
+		at(pdu.Name, oidCdpCacheDevicePort).remotePort = pduString(pdu)
+		return nil
+	})
+	return neighbors, nil
+}
+
+func pduString(pdu gosnmp.SnmpPDU) string {
+	switch v := pdu.Value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "devices", Type: collector.TypeList, Required: true, Description: "switches to walk"},
+		{Name: "community", Type: collector.TypeString, Required: true, Description: "SNMP community"},
+		{Name: "timeout", Type: collector.TypeDuration, Description: "per-device timeout"},
+	}
+}
@@ -0,0 +1,111 @@
+// Package mdm imports managed end-user devices from an endpoint
+// management system — Microsoft Intune via the Graph API, or SCCM via
+// its AdminService REST API. Laptops and desktops rarely show up in
+// infrastructure sources at all, so this is usually their only feed;
+// devices arrive with compliance state, assigned owner and hardware
+// attributes.
+package mdm
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("mdm", New)
+}
+
+// Collector reads one management system through a backend-specific
+// API client.
+type Collector struct {
+	name    string
+	backend string
+	// Intune authenticates as an app registration.
+	tenantID     string
+	clientID     string
+	clientSecret string
+	// SCCM AdminService uses the site server URL with basic auth.
+	url      string
+	username string
+	password string
+	client   *http.Client
+
+	// token caches the Graph bearer token across runs until it expires.
+	token       string
+	tokenExpiry time.Time
+}
+
+// New builds the collector from config. Required setting: backend
+// ("intune" or "sccm"). Intune needs tenant_id, client_id and
+// client_secret for an app registration with
+// DeviceManagementManagedDevices.Read.All. SCCM needs url (the site
+// server's AdminService base URL), username and password; optional
+// insecure ("true") skips TLS verification for self-signed site
+// certificates.
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:         cfg.Name,
+		backend:      cfg.Settings["backend"],
+		tenantID:     cfg.Settings["tenant_id"],
+		clientID:     cfg.Settings["client_id"],
+		clientSecret: cfg.Settings["client_secret"],
+		url:          strings.TrimSuffix(cfg.Settings["url"], "/"),
+		username:     cfg.Settings["username"],
+		password:     cfg.Settings["password"],
+	}
+	switch c.backend {
+	case "intune":
+		if c.tenantID == "" || c.clientID == "" || c.clientSecret == "" {
+			return nil, fmt.Errorf("mdm: intune backend requires tenant_id, client_id and client_secret settings")
+		}
+	case "sccm":
+		if c.url == "" || c.username == "" || c.password == "" {
+			return nil, fmt.Errorf("mdm: sccm backend requires url, username and password settings")
+		}
+	default:
+		return nil, fmt.Errorf("mdm: backend setting must be \"intune\" or \"sccm\", got %q", c.backend)
+	}
+	transport := &http.Transport{}
+	if cfg.Settings["insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c.client = &http.Client{Timeout: 60 * time.Second, Transport: transport}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect dispatches to the configured backend. Both produce
+// "endpoint" assets so managed devices stay distinguishable from
+// server-class hosts.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	switch c.backend {
+	case "intune":
+		return c.collectIntune(ctx)
+	default:
+		return c.collectSCCM(ctx)
+	}
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "backend", Type: collector.TypeString, Required: true, Description: "\"intune\" or \"sccm\""},
+		{Name: "tenant_id", Type: collector.TypeString, Description: "Entra tenant (intune backend)"},
+		{Name: "client_id", Type: collector.TypeString, Description: "app registration (intune backend)"},
+		{Name: "client_secret", Type: collector.TypeString, Description: "app secret (intune backend)"},
+		{Name: "url", Type: collector.TypeString, Description: "AdminService base URL (sccm backend)"},
+		{Name: "username", Type: collector.TypeString, Description: "API user (sccm backend)"},
+		{Name: "password", Type: collector.TypeString, Description: "API password (sccm backend)"},
+		{Name: "insecure", Type: collector.TypeBool, Description: "skip TLS verification (sccm backend)"},
+	}
+}
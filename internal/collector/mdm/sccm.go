@@ -0,0 +1,108 @@
+package mdm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// sccmDevice is one row of the AdminService Device entity set.
+type sccmDevice struct {
+	MachineID        int64  `json:"MachineId"`
+	Name             string `json:"Name"`
+	Domain           string `json:"Domain"`
+	DeviceOS         string `json:"DeviceOS"`
+	DeviceOSBuild    string `json:"DeviceOSBuild"`
+	PrimaryUser      string `json:"PrimaryUser"`
+	CurrentLogonUser string `json:"CurrentLogonUser"`
+	SerialNumber     string `json:"SerialNumber"`
+	IsVirtualMachine bool   `json:"IsVirtualMachine"`
+	ComplianceState  int    `json:"ComplianceState"`
+	LastActiveTime   string `json:"LastActiveTime"`
+}
+
+// collectSCCM queries the AdminService OData endpoint. The service
+// returns the full device list in one response; site databases rarely
+// exceed a few tens of thousands of rows.
+func (c *Collector) collectSCCM(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/v1.0/Device", nil)
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("mdm: adminservice request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return model.CollectResult{}, fmt.Errorf("mdm: adminservice returned status %d", resp.StatusCode)
+	}
+	var page struct {
+		Value []sccmDevice `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return model.CollectResult{}, fmt.Errorf("mdm: decode device list: %w", err)
+	}
+	for _, d := range page.Value {
+		res.Assets = append(res.Assets, sccmToAsset(d))
+	}
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func sccmToAsset(d sccmDevice) *model.Asset {
+	a := &model.Asset{
+		Name:       d.Name,
+		AssetType:  "endpoint",
+		ExternalID: fmt.Sprintf("device/%d", d.MachineID),
+		Attributes: map[string]any{
+			"compliance": sccmCompliance(d.ComplianceState),
+		},
+	}
+	if d.Domain != "" && d.Name != "" {
+		a.FQDN = strings.ToLower(d.Name + "." + d.Domain)
+	}
+	if d.DeviceOS != "" {
+		os := d.DeviceOS
+		if d.DeviceOSBuild != "" {
+			os += " " + d.DeviceOSBuild
+		}
+		a.Attributes["os"] = os
+	}
+	owner := d.PrimaryUser
+	if owner == "" {
+		owner = d.CurrentLogonUser
+	}
+	if owner != "" {
+		a.Attributes["owner"] = owner
+	}
+	a.SerialNumber = d.SerialNumber
+	if d.IsVirtualMachine {
+		a.Attributes["virtual"] = true
+	}
+	if d.LastActiveTime != "" {
+		a.Attributes["last_active"] = d.LastActiveTime
+	}
+	return a
+}
+
+// sccmCompliance maps the numeric ComplianceState to the words Intune
+// uses, so both backends report the attribute the same way.
+func sccmCompliance(state int) string {
+	switch state {
+	case 1:
+		return "compliant"
+	case 2:
+		return "noncompliant"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,192 @@
+// Package mikrotik imports a MikroTik router through the RouterOS v7
+// REST API: the router itself plus the clients behind it, taken from
+// the DHCP lease table (hostname, MAC, IP) with ARP as a fallback for
+// statically addressed machines. Clients get CONNECTS_TO edges to the
+// router. Like the unifi collector, this exists for branch sites
+// where nothing else inventories what is on the LAN.
+package mikrotik
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("mikrotik", New)
+}
+
+// Collector reads one router.
+type Collector struct {
+	name     string
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+// New builds the collector from config. Required settings: url (the
+// router base URL, e.g. https://192.0.2.1), username, password.
+// Optional: insecure ("true" skips TLS verification for the router's
+// self-signed certificate).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		url:      strings.TrimSuffix(cfg.Settings["url"], "/"),
+		username: cfg.Settings["username"],
+		password: cfg.Settings["password"],
+	}
+	if c.url == "" || c.username == "" || c.password == "" {
+		return nil, fmt.Errorf("mikrotik: url, username and password settings are required")
+	}
+	transport := &http.Transport{}
+	if cfg.Settings["insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c.client = &http.Client{Timeout: 60 * time.Second, Transport: transport}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/rest"+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mikrotik: %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("mikrotik: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// Collect reads the router identity and resources, then builds the
+// client list from DHCP leases and ARP. External IDs are MACs, same
+// as the unifi collector, so the same laptop roaming between sites
+// still matches.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	var identity struct {
+		Name string `json:"name"`
+	}
+	if err := c.get(ctx, "/system/identity", &identity); err != nil {
+		return model.CollectResult{}, err
+	}
+	var resource struct {
+		BoardName string `json:"board-name"`
+		Version   string `json:"version"`
+	}
+	if err := c.get(ctx, "/system/resource", &resource); err != nil {
+		return model.CollectResult{}, err
+	}
+	routerID := "router/" + identity.Name
+	res.Assets = append(res.Assets, &model.Asset{
+		Name:       identity.Name,
+		AssetType:  "router",
+		ExternalID: routerID,
+		Attributes: map[string]any{
+			"vendor":  "mikrotik",
+			"model":   resource.BoardName,
+			"version": resource.Version,
+		},
+	})
+
+	emitted := map[string]*model.Asset{}
+	emit := func(mac, ip, hostname string) {
+		mac = strings.ToUpper(mac)
+		if mac == "" {
+			return
+		}
+		if a := emitted[mac]; a != nil {
+			// ARP confirming a lease adds nothing; a lease after ARP
+			// adds the hostname.
+			if hostname != "" && a.Name == mac {
+				a.Name = hostname
+			}
+			return
+		}
+		name := hostname
+		if name == "" {
+			name = mac
+		}
+		a := &model.Asset{
+			Name:       name,
+			AssetType:  "host",
+			ExternalID: mac,
+			MACs:       []string{mac},
+		}
+		if net.ParseIP(ip) != nil {
+			a.IPs = []string{ip}
+		}
+		emitted[mac] = a
+		res.Assets = append(res.Assets, a)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: mac,
+			ToExternalID:   routerID,
+			Type:           model.RelConnectsTo,
+		})
+	}
+
+	var leases []struct {
+		Address  string `json:"address"`
+		MAC      string `json:"mac-address"`
+		HostName string `json:"host-name"`
+		Status   string `json:"status"`
+	}
+	if err := c.get(ctx, "/ip/dhcp-server/lease", &leases); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, l := range leases {
+		res.Stats.Fetched++
+		if l.Status != "bound" {
+			res.Stats.Filtered++
+			continue
+		}
+		emit(l.MAC, l.Address, l.HostName)
+	}
+
+	var arp []struct {
+		Address string `json:"address"`
+		MAC     string `json:"mac-address"`
+	}
+	if err := c.get(ctx, "/ip/arp", &arp); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, e := range arp {
+		res.Stats.Fetched++
+		emit(e.MAC, e.Address, "")
+	}
+
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "RouterOS REST base URL"},
+		{Name: "username", Type: collector.TypeString, Required: true, Description: "API user"},
+		{Name: "password", Type: collector.TypeString, Required: true, Description: "API password"},
+		{Name: "insecure", Type: collector.TypeBool, Description: "skip TLS verification"},
+	}
+}
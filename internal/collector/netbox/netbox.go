@@ -0,0 +1,368 @@
+// Package netbox seeds the inventory from a NetBox instance: sites,
+// racks, devices, virtual machines and prefixes become assets, with
+// the physical hierarchy preserved as CONTAINS edges (site > rack >
+// device, site > prefix, site > VM). IP address records are folded
+// onto the device or VM their interface belongs to rather than emitted
+// as separate assets.
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("netbox", New)
+}
+
+// Collector reads one NetBox instance.
+type Collector struct {
+	name   string
+	url    string
+	token  string
+	client *http.Client
+}
+
+// New builds the collector from config. Required settings: url (the
+// NetBox base URL) and token (an API token with read access).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:   cfg.Name,
+		url:    strings.TrimSuffix(cfg.Settings["url"], "/"),
+		token:  cfg.Settings["token"],
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+	if c.url == "" || c.token == "" {
+		return nil, fmt.Errorf("netbox: url and token settings are required")
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// getAll follows NetBox's cursor pagination, appending every results
+// page into out via the handler.
+func (c *Collector) getAll(ctx context.Context, path string, handle func(json.RawMessage) error) error {
+	url := c.url + path
+	for url != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Token "+c.token)
+		req.Header.Set("Accept", "application/json")
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return err
+		}
+		var page struct {
+			Next    *string           `json:"next"`
+			Results []json.RawMessage `json:"results"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("netbox: %s returned status %d", path, resp.StatusCode)
+		}
+		if err != nil {
+			return fmt.Errorf("netbox: decode %s: %w", path, err)
+		}
+		for _, raw := range page.Results {
+			if err := handle(raw); err != nil {
+				return err
+			}
+		}
+		url = ""
+		if page.Next != nil {
+			url = *page.Next
+		}
+	}
+	return nil
+}
+
+// ref is NetBox's nested-object representation.
+type ref struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type ipRef struct {
+	Address string `json:"address"`
+}
+
+// Collect walks the DCIM, virtualization and IPAM endpoints. External
+// IDs are "<kind>/<numeric id>" — NetBox IDs are stable, names are
+// not.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	err := c.getAll(ctx, "/api/dcim/sites/?limit=200", func(raw json.RawMessage) error {
+		var site struct {
+			ID     int64  `json:"id"`
+			Name   string `json:"name"`
+			Slug   string `json:"slug"`
+			Status struct {
+				Value string `json:"value"`
+			} `json:"status"`
+		}
+		if err := json.Unmarshal(raw, &site); err != nil {
+			return err
+		}
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       site.Name,
+			AssetType:  "site",
+			ExternalID: fmt.Sprintf("site/%d", site.ID),
+			Attributes: map[string]any{"slug": site.Slug, "status": site.Status.Value},
+		})
+		return nil
+	})
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+
+	err = c.getAll(ctx, "/api/dcim/racks/?limit=200", func(raw json.RawMessage) error {
+		var rack struct {
+			ID      int64  `json:"id"`
+			Name    string `json:"name"`
+			Site    *ref   `json:"site"`
+			UHeight int    `json:"u_height"`
+		}
+		if err := json.Unmarshal(raw, &rack); err != nil {
+			return err
+		}
+		id := fmt.Sprintf("rack/%d", rack.ID)
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       rack.Name,
+			AssetType:  "rack",
+			ExternalID: id,
+			Attributes: map[string]any{"u_height": rack.UHeight},
+		})
+		if rack.Site != nil {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: fmt.Sprintf("site/%d", rack.Site.ID),
+				ToExternalID:   id,
+				Type:           model.RelContains,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+
+	// assetByNetboxID lets the IP address pass below attach addresses
+	// to the device or VM that owns the interface.
+	deviceAssets := map[string]*model.Asset{}
+	err = c.getAll(ctx, "/api/dcim/devices/?limit=200", func(raw json.RawMessage) error {
+		var dev struct {
+			ID         int64  `json:"id"`
+			Name       string `json:"name"`
+			Site       *ref   `json:"site"`
+			Rack       *ref   `json:"rack"`
+			DeviceType struct {
+				Model        string `json:"model"`
+				Manufacturer ref    `json:"manufacturer"`
+			} `json:"device_type"`
+			Role   ref    `json:"role"`
+			Serial string `json:"serial"`
+			Status struct {
+				Value string `json:"value"`
+			} `json:"status"`
+			PrimaryIP4 *ipRef `json:"primary_ip4"`
+		}
+		if err := json.Unmarshal(raw, &dev); err != nil {
+			return err
+		}
+		id := fmt.Sprintf("device/%d", dev.ID)
+		a := &model.Asset{
+			Name:       dev.Name,
+			AssetType:  "host",
+			ExternalID: id,
+			Attributes: map[string]any{
+				"model":        dev.DeviceType.Model,
+				"manufacturer": dev.DeviceType.Manufacturer.Name,
+				"role":         dev.Role.Name,
+				"status":       dev.Status.Value,
+			},
+		}
+		a.SerialNumber = dev.Serial
+		if dev.PrimaryIP4 != nil {
+			a.IPs = append(a.IPs, stripPrefixLen(dev.PrimaryIP4.Address))
+		}
+		deviceAssets["device/"+fmt.Sprint(dev.ID)] = a
+		res.Assets = append(res.Assets, a)
+		parent := ""
+		if dev.Rack != nil {
+			parent = fmt.Sprintf("rack/%d", dev.Rack.ID)
+		} else if dev.Site != nil {
+			parent = fmt.Sprintf("site/%d", dev.Site.ID)
+		}
+		if parent != "" {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: parent,
+				ToExternalID:   id,
+				Type:           model.RelContains,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+
+	err = c.getAll(ctx, "/api/virtualization/virtual-machines/?limit=200", func(raw json.RawMessage) error {
+		var vm struct {
+			ID      int64   `json:"id"`
+			Name    string  `json:"name"`
+			Site    *ref    `json:"site"`
+			Cluster *ref    `json:"cluster"`
+			VCPUs   float64 `json:"vcpus"`
+			Memory  int     `json:"memory"`
+			Status  struct {
+				Value string `json:"value"`
+			} `json:"status"`
+			PrimaryIP4 *ipRef `json:"primary_ip4"`
+		}
+		if err := json.Unmarshal(raw, &vm); err != nil {
+			return err
+		}
+		id := fmt.Sprintf("vm/%d", vm.ID)
+		a := &model.Asset{
+			Name:       vm.Name,
+			AssetType:  "vm",
+			ExternalID: id,
+			Attributes: map[string]any{"status": vm.Status.Value},
+		}
+		if vm.Cluster != nil {
+			a.Attributes["cluster"] = vm.Cluster.Name
+		}
+		if vm.VCPUs > 0 {
+			a.Attributes["cpu_count"] = int(vm.VCPUs)
+		}
+		if vm.Memory > 0 {
+			a.Attributes["memory_mb"] = vm.Memory
+		}
+		if vm.PrimaryIP4 != nil {
+			a.IPs = append(a.IPs, stripPrefixLen(vm.PrimaryIP4.Address))
+		}
+		deviceAssets["vm/"+fmt.Sprint(vm.ID)] = a
+		res.Assets = append(res.Assets, a)
+		if vm.Site != nil {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: fmt.Sprintf("site/%d", vm.Site.ID),
+				ToExternalID:   id,
+				Type:           model.RelContains,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+
+	err = c.getAll(ctx, "/api/ipam/prefixes/?limit=200", func(raw json.RawMessage) error {
+		var prefix struct {
+			ID     int64  `json:"id"`
+			Prefix string `json:"prefix"`
+			Site   *ref   `json:"site"`
+			Status struct {
+				Value string `json:"value"`
+			} `json:"status"`
+			Description string `json:"description"`
+		}
+		if err := json.Unmarshal(raw, &prefix); err != nil {
+			return err
+		}
+		id := fmt.Sprintf("prefix/%d", prefix.ID)
+		a := &model.Asset{
+			Name:       prefix.Prefix,
+			AssetType:  "subnet",
+			ExternalID: id,
+			Attributes: map[string]any{"cidr": prefix.Prefix, "status": prefix.Status.Value},
+		}
+		if prefix.Description != "" {
+			a.Attributes["description"] = prefix.Description
+		}
+		res.Assets = append(res.Assets, a)
+		if prefix.Site != nil {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: fmt.Sprintf("site/%d", prefix.Site.ID),
+				ToExternalID:   id,
+				Type:           model.RelContains,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+
+	// Every assigned IP lands on its owner; primary IPs were already
+	// set above and the append is deduplicated.
+	err = c.getAll(ctx, "/api/ipam/ip-addresses/?limit=200", func(raw json.RawMessage) error {
+		var ip struct {
+			Address        string `json:"address"`
+			AssignedObject *struct {
+				Device         *ref `json:"device"`
+				VirtualMachine *ref `json:"virtual_machine"`
+			} `json:"assigned_object"`
+		}
+		if err := json.Unmarshal(raw, &ip); err != nil {
+			return err
+		}
+		if ip.AssignedObject == nil {
+			return nil
+		}
+		var owner *model.Asset
+		if d := ip.AssignedObject.Device; d != nil {
+			owner = deviceAssets[fmt.Sprintf("device/%d", d.ID)]
+		} else if v := ip.AssignedObject.VirtualMachine; v != nil {
+			owner = deviceAssets[fmt.Sprintf("vm/%d", v.ID)]
+		}
+		if owner == nil {
+			return nil
+		}
+		addr := stripPrefixLen(ip.Address)
+		for _, existing := range owner.IPs {
+			if existing == addr {
+				return nil
+			}
+		}
+		owner.IPs = append(owner.IPs, addr)
+		return nil
+	})
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// stripPrefixLen removes the prefix length NetBox appends to
+// addresses ("10.0.0.5/24" -> "10.0.0.5").
+func stripPrefixLen(addr string) string {
+	if i := strings.IndexByte(addr, '/'); i >= 0 {
+		return addr[:i]
+	}
+	return addr
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "NetBox base URL"},
+		{Name: "token", Type: collector.TypeString, Required: true, Description: "API token"},
+	}
+}
@@ -0,0 +1,296 @@
+// Package netscaler imports Citrix NetScaler (ADC) configuration via
+// the NITRO REST API: lbvservers, service groups, their backends, and
+// the installed SSL certkeys with expiry dates. Virtual servers are
+// linked to the groups they balance and backends to the groups they
+// belong to, so load-balancing paths show up in the graph.
+package netscaler
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("netscaler", New)
+}
+
+// Collector connects to a NetScaler appliance.
+type Collector struct {
+	name     string
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+// New builds the collector from config. Required settings: url,
+// username, password. Optional: insecure ("true" skips TLS
+// verification, common for appliances with self-signed management
+// certs).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		url:      cfg.Settings["url"],
+		username: cfg.Settings["username"],
+		password: cfg.Settings["password"],
+	}
+	if c.url == "" || c.username == "" || c.password == "" {
+		return nil, fmt.Errorf("netscaler: url, username and password settings are required")
+	}
+	transport := &http.Transport{}
+	if cfg.Settings["insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c.client = &http.Client{Timeout: 60 * time.Second, Transport: transport}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// nitroStatus is the error envelope present in every NITRO response.
+type nitroStatus struct {
+	Errorcode int    `json:"errorcode"`
+	Message   string `json:"message"`
+}
+
+func (s nitroStatus) status() nitroStatus { return s }
+
+// nitroResponse lets get check the envelope of any embedded response
+// struct.
+type nitroResponse interface{ status() nitroStatus }
+
+// get fetches one NITRO config resource into out, which embeds
+// nitroStatus alongside its payload field.
+func (c *Collector) get(ctx context.Context, resource string, out nitroResponse) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/nitro/v1/config/"+resource, nil)
+	if err != nil {
+		return err
+	}
+	// NITRO supports stateless authentication via headers, which avoids
+	// session bookkeeping across the handful of calls we make.
+	req.Header.Set("X-NITRO-USER", c.username)
+	req.Header.Set("X-NITRO-PASS", c.password)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("netscaler: %s returned status %d", resource, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("netscaler: decode %s: %w", resource, err)
+	}
+	if st := out.status(); st.Errorcode != 0 {
+		return fmt.Errorf("netscaler: %s: NITRO error %d: %s", resource, st.Errorcode, st.Message)
+	}
+	return nil
+}
+
+type lbvserver struct {
+	Name        string `json:"name"`
+	IP          string `json:"ipv46"`
+	Port        int    `json:"port"`
+	ServiceType string `json:"servicetype"`
+	State       string `json:"curstate"`
+}
+
+type servicegroup struct {
+	Name        string `json:"servicegroupname"`
+	ServiceType string `json:"servicetype"`
+	State       string `json:"state"`
+}
+
+type sgBinding struct {
+	ServiceGroupName string `json:"servicegroupname"`
+}
+
+type sgMember struct {
+	ServerName string `json:"servername"`
+	IP         string `json:"ip"`
+	Port       int    `json:"port"`
+	State      string `json:"state"`
+}
+
+type sslcertkey struct {
+	CertKey          string `json:"certkey"`
+	Cert             string `json:"cert"`
+	Subject          string `json:"subject"`
+	Issuer           string `json:"issuer"`
+	NotAfter         string `json:"clientcertnotafter"`
+	DaysToExpiration int    `json:"daystoexpiration,string"`
+	Status           string `json:"status"`
+}
+
+// Collect pulls vservers, service groups with their members, and SSL
+// certkeys. External IDs are prefixed by kind ("lbvserver/web-443")
+// because NITRO object names are only unique within their kind.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	var vservers struct {
+		nitroStatus
+		Items []lbvserver `json:"lbvserver"`
+	}
+	if err := c.get(ctx, "lbvserver", &vservers); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, vs := range vservers.Items {
+		a := &model.Asset{
+			Name:       vs.Name,
+			AssetType:  "lb_vserver",
+			ExternalID: "lbvserver/" + vs.Name,
+			Attributes: map[string]any{
+				"service_type": vs.ServiceType,
+				"port":         vs.Port,
+				"state":        vs.State,
+			},
+		}
+		if vs.IP != "" && vs.IP != "0.0.0.0" {
+			a.IPs = []string{vs.IP}
+		}
+		res.Assets = append(res.Assets, a)
+		var bindings struct {
+			nitroStatus
+			Items []sgBinding `json:"lbvserver_servicegroup_binding"`
+		}
+		if err := c.get(ctx, "lbvserver_servicegroup_binding/"+url.PathEscape(vs.Name), &bindings); err != nil {
+			return model.CollectResult{}, err
+		}
+		for _, b := range bindings.Items {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: "lbvserver/" + vs.Name,
+				ToExternalID:   "servicegroup/" + b.ServiceGroupName,
+				Type:           model.RelLoadBalances,
+			})
+		}
+	}
+
+	var groups struct {
+		nitroStatus
+		Items []servicegroup `json:"servicegroup"`
+	}
+	if err := c.get(ctx, "servicegroup", &groups); err != nil {
+		return model.CollectResult{}, err
+	}
+	backends := map[string]*model.Asset{}
+	for _, sg := range groups.Items {
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       sg.Name,
+			AssetType:  "service_group",
+			ExternalID: "servicegroup/" + sg.Name,
+			Attributes: map[string]any{
+				"service_type": sg.ServiceType,
+				"state":        sg.State,
+			},
+		})
+		var members struct {
+			nitroStatus
+			Items []sgMember `json:"servicegroup_servicegroupmember_binding"`
+		}
+		if err := c.get(ctx, "servicegroup_servicegroupmember_binding/"+url.PathEscape(sg.Name), &members); err != nil {
+			return model.CollectResult{}, err
+		}
+		for _, m := range members.Items {
+			b := backendAsset(backends, m)
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: b.ExternalID,
+				ToExternalID:   "servicegroup/" + sg.Name,
+				Type:           model.RelMemberOf,
+				Properties:     map[string]any{"port": m.Port, "state": m.State},
+			})
+		}
+	}
+	for _, b := range backends {
+		res.Assets = append(res.Assets, b)
+	}
+
+	var certs struct {
+		nitroStatus
+		Items []sslcertkey `json:"sslcertkey"`
+	}
+	if err := c.get(ctx, "sslcertkey", &certs); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, ck := range certs.Items {
+		res.Assets = append(res.Assets, certToAsset(ck))
+	}
+
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// backendAsset returns the deduplicated asset for a service group
+// member; the same server typically backs several groups.
+func backendAsset(backends map[string]*model.Asset, m sgMember) *model.Asset {
+	id := m.ServerName
+	if id == "" {
+		id = m.IP
+	}
+	if b, ok := backends[id]; ok {
+		return b
+	}
+	b := &model.Asset{
+		Name:       id,
+		AssetType:  "host",
+		ExternalID: "server/" + id,
+		Attributes: map[string]any{},
+	}
+	if m.IP != "" {
+		b.IPs = []string{m.IP}
+	}
+	backends[id] = b
+	return b
+}
+
+// certToAsset maps an installed certkey to an asset. Expiry lands in
+// attributes (days_to_expiration, not_after) so alert rules can fire
+// on certificates approaching expiry.
+func certToAsset(ck sslcertkey) *model.Asset {
+	a := &model.Asset{
+		Name:       ck.CertKey,
+		AssetType:  "ssl_certificate",
+		ExternalID: "sslcertkey/" + ck.CertKey,
+		Attributes: map[string]any{
+			"cert_file":          ck.Cert,
+			"days_to_expiration": ck.DaysToExpiration,
+			"status":             ck.Status,
+		},
+	}
+	if ck.Subject != "" {
+		a.Attributes["subject"] = ck.Subject
+	}
+	if ck.Issuer != "" {
+		a.Attributes["issuer"] = ck.Issuer
+	}
+	// NITRO reports notAfter in OpenSSL's text form
+	// ("Dec 31 23:59:59 2026 GMT"); keep the parsed form when it
+	// parses.
+	if t, err := time.Parse("Jan 2 15:04:05 2006 MST", ck.NotAfter); err == nil {
+		a.Attributes["not_after"] = t.UTC().Format(time.RFC3339)
+	} else if ck.NotAfter != "" {
+		a.Attributes["not_after"] = ck.NotAfter
+	}
+	return a
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "NITRO API base URL"},
+		{Name: "username", Type: collector.TypeString, Required: true, Description: "API user"},
+		{Name: "password", Type: collector.TypeString, Required: true, Description: "API password"},
+		{Name: "insecure", Type: collector.TypeBool, Description: "skip TLS verification"},
+	}
+}
@@ -0,0 +1,89 @@
+package nmap
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// parseJSON parses the JSON emitted by nmap wrappers. Both a bare host
+// array and a {"hosts": [...]} envelope are accepted.
+func parseJSON(raw []byte) ([]host, error) {
+	var envelope struct {
+		Hosts []jsonHost `json:"hosts"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil || envelope.Hosts == nil {
+		var bare []jsonHost
+		if err := json.Unmarshal(raw, &bare); err != nil {
+			return nil, fmt.Errorf("nmap: parse JSON report: %w", err)
+		}
+		envelope.Hosts = bare
+	}
+	hosts := make([]host, 0, len(envelope.Hosts))
+	for _, jh := range envelope.Hosts {
+		h := host{Up: jh.Status == "up" || jh.Status == ""}
+		for _, addr := range jh.Addresses {
+			switch addr.AddrType {
+			case "ipv4", "ipv6":
+				h.IPs = append(h.IPs, addr.Addr)
+			case "mac":
+				h.MAC = addr.Addr
+			}
+		}
+		if len(h.IPs) == 0 && jh.IP != "" {
+			h.IPs = append(h.IPs, jh.IP)
+		}
+		if len(jh.Hostnames) > 0 {
+			h.FQDN = jh.Hostnames[0].Name
+		} else if jh.Hostname != "" {
+			h.FQDN = jh.Hostname
+		}
+		for _, p := range jh.Ports {
+			if p.State != "" && p.State != "open" {
+				continue
+			}
+			h.Ports = append(h.Ports, portInfo{
+				Port:     p.Port,
+				Protocol: p.Protocol,
+				Service:  p.Service.Name,
+				Product:  p.Service.Product,
+			})
+		}
+		h.OS = jh.OS
+		hosts = append(hosts, h)
+	}
+	return hosts, nil
+}
+
+// JSON mapping for the report shape produced by common nmap wrappers.
+// Field aliases (ip/hostname vs addresses/hostnames) cover the
+// variations we have seen in the wild.
+type jsonHost struct {
+	Status    string        `json:"status"`
+	IP        string        `json:"ip"`
+	Hostname  string        `json:"hostname"`
+	Addresses []jsonAddress `json:"addresses"`
+	Hostnames []jsonName    `json:"hostnames"`
+	Ports     []jsonPort    `json:"ports"`
+	OS        string        `json:"os"`
+}
+
+type jsonAddress struct {
+	Addr     string `json:"addr"`
+	AddrType string `json:"addrtype"`
+}
+
+type jsonName struct {
+	Name string `json:"name"`
+}
+
+type jsonPort struct {
+	Port     int         `json:"port"`
+	Protocol string      `json:"protocol"`
+	State    string      `json:"state"`
+	Service  jsonService `json:"service"`
+}
+
+type jsonService struct {
+	Name    string `json:"name"`
+	Product string `json:"product"`
+}
@@ -0,0 +1,341 @@
+// Package nmap imports hosts from nmap scans, in one of two modes:
+// parsing a report written by -oX (XML) or by JSON-emitting nmap
+// wrappers from a configured path, or running nmap itself against
+// configured targets and parsing the XML it writes to stdout.
+package nmap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("nmap", New)
+}
+
+// Collector parses nmap reports into host assets.
+type Collector struct {
+	name   string
+	path   string
+	format string
+	// Exec mode.
+	targets  []string
+	scanArgs []string
+	nmapPath string
+}
+
+// New builds the collector from config. Exactly one of two settings
+// selects the mode: xml_path (parse an existing report; any report
+// path despite the name) or targets (a comma-separated list of hosts
+// or CIDR ranges for nmap to scan). Optional for xml_path: format
+// ("xml", "json" or "auto", the default, which sniffs the content).
+// Optional for targets: scan_flags (extra nmap arguments, default
+// "-sV"), max_rate (packets per second, passed as --max-rate so scans
+// stay polite) and nmap_path (the binary, default "nmap" from PATH).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		path:     cfg.Settings["xml_path"],
+		nmapPath: cfg.Settings["nmap_path"],
+	}
+	for _, t := range strings.Split(cfg.Settings["targets"], ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			c.targets = append(c.targets, t)
+		}
+	}
+	if (c.path == "") == (len(c.targets) == 0) {
+		return nil, fmt.Errorf("nmap: exactly one of xml_path and targets is required")
+	}
+	format := cfg.Settings["format"]
+	switch format {
+	case "", "auto":
+		format = "auto"
+	case "xml", "json":
+	default:
+		return nil, fmt.Errorf("nmap: unknown format %q (want xml, json or auto)", format)
+	}
+	c.format = format
+	if len(c.targets) > 0 {
+		flags := cfg.Settings["scan_flags"]
+		if flags == "" {
+			flags = "-sV"
+		}
+		c.scanArgs = strings.Fields(flags)
+		if raw := cfg.Settings["max_rate"]; raw != "" {
+			rate, err := strconv.Atoi(raw)
+			if err != nil || rate <= 0 {
+				return nil, fmt.Errorf("nmap: invalid max_rate %q", raw)
+			}
+			c.scanArgs = append(c.scanArgs, "--max-rate", raw)
+		}
+		if c.nmapPath == "" {
+			c.nmapPath = "nmap"
+		}
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect parses the configured report file, or runs the scan in exec
+// mode.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	if len(c.targets) > 0 {
+		return c.collectExec(ctx)
+	}
+	f, err := os.Open(c.path)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("nmap: read report: %w", err)
+	}
+	defer f.Close()
+	hosts, err := parseReportStream(f, c.format)
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	return buildResult(hosts), nil
+}
+
+// collectExec runs nmap against the configured targets with XML on
+// stdout, parsed as it streams. The context cancels the scan; nmap's
+// stderr progress output is kept only for error reporting.
+func (c *Collector) collectExec(ctx context.Context) (model.CollectResult, error) {
+	args := append([]string{"-oX", "-"}, c.scanArgs...)
+	args = append(args, c.targets...)
+	cmd := exec.CommandContext(ctx, c.nmapPath, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("nmap: scan failed: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return model.CollectResult{}, fmt.Errorf("nmap: scan failed: %w", err)
+	}
+	hosts, parseErr := parseXMLStream(stdout)
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return model.CollectResult{}, fmt.Errorf("nmap: scan failed: %s", msg)
+		}
+		return model.CollectResult{}, fmt.Errorf("nmap: scan failed: %w", err)
+	}
+	if parseErr != nil {
+		return model.CollectResult{}, parseErr
+	}
+	return buildResult(hosts), nil
+}
+
+// Preview implements collector.Previewer: it parses an uploaded report
+// instead of the configured path.
+func (c *Collector) Preview(ctx context.Context, payload []byte) (model.CollectResult, error) {
+	hosts, err := parseReport(payload, c.format)
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	return buildResult(hosts), nil
+}
+
+// parseReport dispatches to the XML or JSON parser, sniffing the
+// content when the format is "auto".
+func parseReport(raw []byte, format string) ([]host, error) {
+	return parseReportStream(bytes.NewReader(raw), format)
+}
+
+// parseReportStream is parseReport over a reader. Gzip-compressed
+// input is decompressed transparently (big scan reports are usually
+// stored compressed). XML streams through the decoder host by host;
+// JSON reports are small wrapper output and are read whole.
+func parseReportStream(r io.Reader, format string) ([]host, error) {
+	br := bufio.NewReader(r)
+	if head, err := br.Peek(2); err == nil && head[0] == 0x1f && head[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("nmap: read gzip report: %w", err)
+		}
+		defer gz.Close()
+		br = bufio.NewReader(gz)
+	}
+	if format == "auto" {
+		if sniffJSON(br) {
+			format = "json"
+		} else {
+			format = "xml"
+		}
+	}
+	if format == "json" {
+		raw, err := io.ReadAll(br)
+		if err != nil {
+			return nil, fmt.Errorf("nmap: read report: %w", err)
+		}
+		return parseJSON(raw)
+	}
+	return parseXMLStream(br)
+}
+
+// sniffJSON peeks at the first non-whitespace byte without consuming
+// it.
+func sniffJSON(br *bufio.Reader) bool {
+	for n := 64; n <= 4096; n *= 2 {
+		head, err := br.Peek(n)
+		trimmed := bytes.TrimLeft(head, " \t\r\n")
+		if len(trimmed) > 0 {
+			return trimmed[0] == '{' || trimmed[0] == '['
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// host is the parser-neutral view of one scanned host; both the XML and
+// JSON paths produce it and buildResult maps it to assets.
+type host struct {
+	Up    bool
+	IPs   []string
+	MAC   string
+	FQDN  string
+	OS    string
+	Ports []portInfo
+}
+
+type portInfo struct {
+	Port     int
+	Protocol string
+	Service  string
+	Product  string
+}
+
+// buildResult maps parsed hosts to assets. Hosts that are not up, or
+// have no address, are filtered. Hosts sharing a distinctive service
+// fingerprint are linked with inferred CONNECTS_TO edges so scan data
+// contributes to the graph, not just nodes.
+func buildResult(hosts []host) model.CollectResult {
+	var res model.CollectResult
+	res.Stats.Fetched = len(hosts)
+	// fingerprint -> external IDs of the hosts exposing it.
+	byFingerprint := map[string][]string{}
+	for _, h := range hosts {
+		a := hostToAsset(h)
+		if a == nil {
+			res.Stats.Filtered++
+			continue
+		}
+		res.Assets = append(res.Assets, a)
+		for _, fp := range serviceFingerprints(h) {
+			byFingerprint[fp] = append(byFingerprint[fp], a.ExternalID)
+		}
+	}
+	res.Relationships = inferConnections(byFingerprint)
+	res.Stats.Emitted = len(res.Assets)
+	return res
+}
+
+// serviceFingerprints returns the distinctive service identities a host
+// exposes ("service/product" per port with a known product). Ports
+// without product detection are skipped: "ssh on 22" is shared by
+// everything and would link the whole network. The fingerprints are
+// also emitted as a structured attribute for downstream inference.
+func serviceFingerprints(h host) []string {
+	var out []string
+	for _, p := range h.Ports {
+		if p.Service != "" && p.Product != "" {
+			out = append(out, fmt.Sprintf("%s/%s", p.Service, p.Product))
+		}
+	}
+	return out
+}
+
+// maxFingerprintGroup caps how many hosts a fingerprint may span before
+// it is considered environmental (a fleet-wide agent, say) rather than
+// evidence of a connection.
+const maxFingerprintGroup = 5
+
+// inferConnections links hosts sharing a distinctive fingerprint with
+// CONNECTS_TO edges. Groups above maxFingerprintGroup are dropped; the
+// edges are marked inferred so operators can treat them accordingly.
+func inferConnections(byFingerprint map[string][]string) []model.Relationship {
+	var rels []model.Relationship
+	for fp, ids := range byFingerprint {
+		if len(ids) < 2 || len(ids) > maxFingerprintGroup {
+			continue
+		}
+		for _, id := range ids[1:] {
+			rels = append(rels, model.Relationship{
+				FromExternalID: id,
+				ToExternalID:   ids[0],
+				Type:           model.RelConnectsTo,
+				Properties: map[string]any{
+					"fingerprint":   fp,
+					"inferred_from": "nmap",
+				},
+			})
+		}
+	}
+	return rels
+}
+
+func hostToAsset(h host) *model.Asset {
+	if !h.Up || len(h.IPs) == 0 {
+		return nil
+	}
+	a := &model.Asset{
+		Name:       h.IPs[0],
+		IPs:        h.IPs,
+		AssetType:  "host",
+		ExternalID: h.IPs[0],
+		Attributes: map[string]any{},
+	}
+	if h.FQDN != "" {
+		a.FQDN = h.FQDN
+		a.Name = h.FQDN
+	}
+	if h.MAC != "" {
+		a.MACs = []string{h.MAC}
+	}
+	var ports []map[string]any
+	for _, p := range h.Ports {
+		port := map[string]any{"port": p.Port, "protocol": p.Protocol}
+		if p.Service != "" {
+			port["service"] = p.Service
+		}
+		if p.Product != "" {
+			port["product"] = p.Product
+		}
+		ports = append(ports, port)
+	}
+	if len(ports) > 0 {
+		a.Attributes["open_ports"] = ports
+	}
+	if h.OS != "" {
+		a.Attributes["os"] = h.OS
+	}
+	if fps := serviceFingerprints(h); len(fps) > 0 {
+		a.Attributes["service_fingerprints"] = fps
+	}
+	return a
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "xml_path", Type: collector.TypeString, Description: "report to parse (one of xml_path and targets)"},
+		{Name: "format", Type: collector.TypeString, Description: "\"xml\", \"json\" or \"auto\" (default)"},
+		{Name: "targets", Type: collector.TypeList, Description: "hosts or CIDR ranges to scan (one of xml_path and targets)"},
+		{Name: "scan_flags", Type: collector.TypeString, Description: "extra nmap arguments (default -sV)"},
+		{Name: "max_rate", Type: collector.TypeInt, Description: "packets per second, passed as --max-rate"},
+		{Name: "nmap_path", Type: collector.TypeString, Description: "nmap binary (default from PATH)"},
+	}
+}
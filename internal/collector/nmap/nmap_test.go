@@ -0,0 +1,121 @@
+package nmap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+)
+
+const sampleXML = `<?xml version="1.0"?>
+<nmaprun>
+  <host>
+    <status state="up"/>
+    <address addr="10.0.0.5" addrtype="ipv4"/>
+    <address addr="AA:BB:CC:DD:EE:FF" addrtype="mac"/>
+    <hostnames><hostname name="db1.example.com" type="PTR"/></hostnames>
+    <ports>
+      <port protocol="tcp" portid="5432"><state state="open"/><service name="postgresql" product="PostgreSQL"/></port>
+      <port protocol="tcp" portid="23"><state state="closed"/><service name="telnet"/></port>
+    </ports>
+    <os><osmatch name="Linux 5.X"/></os>
+  </host>
+  <host>
+    <status state="down"/>
+    <address addr="10.0.0.6" addrtype="ipv4"/>
+  </host>
+</nmaprun>`
+
+const sampleJSON = `{"hosts": [
+  {"status": "up", "ip": "10.0.0.5", "hostname": "db1.example.com",
+   "ports": [{"port": 5432, "protocol": "tcp", "state": "open", "service": {"name": "postgresql"}},
+             {"port": 23, "protocol": "tcp", "state": "closed", "service": {"name": "telnet"}}],
+   "os": "Linux 5.X"},
+  {"status": "down", "ip": "10.0.0.6"}
+]}`
+
+func TestParseXMLAndJSONAgree(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		raw    string
+		format string
+	}{
+		{"xml", sampleXML, "auto"},
+		{"json", sampleJSON, "auto"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hosts, err := parseReport([]byte(tc.raw), tc.format)
+			if err != nil {
+				t.Fatalf("parseReport: %v", err)
+			}
+			res := buildResult(hosts)
+			if res.Stats.Fetched != 2 || res.Stats.Filtered != 1 || res.Stats.Emitted != 1 {
+				t.Fatalf("stats = %+v, want fetched=2 filtered=1 emitted=1", res.Stats)
+			}
+			a := res.Assets[0]
+			if a.Name != "db1.example.com" || a.FQDN != "db1.example.com" {
+				t.Errorf("name/fqdn = %q/%q, want db1.example.com", a.Name, a.FQDN)
+			}
+			if len(a.IPs) != 1 || a.IPs[0] != "10.0.0.5" {
+				t.Errorf("ips = %v, want [10.0.0.5]", a.IPs)
+			}
+			ports, ok := a.Attributes["open_ports"].([]map[string]any)
+			if !ok || len(ports) != 1 {
+				t.Fatalf("open_ports = %v, want one open port", a.Attributes["open_ports"])
+			}
+			if ports[0]["port"] != 5432 || ports[0]["service"] != "postgresql" {
+				t.Errorf("port = %v, want 5432/postgresql", ports[0])
+			}
+			if a.Attributes["os"] != "Linux 5.X" {
+				t.Errorf("os = %v, want Linux 5.X", a.Attributes["os"])
+			}
+		})
+	}
+}
+
+func TestInferConnections(t *testing.T) {
+	hosts := []host{
+		{Up: true, IPs: []string{"10.0.0.1"}, Ports: []portInfo{{Port: 5432, Protocol: "tcp", Service: "postgresql", Product: "PostgreSQL"}}},
+		{Up: true, IPs: []string{"10.0.0.2"}, Ports: []portInfo{{Port: 5432, Protocol: "tcp", Service: "postgresql", Product: "PostgreSQL"}}},
+		// No product detection: must never produce an edge.
+		{Up: true, IPs: []string{"10.0.0.3"}, Ports: []portInfo{{Port: 22, Protocol: "tcp", Service: "ssh"}}},
+	}
+	res := buildResult(hosts)
+	if len(res.Relationships) != 1 {
+		t.Fatalf("got %d relationships, want 1: %+v", len(res.Relationships), res.Relationships)
+	}
+	rel := res.Relationships[0]
+	if rel.Type != "CONNECTS_TO" {
+		t.Errorf("type = %q, want CONNECTS_TO", rel.Type)
+	}
+	if rel.Properties["fingerprint"] != "postgresql/PostgreSQL" {
+		t.Errorf("fingerprint = %v, want postgresql/PostgreSQL", rel.Properties["fingerprint"])
+	}
+}
+
+func TestParseReportGzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(sampleXML)); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	hosts, err := parseReport(buf.Bytes(), "auto")
+	if err != nil {
+		t.Fatalf("parseReport: %v", err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("got %d hosts, want 2", len(hosts))
+	}
+}
+
+func TestParseReportSniffsFormat(t *testing.T) {
+	hosts, err := parseReport([]byte("  \n"+sampleJSON), "auto")
+	if err != nil {
+		t.Fatalf("parseReport: %v", err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("got %d hosts, want 2", len(hosts))
+	}
+}
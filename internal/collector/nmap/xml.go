@@ -0,0 +1,117 @@
+package nmap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// parseXML parses an in-memory nmap -oX report.
+func parseXML(raw []byte) ([]host, error) {
+	return parseXMLStream(bytes.NewReader(raw))
+}
+
+// parseXMLStream parses an nmap -oX report from a reader, decoding one
+// <host> element at a time. A /16 scan report runs to gigabytes;
+// unmarshalling the whole document would hold every host in memory
+// twice (the DOM and our slice), so only the current element is ever
+// materialised.
+func parseXMLStream(r io.Reader) ([]host, error) {
+	dec := xml.NewDecoder(r)
+	var hosts []host
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("nmap: parse XML report: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "host" {
+			continue
+		}
+		var xh xmlHost
+		if err := dec.DecodeElement(&xh, &se); err != nil {
+			return nil, fmt.Errorf("nmap: parse XML report: %w", err)
+		}
+		hosts = append(hosts, hostFromXML(xh))
+	}
+	return hosts, nil
+}
+
+func hostFromXML(xh xmlHost) host {
+	h := host{Up: xh.Status.State == "up"}
+	for _, addr := range xh.Addresses {
+		switch addr.AddrType {
+		case "ipv4", "ipv6":
+			h.IPs = append(h.IPs, addr.Addr)
+		case "mac":
+			h.MAC = addr.Addr
+		}
+	}
+	for _, hn := range xh.Hostnames {
+		if hn.Name != "" {
+			h.FQDN = hn.Name
+			break
+		}
+	}
+	for _, p := range xh.Ports {
+		if p.State.State != "open" {
+			continue
+		}
+		h.Ports = append(h.Ports, portInfo{
+			Port:     p.PortID,
+			Protocol: p.Protocol,
+			Service:  p.Service.Name,
+			Product:  p.Service.Product,
+		})
+	}
+	if len(xh.OS.Matches) > 0 {
+		h.OS = xh.OS.Matches[0].Name
+	}
+	return h
+}
+
+// XML mapping for the subset of the nmap report we consume.
+type xmlHost struct {
+	Status    xmlStatus     `xml:"status"`
+	Addresses []xmlAddress  `xml:"address"`
+	Hostnames []xmlHostname `xml:"hostnames>hostname"`
+	Ports     []xmlPort     `xml:"ports>port"`
+	OS        xmlOS         `xml:"os"`
+}
+
+type xmlStatus struct {
+	State string `xml:"state,attr"`
+}
+
+type xmlAddress struct {
+	Addr     string `xml:"addr,attr"`
+	AddrType string `xml:"addrtype,attr"`
+}
+
+type xmlHostname struct {
+	Name string `xml:"name,attr"`
+}
+
+type xmlPort struct {
+	Protocol string     `xml:"protocol,attr"`
+	PortID   int        `xml:"portid,attr"`
+	State    xmlStatus  `xml:"state"`
+	Service  xmlService `xml:"service"`
+}
+
+type xmlService struct {
+	Name    string `xml:"name,attr"`
+	Product string `xml:"product,attr"`
+}
+
+type xmlOS struct {
+	Matches []xmlOSMatch `xml:"osmatch"`
+}
+
+type xmlOSMatch struct {
+	Name string `xml:"name,attr"`
+}
@@ -0,0 +1,242 @@
+// Package openstack imports instances, networks, routers and load
+// balancers from an OpenStack cloud via gophercloud (Nova, Neutron
+// and, when deployed, Octavia). Instances are linked to the networks
+// they attach to with CONNECTS_TO edges.
+package openstack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("openstack", New)
+}
+
+// Collector connects to one OpenStack project.
+type Collector struct {
+	name   string
+	opts   gophercloud.AuthOptions
+	region string
+}
+
+// New builds the collector from config. Required settings: auth_url,
+// username, password, project_name. Optional: domain_name (defaults to
+// "Default") and region.
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name: cfg.Name,
+		opts: gophercloud.AuthOptions{
+			IdentityEndpoint: cfg.Settings["auth_url"],
+			Username:         cfg.Settings["username"],
+			Password:         cfg.Settings["password"],
+			TenantName:       cfg.Settings["project_name"],
+			DomainName:       cfg.Settings["domain_name"],
+			AllowReauth:      true,
+		},
+		region: cfg.Settings["region"],
+	}
+	if c.opts.IdentityEndpoint == "" || c.opts.Username == "" || c.opts.Password == "" || c.opts.TenantName == "" {
+		return nil, fmt.Errorf("openstack: auth_url, username, password and project_name settings are required")
+	}
+	if c.opts.DomainName == "" {
+		c.opts.DomainName = "Default"
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect authenticates and walks Nova, Neutron and Octavia. External
+// IDs are the OpenStack UUIDs. Octavia is optional in many
+// deployments, so a missing load-balancer endpoint is not an error.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	provider, err := openstack.AuthenticatedClient(c.opts)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("openstack: authenticate: %w", err)
+	}
+	provider.Context = ctx
+	endpoint := gophercloud.EndpointOpts{Region: c.region}
+
+	computeClient, err := openstack.NewComputeV2(provider, endpoint)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("openstack: compute endpoint: %w", err)
+	}
+	networkClient, err := openstack.NewNetworkV2(provider, endpoint)
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("openstack: network endpoint: %w", err)
+	}
+
+	var res model.CollectResult
+
+	// Network name -> ID, for resolving instance attachments: Nova
+	// reports addresses keyed by network name.
+	netIDs := map[string]string{}
+	err = networks.List(networkClient, networks.ListOpts{}).EachPage(func(page pagination.Page) (bool, error) {
+		nets, err := networks.ExtractNetworks(page)
+		if err != nil {
+			return false, err
+		}
+		for _, n := range nets {
+			netIDs[n.Name] = n.ID
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       n.Name,
+				AssetType:  "network",
+				ExternalID: n.ID,
+				Attributes: map[string]any{
+					"status": n.Status,
+					"shared": n.Shared,
+				},
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("openstack: list networks: %w", err)
+	}
+
+	err = routers.List(networkClient, routers.ListOpts{}).EachPage(func(page pagination.Page) (bool, error) {
+		rs, err := routers.ExtractRouters(page)
+		if err != nil {
+			return false, err
+		}
+		for _, rt := range rs {
+			res.Assets = append(res.Assets, &model.Asset{
+				Name:       rt.Name,
+				AssetType:  "router",
+				ExternalID: rt.ID,
+				Attributes: map[string]any{"status": rt.Status, "admin_up": rt.AdminStateUp},
+			})
+			if rt.GatewayInfo.NetworkID != "" {
+				res.Relationships = append(res.Relationships, model.Relationship{
+					FromExternalID: rt.ID,
+					ToExternalID:   rt.GatewayInfo.NetworkID,
+					Type:           model.RelConnectsTo,
+					Properties:     map[string]any{"role": "gateway"},
+				})
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("openstack: list routers: %w", err)
+	}
+
+	err = servers.List(computeClient, servers.ListOpts{}).EachPage(func(page pagination.Page) (bool, error) {
+		srvs, err := servers.ExtractServers(page)
+		if err != nil {
+			return false, err
+		}
+		for _, srv := range srvs {
+			res.Assets = append(res.Assets, serverToAsset(srv))
+			for netName := range srv.Addresses {
+				if id := netIDs[netName]; id != "" {
+					res.Relationships = append(res.Relationships, model.Relationship{
+						FromExternalID: srv.ID,
+						ToExternalID:   id,
+						Type:           model.RelConnectsTo,
+					})
+				}
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return model.CollectResult{}, fmt.Errorf("openstack: list servers: %w", err)
+	}
+
+	// Octavia: optional; clouds without it just skip load balancers.
+	if lbClient, err := openstack.NewLoadBalancerV2(provider, endpoint); err == nil {
+		err = loadbalancers.List(lbClient, loadbalancers.ListOpts{}).EachPage(func(page pagination.Page) (bool, error) {
+			lbs, err := loadbalancers.ExtractLoadBalancers(page)
+			if err != nil {
+				return false, err
+			}
+			for _, lb := range lbs {
+				a := &model.Asset{
+					Name:       lb.Name,
+					AssetType:  "load_balancer",
+					ExternalID: lb.ID,
+					Attributes: map[string]any{
+						"provisioning_status": lb.ProvisioningStatus,
+						"operating_status":    lb.OperatingStatus,
+						"provider":            lb.Provider,
+					},
+				}
+				if lb.VipAddress != "" {
+					a.IPs = []string{lb.VipAddress}
+				}
+				res.Assets = append(res.Assets, a)
+				if lb.VipNetworkID != "" {
+					res.Relationships = append(res.Relationships, model.Relationship{
+						FromExternalID: lb.ID,
+						ToExternalID:   lb.VipNetworkID,
+						Type:           model.RelConnectsTo,
+					})
+				}
+			}
+			return true, nil
+		})
+		if err != nil {
+			return model.CollectResult{}, fmt.Errorf("openstack: list load balancers: %w", err)
+		}
+	}
+
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func serverToAsset(srv servers.Server) *model.Asset {
+	a := &model.Asset{
+		Name:       srv.Name,
+		AssetType:  "vm",
+		ExternalID: srv.ID,
+		Attributes: map[string]any{"status": srv.Status},
+	}
+	if len(srv.Metadata) > 0 {
+		a.Attributes["metadata"] = srv.Metadata
+	}
+	// Addresses is network name -> []{addr, type, version}.
+	for _, raw := range srv.Addresses {
+		entries, ok := raw.([]any)
+		if !ok {
+			continue
+		}
+		for _, e := range entries {
+			m, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			if addr, ok := m["addr"].(string); ok && addr != "" {
+				a.IPs = append(a.IPs, addr)
+			}
+		}
+	}
+	return a
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "auth_url", Type: collector.TypeString, Required: true, Description: "Keystone endpoint"},
+		{Name: "username", Type: collector.TypeString, Required: true, Description: "API user"},
+		{Name: "password", Type: collector.TypeString, Required: true, Description: "API password"},
+		{Name: "project_name", Type: collector.TypeString, Required: true, Description: "project to enumerate"},
+		{Name: "domain_name", Type: collector.TypeString, Description: "user domain, defaults to Default"},
+		{Name: "region", Type: collector.TypeString, Description: "region to query"},
+	}
+}
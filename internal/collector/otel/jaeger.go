@@ -0,0 +1,50 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// collectJaeger reads the Jaeger query service's dependencies API,
+// which returns aggregated caller/callee pairs for a time window.
+func (c *Collector) collectJaeger(ctx context.Context) ([]dependency, error) {
+	endTs := time.Now().UnixMilli()
+	query := url.Values{
+		"endTs":    {strconv.FormatInt(endTs, 10)},
+		"lookback": {strconv.FormatInt(c.lookback.Milliseconds(), 10)},
+	}
+	u := strings.TrimSuffix(c.url, "/") + "/api/dependencies?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("otel: fetch dependencies: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("otel: dependencies returned status %d", resp.StatusCode)
+	}
+	var envelope struct {
+		Data []struct {
+			Parent    string `json:"parent"`
+			Child     string `json:"child"`
+			CallCount int64  `json:"callCount"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("otel: decode dependencies: %w", err)
+	}
+	deps := make([]dependency, 0, len(envelope.Data))
+	for _, d := range envelope.Data {
+		deps = append(deps, dependency{Parent: d.Parent, Child: d.Child, Calls: d.CallCount})
+	}
+	return deps, nil
+}
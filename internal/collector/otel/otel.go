@@ -0,0 +1,142 @@
+// Package otel imports the application-level service topology from an
+// OpenTelemetry tracing backend: every traced service becomes a
+// service asset and observed caller/callee pairs become DEPENDS_ON
+// edges. Traces record who actually calls whom in production, which is
+// the dependency ground truth the infrastructure collectors can only
+// guess at — this source bridges the two layers.
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("otel", New)
+}
+
+// Collector reads one tracing backend.
+type Collector struct {
+	name     string
+	backend  string
+	url      string
+	lookback time.Duration
+	client   *http.Client
+}
+
+// New builds the collector from config. Required settings: backend
+// ("jaeger" for the Jaeger dependencies API, "tempo" for Tempo's
+// service-graph metrics via a Prometheus-compatible endpoint) and url.
+// Optional: lookback (how far back to read dependencies, Go duration,
+// default 1h).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		backend:  cfg.Settings["backend"],
+		url:      cfg.Settings["url"],
+		lookback: time.Hour,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+	switch c.backend {
+	case "jaeger", "tempo":
+	case "":
+		return nil, fmt.Errorf("otel: backend setting is required (jaeger or tempo)")
+	default:
+		return nil, fmt.Errorf("otel: unknown backend %q (want jaeger or tempo)", c.backend)
+	}
+	if c.url == "" {
+		return nil, fmt.Errorf("otel: url setting is required")
+	}
+	if raw := cfg.Settings["lookback"]; raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("otel: invalid lookback %q: %w", raw, err)
+		}
+		c.lookback = d
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// dependency is one observed caller/callee pair, the backend-neutral
+// unit both backends produce.
+type dependency struct {
+	Parent string
+	Child  string
+	Calls  int64
+}
+
+// Collect fetches the dependency pairs and maps them to service assets
+// and edges.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var deps []dependency
+	var err error
+	switch c.backend {
+	case "jaeger":
+		deps, err = c.collectJaeger(ctx)
+	case "tempo":
+		deps, err = c.collectTempo(ctx)
+	}
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	return buildResult(deps), nil
+}
+
+// buildResult emits one service asset per distinct service name and a
+// DEPENDS_ON edge per caller/callee pair. Pairs with an empty side are
+// filtered — backends report those for uninstrumented peers.
+func buildResult(deps []dependency) model.CollectResult {
+	var res model.CollectResult
+	res.Stats.Fetched = len(deps)
+	services := map[string]bool{}
+	for _, d := range deps {
+		if d.Parent == "" || d.Child == "" || d.Parent == d.Child {
+			res.Stats.Filtered++
+			continue
+		}
+		services[d.Parent] = true
+		services[d.Child] = true
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: "service/" + d.Parent,
+			ToExternalID:   "service/" + d.Child,
+			Type:           model.RelDependsOn,
+			Properties: map[string]any{
+				"call_count": d.Calls,
+				"observed":   "traces",
+			},
+		})
+	}
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       name,
+			AssetType:  "service",
+			ExternalID: "service/" + name,
+		})
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "backend", Type: collector.TypeString, Required: true, Description: "\"jaeger\" or \"tempo\""},
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "backend base URL"},
+		{Name: "lookback", Type: collector.TypeDuration, Description: "how far back to read dependencies (default 1h)"},
+	}
+}
@@ -0,0 +1,78 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// collectTempo reads Tempo's service graph. Tempo's metrics-generator
+// publishes the graph as Prometheus metrics, so the url setting points
+// at the Prometheus-compatible endpoint those land in and we query the
+// request totals summed per client/server pair over the lookback.
+func (c *Collector) collectTempo(ctx context.Context) ([]dependency, error) {
+	window := fmt.Sprintf("%ds", int(c.lookback.Seconds()))
+	promQL := fmt.Sprintf("sum by (client, server) (increase(traces_service_graph_request_total[%s]))", window)
+	u := strings.TrimSuffix(c.url, "/") + "/api/v1/query?" + url.Values{"query": {promQL}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("otel: query service graph: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("otel: service graph query returned status %d", resp.StatusCode)
+	}
+	var envelope struct {
+		Status string `json:"status"`
+		Data   struct {
+			Result []struct {
+				Metric struct {
+					Client string `json:"client"`
+					Server string `json:"server"`
+				} `json:"metric"`
+				Value []any `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("otel: decode service graph: %w", err)
+	}
+	if envelope.Status != "success" {
+		return nil, fmt.Errorf("otel: service graph query reported status %q", envelope.Status)
+	}
+	var deps []dependency
+	for _, r := range envelope.Data.Result {
+		deps = append(deps, dependency{
+			Parent: r.Metric.Client,
+			Child:  r.Metric.Server,
+			Calls:  sampleValue(r.Value),
+		})
+	}
+	return deps, nil
+}
+
+// sampleValue extracts the value from a Prometheus instant-vector
+// sample, which arrives as [timestamp, "value"].
+func sampleValue(sample []any) int64 {
+	if len(sample) != 2 {
+		return 0
+	}
+	raw, ok := sample[1].(string)
+	if !ok {
+		return 0
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(v) {
+		return 0
+	}
+	return int64(math.Round(v))
+}
@@ -0,0 +1,177 @@
+// Package phpipam imports subnets and IP reservations from a phpIPAM
+// instance. Subnets become subnet assets and every reserved address
+// becomes a thin host inside its subnet via a CONTAINS edge — the
+// address plan as the IPAM believes it to be, which the reconciler
+// then lines up against what collectors actually observe on the
+// network.
+package phpipam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("phpipam", New)
+}
+
+// Collector reads one phpIPAM instance.
+type Collector struct {
+	name   string
+	url    string
+	appID  string
+	token  string
+	client *http.Client
+}
+
+// New builds the collector from config. Required settings: url (the
+// phpIPAM base URL), app_id and token (a static app token for an API
+// app with read access).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:   cfg.Name,
+		url:    strings.TrimSuffix(cfg.Settings["url"], "/"),
+		appID:  cfg.Settings["app_id"],
+		token:  cfg.Settings["token"],
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+	if c.url == "" || c.appID == "" || c.token == "" {
+		return nil, fmt.Errorf("phpipam: url, app_id and token settings are required")
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) get(ctx context.Context, path string, out any) error {
+	url := fmt.Sprintf("%s/api/%s%s", c.url, c.appID, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("token", c.token)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("phpipam: %s returned status %d", path, resp.StatusCode)
+	}
+	var envelope struct {
+		Success bool            `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("phpipam: decode %s: %w", path, err)
+	}
+	if !envelope.Success {
+		return fmt.Errorf("phpipam: %s reported failure", path)
+	}
+	return json.Unmarshal(envelope.Data, out)
+}
+
+// Collect lists all subnets and all addresses. External IDs use the
+// phpIPAM numeric IDs, which survive renumbering.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	var subnets []struct {
+		ID          string `json:"id"`
+		Subnet      string `json:"subnet"`
+		Mask        string `json:"mask"`
+		Description string `json:"description"`
+	}
+	if err := c.get(ctx, "/subnets/", &subnets); err != nil {
+		return model.CollectResult{}, err
+	}
+	subnetIDs := map[string]string{}
+	for _, s := range subnets {
+		if s.Subnet == "" || s.Mask == "" {
+			continue
+		}
+		cidr := s.Subnet + "/" + s.Mask
+		id := "subnet/" + s.ID
+		subnetIDs[s.ID] = id
+		name := s.Description
+		if name == "" {
+			name = cidr
+		}
+		res.Assets = append(res.Assets, &model.Asset{
+			Name:       name,
+			AssetType:  "subnet",
+			ExternalID: id,
+			Attributes: map[string]any{"cidr": cidr},
+		})
+	}
+
+	var addresses []struct {
+		ID          string `json:"id"`
+		SubnetID    string `json:"subnetId"`
+		IP          string `json:"ip"`
+		Hostname    string `json:"hostname"`
+		Description string `json:"description"`
+		MAC         string `json:"mac"`
+	}
+	if err := c.get(ctx, "/addresses/", &addresses); err != nil {
+		return model.CollectResult{}, err
+	}
+	res.Stats.Fetched = len(subnets) + len(addresses)
+	for _, addr := range addresses {
+		if net.ParseIP(addr.IP) == nil {
+			res.Stats.Filtered++
+			continue
+		}
+		id := "address/" + addr.ID
+		name := addr.Hostname
+		if name == "" {
+			name = addr.IP
+		}
+		a := &model.Asset{
+			Name:       name,
+			AssetType:  "host",
+			ExternalID: id,
+			IPs:        []string{addr.IP},
+			Attributes: map[string]any{},
+		}
+		if strings.Contains(addr.Hostname, ".") {
+			a.FQDN = addr.Hostname
+		}
+		if addr.Description != "" {
+			a.Attributes["description"] = addr.Description
+		}
+		if addr.MAC != "" {
+			a.MACs = []string{addr.MAC}
+		}
+		res.Assets = append(res.Assets, a)
+		if parent, ok := subnetIDs[addr.SubnetID]; ok {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: parent,
+				ToExternalID:   id,
+				Type:           model.RelContains,
+			})
+		}
+	}
+
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "phpIPAM base URL"},
+		{Name: "app_id", Type: collector.TypeString, Required: true, Description: "API app ID"},
+		{Name: "token", Type: collector.TypeString, Required: true, Description: "static app token"},
+	}
+}
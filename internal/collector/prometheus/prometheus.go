@@ -0,0 +1,205 @@
+// Package prometheus discovers monitored endpoints from a Prometheus
+// server: every active scrape target becomes a thin asset carrying
+// its instance IP, with a MONITORS edge from the server. The instance
+// IPs let the reconciler's fuzzy matcher fold the targets onto hosts
+// other sources know in full, which answers the operational question
+// "is this machine monitored?". When the node exporter is scraped,
+// node_uname_info facts are added to the targets.
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("prometheus", New)
+}
+
+// Collector reads one Prometheus server.
+type Collector struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// New builds the collector from config. Required setting: url (the
+// Prometheus base URL).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:   cfg.Name,
+		url:    strings.TrimSuffix(cfg.Settings["url"], "/"),
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+	if c.url == "" {
+		return nil, fmt.Errorf("prometheus: url setting is required")
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("prometheus: %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("prometheus: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+type target struct {
+	Labels     map[string]string `json:"labels"`
+	ScrapeURL  string            `json:"scrapeUrl"`
+	Health     string            `json:"health"`
+	LastScrape time.Time         `json:"lastScrape"`
+}
+
+// Collect lists active targets and decorates them with node exporter
+// facts. The server itself is one asset; each distinct instance
+// (host:port stripped to host) is another, so two jobs scraping the
+// same machine fold into one endpoint.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+
+	serverName := c.url
+	if u, err := url.Parse(c.url); err == nil && u.Host != "" {
+		serverName = u.Host
+	}
+	serverID := "prometheus/" + serverName
+	res.Assets = append(res.Assets, &model.Asset{
+		Name:       serverName,
+		AssetType:  "monitoring_server",
+		ExternalID: serverID,
+		Attributes: map[string]any{"url": c.url},
+	})
+
+	var targets struct {
+		Data struct {
+			ActiveTargets []target `json:"activeTargets"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/api/v1/targets?state=active", &targets); err != nil {
+		return model.CollectResult{}, err
+	}
+	res.Stats.Fetched = len(targets.Data.ActiveTargets)
+
+	// One asset per scraped machine; jobs land as attributes on it.
+	endpoints := map[string]*model.Asset{}
+	for _, t := range targets.Data.ActiveTargets {
+		instance := t.Labels["instance"]
+		host := instanceHost(instance)
+		if host == "" {
+			res.Stats.Filtered++
+			continue
+		}
+		a := endpoints[host]
+		if a == nil {
+			a = &model.Asset{
+				Name:       host,
+				AssetType:  "host",
+				ExternalID: "target/" + host,
+				Attributes: map[string]any{},
+			}
+			if net.ParseIP(host) != nil {
+				a.IPs = []string{host}
+			} else if strings.Contains(host, ".") {
+				a.FQDN = host
+			}
+			endpoints[host] = a
+			res.Assets = append(res.Assets, a)
+		}
+		jobs, _ := a.Attributes["monitored_jobs"].([]string)
+		a.Attributes["monitored_jobs"] = append(jobs, t.Labels["job"])
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: serverID,
+			ToExternalID:   a.ExternalID,
+			Type:           model.RelMonitors,
+			Properties: map[string]any{
+				"job":    t.Labels["job"],
+				"health": t.Health,
+			},
+		})
+	}
+
+	if err := c.applyUname(ctx, endpoints); err != nil {
+		// Facts are a bonus; a server without the node exporter job
+		// still yields useful topology.
+		res.Stats.Filtered++
+	}
+
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// applyUname folds node_uname_info metric labels (nodename, sysname,
+// release) onto the endpoint assets by instance.
+func (c *Collector) applyUname(ctx context.Context, endpoints map[string]*model.Asset) error {
+	var result struct {
+		Data struct {
+			Result []struct {
+				Metric map[string]string `json:"metric"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/api/v1/query?query="+url.QueryEscape("node_uname_info"), &result); err != nil {
+		return err
+	}
+	for _, r := range result.Data.Result {
+		a := endpoints[instanceHost(r.Metric["instance"])]
+		if a == nil {
+			continue
+		}
+		if nodename := r.Metric["nodename"]; nodename != "" {
+			a.Name = nodename
+			if strings.Contains(nodename, ".") && a.FQDN == "" {
+				a.FQDN = nodename
+			}
+		}
+		if sysname := r.Metric["sysname"]; sysname != "" {
+			os := sysname
+			if release := r.Metric["release"]; release != "" {
+				os += " " + release
+			}
+			a.Attributes["os"] = os
+		}
+	}
+	return nil
+}
+
+// instanceHost strips the scrape port from an instance label
+// ("10.0.0.5:9100" -> "10.0.0.5").
+func instanceHost(instance string) string {
+	if host, _, err := net.SplitHostPort(instance); err == nil {
+		return host
+	}
+	return instance
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "Prometheus base URL"},
+	}
+}
@@ -0,0 +1,256 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/reconcile"
+)
+
+// Status is the scheduler's view of one collector instance, exposed by
+// the collectors API.
+type Status struct {
+	Name                string              `json:"name"`
+	Type                string              `json:"type"`
+	Enabled             bool                `json:"enabled"`
+	Interval            time.Duration       `json:"interval"`
+	Running             bool                `json:"running"`
+	LastRun             time.Time           `json:"last_run,omitempty"`
+	LastError           string              `json:"last_error,omitempty"`
+	LastReport          *reconcile.Report   `json:"last_report,omitempty"`
+	LastStats           *model.CollectStats `json:"last_stats,omitempty"`
+	ConsecutiveFailures int                 `json:"consecutive_failures"`
+	// Schema describes the settings the collector's type understands,
+	// so API consumers can build and validate configurations.
+	Schema Schema `json:"schema,omitempty"`
+}
+
+type instance struct {
+	cfg       config.CollectorConfig
+	collector Collector
+
+	mu     sync.Mutex
+	status Status
+}
+
+// Scheduler runs configured collectors on their intervals and feeds
+// their output to the reconciler.
+type Scheduler struct {
+	reconciler *reconcile.Reconciler
+	log        *slog.Logger
+	// failureThreshold emits a collector.failing alert to the sinks
+	// when a collector's consecutive failures reach it; <= 0 disables.
+	failureThreshold int
+	sinks            []reconcile.EventSink
+
+	mu        sync.RWMutex
+	instances map[string]*instance
+}
+
+// NewScheduler builds collectors for every configured instance. sinks
+// receive synthetic collector.failing alerts when a collector crosses
+// failureThreshold consecutive failures.
+func NewScheduler(cfgs []config.CollectorConfig, rec *reconcile.Reconciler, failureThreshold int, log *slog.Logger, sinks ...reconcile.EventSink) (*Scheduler, error) {
+	s := &Scheduler{
+		reconciler:       rec,
+		log:              log,
+		failureThreshold: failureThreshold,
+		sinks:            sinks,
+		instances:        make(map[string]*instance, len(cfgs)),
+	}
+	for _, cfg := range cfgs {
+		c, err := Build(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("collector %q: %w", cfg.Name, err)
+		}
+		if _, dup := s.instances[cfg.Name]; dup {
+			return nil, fmt.Errorf("collector %q: duplicate name", cfg.Name)
+		}
+		s.instances[cfg.Name] = &instance{
+			cfg:       cfg,
+			collector: c,
+			status: Status{
+				Name: cfg.Name, Type: cfg.Type,
+				Enabled: cfg.Enabled, Interval: cfg.Interval.Std(),
+				Schema: c.Schema(),
+			},
+		}
+	}
+	return s, nil
+}
+
+// Run starts one loop per enabled collector and blocks until ctx is
+// cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	s.mu.RLock()
+	for _, inst := range s.instances {
+		if !inst.cfg.Enabled {
+			continue
+		}
+		wg.Add(1)
+		go func(inst *instance) {
+			defer wg.Done()
+			s.loop(ctx, inst)
+		}(inst)
+	}
+	s.mu.RUnlock()
+	wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, inst *instance) {
+	ticker := time.NewTicker(inst.cfg.Interval.Std())
+	defer ticker.Stop()
+	s.runOnce(ctx, inst)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, inst)
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, inst *instance) {
+	inst.mu.Lock()
+	if inst.status.Running {
+		inst.mu.Unlock()
+		s.log.Warn("scheduler: previous run still in progress, skipping", "collector", inst.cfg.Name)
+		return
+	}
+	inst.status.Running = true
+	inst.mu.Unlock()
+
+	start := time.Now()
+	res, err := inst.collector.Collect(ctx)
+	var report *reconcile.Report
+	if err == nil {
+		s.log.Info("scheduler: collected", "collector", inst.cfg.Name,
+			"fetched", res.Stats.Fetched, "filtered", res.Stats.Filtered, "emitted", res.Stats.Emitted)
+		report, err = s.reconciler.Reconcile(ctx, inst.cfg.Name, res, false)
+		if err == nil && !res.Partial {
+			// Partial runs say nothing about unreported assets, so only
+			// full snapshots advance the lifecycle.
+			s.sweep(ctx, inst)
+		}
+	}
+
+	inst.mu.Lock()
+	inst.status.Running = false
+	inst.status.LastRun = start
+	if err != nil {
+		inst.status.LastError = err.Error()
+		inst.status.ConsecutiveFailures++
+		s.log.Error("scheduler: collector run failed", "collector", inst.cfg.Name,
+			"failures", inst.status.ConsecutiveFailures, "error", err)
+		if s.failureThreshold > 0 && inst.status.ConsecutiveFailures == s.failureThreshold {
+			s.alertFailing(inst.cfg.Name, inst.status.ConsecutiveFailures, err)
+		}
+	} else {
+		inst.status.LastError = ""
+		inst.status.ConsecutiveFailures = 0
+		inst.status.LastReport = report
+		stats := res.Stats
+		inst.status.LastStats = &stats
+	}
+	inst.mu.Unlock()
+}
+
+// sweep applies the collector's configured lifecycle thresholds,
+// expressed in intervals, after a successful full run. A sweep failure
+// is logged but doesn't count as a collector failure — the run itself
+// succeeded.
+func (s *Scheduler) sweep(ctx context.Context, inst *instance) {
+	if inst.cfg.StaleAfter <= 0 && inst.cfg.RemoveAfter <= 0 {
+		return
+	}
+	interval := inst.cfg.Interval.Std()
+	staleAfter := time.Duration(inst.cfg.StaleAfter) * interval
+	removeAfter := time.Duration(inst.cfg.RemoveAfter) * interval
+	if err := s.reconciler.Sweep(ctx, inst.cfg.Name, staleAfter, removeAfter); err != nil {
+		s.log.Warn("scheduler: lifecycle sweep failed", "collector", inst.cfg.Name, "error", err)
+	}
+}
+
+// alertFailing routes a synthetic collector.failing event through the
+// sinks exactly when the failure count reaches the threshold, so
+// on-call hears "this source has been failing" once rather than on
+// every subsequent failure.
+func (s *Scheduler) alertFailing(name string, failures int, err error) {
+	e := &model.ChangeEvent{
+		Action: model.ActionCollectorFailing,
+		Source: name,
+		After: map[string]any{
+			"collector":            name,
+			"consecutive_failures": failures,
+			"last_error":           err.Error(),
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, sink := range s.sinks {
+		sink.Publish(e)
+	}
+}
+
+// Trigger runs the named collector immediately, returning the
+// reconcile report. force overrides the reconciler's snapshot guard.
+func (s *Scheduler) Trigger(ctx context.Context, name string, force bool) (*reconcile.Report, error) {
+	inst, err := s.instance(name)
+	if err != nil {
+		return nil, err
+	}
+	res, err := inst.collector.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.reconciler.Reconcile(ctx, inst.cfg.Name, res, force)
+}
+
+// Preview runs the named collector's fetch+parse path and returns the
+// raw CollectResult without reconciling, so operators can see exactly
+// what a source would yield. A non-empty payload is parsed directly
+// when the collector supports it.
+func (s *Scheduler) Preview(ctx context.Context, name string, payload []byte) (model.CollectResult, error) {
+	inst, err := s.instance(name)
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	if len(payload) > 0 {
+		p, ok := inst.collector.(Previewer)
+		if !ok {
+			return model.CollectResult{}, fmt.Errorf("collector %q does not accept payload uploads", name)
+		}
+		return p.Preview(ctx, payload)
+	}
+	return inst.collector.Collect(ctx)
+}
+
+func (s *Scheduler) instance(name string) (*instance, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	inst, ok := s.instances[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown collector %q", name)
+	}
+	return inst, nil
+}
+
+// Statuses returns a snapshot of every configured collector, sorted by
+// name on the API side.
+func (s *Scheduler) Statuses() []Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Status, 0, len(s.instances))
+	for _, inst := range s.instances {
+		inst.mu.Lock()
+		out = append(out, inst.status)
+		inst.mu.Unlock()
+	}
+	return out
+}
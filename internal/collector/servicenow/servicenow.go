@@ -0,0 +1,212 @@
+// Package servicenow imports configuration items from a ServiceNow
+// CMDB through the Table API. Which CI classes are read, and the asset
+// type each class maps to, is configured per instance, so the same
+// collector covers servers, VMs, network gear or anything else the
+// CMDB tracks. The companion exporter in internal/export/servicenow
+// pushes reconciled assets back the other way.
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("servicenow", New)
+}
+
+// classMapping pairs a CMDB table with the asset type its records
+// become.
+type classMapping struct {
+	class     string
+	assetType string
+}
+
+// Collector reads one ServiceNow instance.
+type Collector struct {
+	name     string
+	url      string
+	username string
+	password string
+	classes  []classMapping
+	client   *http.Client
+}
+
+// New builds the collector from config. Required settings: url (the
+// instance base URL), username, password. Optional: class_map, a
+// comma-separated list of "<ci class>=<asset type>" pairs; the default
+// is "cmdb_ci_server=host,cmdb_ci_vm_instance=vm".
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		url:      strings.TrimSuffix(cfg.Settings["url"], "/"),
+		username: cfg.Settings["username"],
+		password: cfg.Settings["password"],
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+	if c.url == "" || c.username == "" || c.password == "" {
+		return nil, fmt.Errorf("servicenow: url, username and password settings are required")
+	}
+	raw := cfg.Settings["class_map"]
+	if raw == "" {
+		raw = "cmdb_ci_server=host,cmdb_ci_vm_instance=vm"
+	}
+	var err error
+	c.classes, err = parseClassMap(raw)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseClassMap splits a "class=type,class=type" setting, keeping the
+// configured order so collection is deterministic.
+func parseClassMap(raw string) ([]classMapping, error) {
+	var classes []classMapping
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		class, assetType, ok := strings.Cut(pair, "=")
+		class, assetType = strings.TrimSpace(class), strings.TrimSpace(assetType)
+		if !ok || class == "" || assetType == "" {
+			return nil, fmt.Errorf("servicenow: invalid class_map entry %q, want <ci class>=<asset type>", pair)
+		}
+		classes = append(classes, classMapping{class: class, assetType: assetType})
+	}
+	if len(classes) == 0 {
+		return nil, fmt.Errorf("servicenow: class_map must list at least one <ci class>=<asset type> pair")
+	}
+	return classes, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// record is a CI row. Display values are requested so reference
+// fields (manufacturer, location) arrive as their names rather than
+// sys_id links.
+type record struct {
+	SysID         string `json:"sys_id"`
+	Name          string `json:"name"`
+	FQDN          string `json:"fqdn"`
+	HostName      string `json:"host_name"`
+	IPAddress     string `json:"ip_address"`
+	OS            string `json:"os"`
+	OSVersion     string `json:"os_version"`
+	SerialNumber  string `json:"serial_number"`
+	Manufacturer  string `json:"manufacturer"`
+	SysClassName  string `json:"sys_class_name"`
+	InstallStatus string `json:"install_status"`
+}
+
+const recordFields = "sys_id,name,fqdn,host_name,ip_address,os,os_version,serial_number,manufacturer,sys_class_name,install_status"
+
+// Collect pages through every configured class. External IDs are
+// sys_ids, which are unique across the whole instance.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+	for _, m := range c.classes {
+		if err := c.collectClass(ctx, m, &res); err != nil {
+			return model.CollectResult{}, err
+		}
+	}
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func (c *Collector) collectClass(ctx context.Context, m classMapping, res *model.CollectResult) error {
+	const limit = 1000
+	for offset := 0; ; offset += limit {
+		path := fmt.Sprintf("/api/now/table/%s?sysparm_fields=%s&sysparm_display_value=true&sysparm_limit=%d&sysparm_offset=%d",
+			url.PathEscape(m.class), recordFields, limit, offset)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(c.username, c.password)
+		req.Header.Set("Accept", "application/json")
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("servicenow: query %s: %w", m.class, err)
+		}
+		var page struct {
+			Result []record `json:"result"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("servicenow: %s returned status %d", m.class, resp.StatusCode)
+		}
+		if err != nil {
+			return fmt.Errorf("servicenow: decode %s: %w", m.class, err)
+		}
+		for _, rec := range page.Result {
+			res.Assets = append(res.Assets, recordToAsset(rec, m.assetType))
+		}
+		if len(page.Result) < limit {
+			return nil
+		}
+	}
+}
+
+func recordToAsset(rec record, assetType string) *model.Asset {
+	a := &model.Asset{
+		Name:       rec.Name,
+		AssetType:  assetType,
+		ExternalID: rec.SysID,
+		Attributes: map[string]any{},
+	}
+	if rec.FQDN != "" {
+		a.FQDN = rec.FQDN
+	} else if strings.Contains(rec.HostName, ".") {
+		a.FQDN = rec.HostName
+	}
+	if a.Name == "" {
+		a.Name = rec.HostName
+	}
+	if rec.IPAddress != "" {
+		a.IPs = []string{rec.IPAddress}
+	}
+	if rec.OS != "" {
+		os := rec.OS
+		if rec.OSVersion != "" {
+			os += " " + rec.OSVersion
+		}
+		a.Attributes["os"] = os
+	}
+	if rec.SerialNumber != "" {
+		a.Attributes["serial"] = rec.SerialNumber
+	}
+	if rec.Manufacturer != "" {
+		a.Attributes["manufacturer"] = rec.Manufacturer
+	}
+	if rec.SysClassName != "" {
+		a.Attributes["ci_class"] = rec.SysClassName
+	}
+	if rec.InstallStatus != "" {
+		a.Attributes["install_status"] = rec.InstallStatus
+	}
+	return a
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "instance base URL"},
+		{Name: "username", Type: collector.TypeString, Required: true, Description: "API user"},
+		{Name: "password", Type: collector.TypeString, Required: true, Description: "API password"},
+		{Name: "class_map", Type: collector.TypeString, Description: "CI class to asset type pairs (\"class=type\", comma-separated)"},
+	}
+}
@@ -0,0 +1,306 @@
+// Package snmp discovers network gear — switches, routers, anything
+// with an SNMP agent — that port scanners cannot fingerprint. For each
+// configured device it reads the system group, the interface table and
+// the VLAN names, and walks the ARP table to surface the neighbors the
+// device has seen, linked with CONNECTS_TO edges.
+package snmp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("snmp", New)
+}
+
+// Collector polls a fixed list of SNMP devices.
+type Collector struct {
+	name      string
+	devices   []string
+	community string
+	timeout   time.Duration
+}
+
+// New builds the collector from config. Required settings: devices (a
+// comma-separated list of host or host:port) and community (v2c).
+// Optional: timeout (Go duration, default 5s).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:      cfg.Name,
+		community: cfg.Settings["community"],
+		timeout:   5 * time.Second,
+	}
+	for _, d := range strings.Split(cfg.Settings["devices"], ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			c.devices = append(c.devices, d)
+		}
+	}
+	if len(c.devices) == 0 || c.community == "" {
+		return nil, fmt.Errorf("snmp: devices and community settings are required")
+	}
+	if raw := cfg.Settings["timeout"]; raw != "" {
+		t, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("snmp: invalid timeout %q: %w", raw, err)
+		}
+		c.timeout = t
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// The OIDs the collector reads; all from standard MIBs so any vendor's
+// gear answers.
+const (
+	oidSysDescr      = ".1.3.6.1.2.1.1.1.0"
+	oidSysName       = ".1.3.6.1.2.1.1.5.0"
+	oidIfDescr       = ".1.3.6.1.2.1.2.2.1.2"
+	oidIfSpeed       = ".1.3.6.1.2.1.2.2.1.5"
+	oidIfPhysAddr    = ".1.3.6.1.2.1.2.2.1.6"
+	oidIfOperStatus  = ".1.3.6.1.2.1.2.2.1.8"
+	oidArpPhysAddr   = ".1.3.6.1.2.1.4.22.1.2"
+	oidVlanStaticNam = ".1.3.6.1.2.1.17.7.1.4.3.1.1"
+)
+
+// Collect polls every device. A device that does not answer is counted
+// as filtered rather than failing the run — network gear is rebooted
+// and ACLed far too often to let one unreachable box wipe the source —
+// but a run where nothing answered is an error.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+	res.Stats.Fetched = len(c.devices)
+	var lastErr error
+	for _, device := range c.devices {
+		if err := ctx.Err(); err != nil {
+			return model.CollectResult{}, err
+		}
+		if err := c.collectDevice(device, &res); err != nil {
+			res.Stats.Filtered++
+			lastErr = fmt.Errorf("snmp: %s: %w", device, err)
+		}
+	}
+	if res.Stats.Filtered == len(c.devices) {
+		return model.CollectResult{}, lastErr
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func (c *Collector) collectDevice(device string, res *model.CollectResult) error {
+	host, port := device, uint16(161)
+	if h, p, err := net.SplitHostPort(device); err == nil {
+		host = h
+		if n, err := strconv.Atoi(p); err == nil {
+			port = uint16(n)
+		}
+	}
+	client := &gosnmp.GoSNMP{
+		Target:    host,
+		Port:      port,
+		Community: c.community,
+		Version:   gosnmp.Version2c,
+		Timeout:   c.timeout,
+		Retries:   1,
+	}
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Conn.Close()
+
+	sys, err := client.Get([]string{oidSysName, oidSysDescr})
+	if err != nil {
+		return fmt.Errorf("system group: %w", err)
+	}
+	var sysName, sysDescr string
+	for _, v := range sys.Variables {
+		switch v.Name {
+		case oidSysName:
+			sysName = pduString(v)
+		case oidSysDescr:
+			sysDescr = pduString(v)
+		}
+	}
+	if sysName == "" {
+		sysName = host
+	}
+	a := &model.Asset{
+		Name:       sysName,
+		AssetType:  deviceType(sysDescr),
+		ExternalID: sysName,
+		Attributes: map[string]any{"sys_descr": sysDescr},
+	}
+	if net.ParseIP(host) != nil {
+		a.IPs = []string{host}
+	}
+
+	ports, err := c.walkInterfaces(client)
+	if err != nil {
+		return err
+	}
+	if len(ports) > 0 {
+		a.Attributes["ports"] = ports
+	}
+	// VLANs come from the Q-BRIDGE MIB; routers and hosts simply have
+	// none.
+	var vlans []map[string]any
+	err = client.Walk(oidVlanStaticNam, func(pdu gosnmp.SnmpPDU) error {
+		vlans = append(vlans, map[string]any{
+			"id":   lastOIDIndex(pdu.Name),
+			"name": pduString(pdu),
+		})
+		return nil
+	})
+	if err == nil && len(vlans) > 0 {
+		a.Attributes["vlans"] = vlans
+	}
+	res.Assets = append(res.Assets, a)
+
+	// The ARP table names the neighbors this device has exchanged
+	// traffic with; they are emitted as thin host assets so scanners
+	// and agents can flesh them out later.
+	return client.Walk(oidArpPhysAddr, func(pdu gosnmp.SnmpPDU) error {
+		ip := arpIndexIP(pdu.Name)
+		if ip == "" {
+			return nil
+		}
+		mac, _ := pdu.Value.([]byte)
+		neighbor := &model.Asset{
+			Name:       ip,
+			AssetType:  "host",
+			ExternalID: ip,
+			IPs:        []string{ip},
+			MACs:       []string{net.HardwareAddr(mac).String()},
+		}
+		res.Assets = append(res.Assets, neighbor)
+		res.Relationships = append(res.Relationships, model.Relationship{
+			FromExternalID: neighbor.ExternalID,
+			ToExternalID:   a.ExternalID,
+			Type:           model.RelConnectsTo,
+			Properties:     map[string]any{"via": "arp"},
+		})
+		return nil
+	})
+}
+
+// walkInterfaces folds the relevant ifTable columns into one entry per
+// interface index.
+func (c *Collector) walkInterfaces(client *gosnmp.GoSNMP) ([]map[string]any, error) {
+	type iface struct {
+		descr  string
+		speed  uint
+		mac    string
+		status int
+	}
+	ifaces := map[int]*iface{}
+	at := func(pdu gosnmp.SnmpPDU) *iface {
+		idx, _ := strconv.Atoi(lastOIDIndex(pdu.Name))
+		if ifaces[idx] == nil {
+			ifaces[idx] = &iface{}
+		}
+		return ifaces[idx]
+	}
+	err := client.Walk(oidIfDescr, func(pdu gosnmp.SnmpPDU) error {
+		at(pdu).descr = pduString(pdu)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ifDescr: %w", err)
+	}
+	client.Walk(oidIfSpeed, func(pdu gosnmp.SnmpPDU) error {
+		at(pdu).speed = uint(gosnmp.ToBigInt(pdu.Value).Uint64())
+		return nil
+	})
+	client.Walk(oidIfPhysAddr, func(pdu gosnmp.SnmpPDU) error {
+		if mac, ok := pdu.Value.([]byte); ok && len(mac) == 6 {
+			at(pdu).mac = net.HardwareAddr(mac).String()
+		}
+		return nil
+	})
+	client.Walk(oidIfOperStatus, func(pdu gosnmp.SnmpPDU) error {
+		at(pdu).status = int(gosnmp.ToBigInt(pdu.Value).Int64())
+		return nil
+	})
+	indexes := make([]int, 0, len(ifaces))
+	for idx := range ifaces {
+		indexes = append(indexes, idx)
+	}
+	// Stable order keeps the attribute from churning between runs.
+	sort.Ints(indexes)
+	var out []map[string]any
+	for _, idx := range indexes {
+		f := ifaces[idx]
+		entry := map[string]any{"index": idx, "descr": f.descr, "oper_up": f.status == 1}
+		if f.speed > 0 {
+			entry["speed_bps"] = f.speed
+		}
+		if f.mac != "" {
+			entry["mac"] = f.mac
+		}
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
+// deviceType guesses switch vs router from the system description;
+// both are honest defaults, not vendor detection.
+func deviceType(sysDescr string) string {
+	if strings.Contains(strings.ToLower(sysDescr), "rout") {
+		return "router"
+	}
+	return "switch"
+}
+
+func pduString(pdu gosnmp.SnmpPDU) string {
+	switch v := pdu.Value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+// lastOIDIndex returns the final component of an OID.
+func lastOIDIndex(oid string) string {
+	if i := strings.LastIndexByte(oid, '.'); i >= 0 {
+		return oid[i+1:]
+	}
+	return oid
+}
+
+// arpIndexIP extracts the IP from an ipNetToMediaPhysAddress index,
+// whose last four components are the address.
+func arpIndexIP(oid string) string {
+	parts := strings.Split(oid, ".")
+	if len(parts) < 4 {
+		return ""
+	}
+	ip := strings.Join(parts[len(parts)-4:], ".")
+	if net.ParseIP(ip) == nil {
+		return ""
+	}
+	return ip
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "devices", Type: collector.TypeList, Required: true, Description: "devices to poll"},
+		{Name: "community", Type: collector.TypeString, Required: true, Description: "SNMP community"},
+		{Name: "timeout", Type: collector.TypeDuration, Description: "per-device timeout"},
+	}
+}
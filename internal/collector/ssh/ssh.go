@@ -0,0 +1,237 @@
+// Package ssh gathers facts from hosts over plain SSH: hostname, OS
+// and kernel, addresses, listening sockets and installed package
+// count. It is the agentless fallback for estates without Ansible —
+// same idea as the ansible collector's fact caches, but arch does the
+// connecting. Hosts that refuse the connection are tolerated; the run
+// only fails when no host answers.
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	xssh "golang.org/x/crypto/ssh"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("ssh", New)
+}
+
+// Collector connects to a fixed list of hosts.
+type Collector struct {
+	name    string
+	hosts   []string
+	config  *xssh.ClientConfig
+	timeout time.Duration
+}
+
+// New builds the collector from config. Required settings: hosts (a
+// comma-separated list of host or host:port) and username, plus one
+// of private_key (a path to an unencrypted key file) or password.
+// Optional: timeout (per-host, Go duration, default 15s). Host keys
+// are not verified — the collector talks to hundreds of machines
+// whose keys churn with reinstalls, which is the situation host-key
+// pinning cannot keep up with.
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:    cfg.Name,
+		timeout: 15 * time.Second,
+	}
+	for _, h := range strings.Split(cfg.Settings["hosts"], ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			if _, _, err := net.SplitHostPort(h); err != nil {
+				h = net.JoinHostPort(h, "22")
+			}
+			c.hosts = append(c.hosts, h)
+		}
+	}
+	username := cfg.Settings["username"]
+	if len(c.hosts) == 0 || username == "" {
+		return nil, fmt.Errorf("ssh: hosts and username settings are required")
+	}
+	var auth []xssh.AuthMethod
+	if path := cfg.Settings["private_key"]; path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("ssh: read private key: %w", err)
+		}
+		signer, err := xssh.ParsePrivateKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ssh: parse private key: %w", err)
+		}
+		auth = append(auth, xssh.PublicKeys(signer))
+	}
+	if pw := cfg.Settings["password"]; pw != "" {
+		auth = append(auth, xssh.Password(pw))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("ssh: one of private_key and password settings is required")
+	}
+	if raw := cfg.Settings["timeout"]; raw != "" {
+		t, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ssh: invalid timeout %q: %w", raw, err)
+		}
+		c.timeout = t
+	}
+	c.config = &xssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: xssh.InsecureIgnoreHostKey(),
+		Timeout:         c.timeout,
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect connects to every host and runs the fact commands.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+	res.Stats.Fetched = len(c.hosts)
+	var lastErr error
+	for _, host := range c.hosts {
+		if err := ctx.Err(); err != nil {
+			return model.CollectResult{}, err
+		}
+		asset, err := c.collectHost(host)
+		if err != nil {
+			res.Stats.Filtered++
+			lastErr = fmt.Errorf("ssh: %s: %w", host, err)
+			continue
+		}
+		res.Assets = append(res.Assets, asset)
+	}
+	if res.Stats.Filtered == len(c.hosts) {
+		return model.CollectResult{}, lastErr
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// run executes one command in its own session and returns trimmed
+// stdout; a failing command yields "" rather than an error, since the
+// fact commands differ across distributions and absence is expected.
+func run(client *xssh.Client, cmd string) string {
+	session, err := client.NewSession()
+	if err != nil {
+		return ""
+	}
+	defer session.Close()
+	out, err := session.Output(cmd)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (c *Collector) collectHost(host string) (*model.Asset, error) {
+	client, err := xssh.Dial("tcp", host, c.config)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	target, _, _ := net.SplitHostPort(host)
+	a := &model.Asset{
+		Name:       target,
+		AssetType:  "host",
+		ExternalID: target,
+		Attributes: map[string]any{},
+	}
+	if net.ParseIP(target) != nil {
+		a.IPs = []string{target}
+	}
+
+	if fqdn := run(client, "hostname -f"); fqdn != "" {
+		a.Name = strings.SplitN(fqdn, ".", 2)[0]
+		if strings.Contains(fqdn, ".") {
+			a.FQDN = strings.ToLower(fqdn)
+		}
+	}
+	if uname := run(client, "uname -sr"); uname != "" {
+		a.Attributes["kernel"] = uname
+	}
+	// PRETTY_NAME is the closest thing to a portable OS label.
+	if osRelease := run(client, `. /etc/os-release 2>/dev/null && echo "$PRETTY_NAME"`); osRelease != "" {
+		a.Attributes["os"] = osRelease
+	}
+
+	// Global IPv4 addresses; the format is stable across iproute2
+	// versions ("2: eth0    inet 10.0.0.5/24 ...").
+	for _, line := range strings.Split(run(client, "ip -o -4 addr show scope global"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		ip, _, _ := strings.Cut(fields[3], "/")
+		if net.ParseIP(ip) == nil {
+			continue
+		}
+		dup := false
+		for _, existing := range a.IPs {
+			if existing == ip {
+				dup = true
+			}
+		}
+		if !dup {
+			a.IPs = append(a.IPs, ip)
+		}
+	}
+
+	// Listening TCP ports, deduplicated across addresses.
+	if out := run(client, "ss -Htln"); out != "" {
+		seen := map[string]bool{}
+		var ports []string
+		for _, line := range strings.Split(out, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				continue
+			}
+			_, port, _ := cutLast(fields[3], ':')
+			if port != "" && !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+		if len(ports) > 0 {
+			a.Attributes["listening_ports"] = strings.Join(ports, ",")
+		}
+	}
+
+	if count := run(client, "dpkg-query -W 2>/dev/null | wc -l"); count != "" && count != "0" {
+		a.Attributes["package_count"] = count
+	} else if count := run(client, "rpm -qa 2>/dev/null | wc -l"); count != "" && count != "0" {
+		a.Attributes["package_count"] = count
+	}
+	return a, nil
+}
+
+// cutLast splits around the last occurrence of sep, which is what
+// "[::]:22"-style socket addresses need.
+func cutLast(s string, sep byte) (string, string, bool) {
+	if i := strings.LastIndexByte(s, sep); i >= 0 {
+		return s[:i], s[i+1:], true
+	}
+	return s, "", false
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "hosts", Type: collector.TypeList, Required: true, Description: "hosts to connect to"},
+		{Name: "username", Type: collector.TypeString, Required: true, Description: "login user"},
+		{Name: "private_key", Type: collector.TypeString, Description: "unencrypted key file (one of private_key and password)"},
+		{Name: "password", Type: collector.TypeString, Description: "login password (one of private_key and password)"},
+		{Name: "timeout", Type: collector.TypeDuration, Description: "per-host timeout (default 15s)"},
+	}
+}
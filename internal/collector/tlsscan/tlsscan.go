@@ -0,0 +1,177 @@
+// Package tlsscan connects to a configured list of host:port targets,
+// pulls the certificate each one presents and emits ssl_certificate
+// assets with a DEPENDS_ON edge from the service to its certificate.
+// Certificates are keyed by fingerprint, so many services fronted by
+// the same wildcard cert share one asset — which is exactly the view
+// needed when that cert is about to expire. The netscaler and f5
+// collectors report certs the load balancers know about; this one
+// covers everything else.
+package tlsscan
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("tlsscan", New)
+}
+
+// Collector scans a fixed target list.
+type Collector struct {
+	name    string
+	targets []string
+	timeout time.Duration
+}
+
+// New builds the collector from config. Required setting: targets, a
+// comma-separated list of host:port (":443" is assumed when the port
+// is missing). Optional: timeout (per-connection, Go duration,
+// default 10s).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:    cfg.Name,
+		timeout: 10 * time.Second,
+	}
+	for _, t := range strings.Split(cfg.Settings["targets"], ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			if _, _, err := net.SplitHostPort(t); err != nil {
+				t = net.JoinHostPort(t, "443")
+			}
+			c.targets = append(c.targets, t)
+		}
+	}
+	if len(c.targets) == 0 {
+		return nil, fmt.Errorf("tlsscan: targets setting is required")
+	}
+	if raw := cfg.Settings["timeout"]; raw != "" {
+		t, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("tlsscan: invalid timeout %q: %w", raw, err)
+		}
+		c.timeout = t
+	}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect scans every target. Unreachable targets are counted as
+// filtered rather than failing the run — a service being down is
+// routine — but a run where nothing answered is refused so it cannot
+// empty the source.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var res model.CollectResult
+	res.Stats.Fetched = len(c.targets)
+	certs := map[string]bool{}
+	var lastErr error
+	for _, target := range c.targets {
+		if err := ctx.Err(); err != nil {
+			return model.CollectResult{}, err
+		}
+		if err := c.scanTarget(ctx, target, certs, &res); err != nil {
+			res.Stats.Filtered++
+			lastErr = fmt.Errorf("tlsscan: %s: %w", target, err)
+		}
+	}
+	if res.Stats.Filtered == len(c.targets) {
+		return model.CollectResult{}, lastErr
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+func (c *Collector) scanTarget(ctx context.Context, target string, certs map[string]bool, res *model.CollectResult) error {
+	host, _, _ := net.SplitHostPort(target)
+	dialer := &net.Dialer{Timeout: c.timeout}
+	// Verification is skipped deliberately: an expired or self-signed
+	// certificate is a finding, not a reason to drop the target.
+	conn, err := tls.DialWithDialer(dialer, "tcp", target, &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         host,
+	})
+	if err != nil {
+		return err
+	}
+	state := conn.ConnectionState()
+	conn.Close()
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("no certificate presented")
+	}
+	leaf := state.PeerCertificates[0]
+
+	serviceID := "service/" + target
+	svc := &model.Asset{
+		Name:       target,
+		AssetType:  "service",
+		ExternalID: serviceID,
+		Attributes: map[string]any{"tls_version": tls.VersionName(state.Version)},
+	}
+	if net.ParseIP(host) != nil {
+		svc.IPs = []string{host}
+	} else if strings.Contains(host, ".") {
+		svc.FQDN = host
+	}
+	res.Assets = append(res.Assets, svc)
+
+	certID := certAsset(leaf, certs, res)
+	res.Relationships = append(res.Relationships, model.Relationship{
+		FromExternalID: serviceID,
+		ToExternalID:   certID,
+		Type:           model.RelDependsOn,
+	})
+	return nil
+}
+
+// certAsset emits the certificate once per fingerprint and returns its
+// external ID.
+func certAsset(leaf *x509.Certificate, certs map[string]bool, res *model.CollectResult) string {
+	sum := sha256.Sum256(leaf.Raw)
+	id := "cert/" + hex.EncodeToString(sum[:])
+	if certs[id] {
+		return id
+	}
+	certs[id] = true
+	a := &model.Asset{
+		Name:       leaf.Subject.CommonName,
+		AssetType:  "ssl_certificate",
+		ExternalID: id,
+		Attributes: map[string]any{
+			"issuer":     leaf.Issuer.CommonName,
+			"not_before": leaf.NotBefore.UTC().Format(time.RFC3339),
+			"not_after":  leaf.NotAfter.UTC().Format(time.RFC3339),
+			"days_left":  int(time.Until(leaf.NotAfter).Hours() / 24),
+		},
+	}
+	if a.Name == "" && len(leaf.DNSNames) > 0 {
+		a.Name = leaf.DNSNames[0]
+	}
+	if len(leaf.DNSNames) > 0 {
+		a.Attributes["san"] = strings.Join(leaf.DNSNames, ",")
+	}
+	if leaf.Issuer.CommonName == leaf.Subject.CommonName {
+		a.Attributes["self_signed"] = true
+	}
+	res.Assets = append(res.Assets, a)
+	return id
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "targets", Type: collector.TypeList, Required: true, Description: "host:port endpoints to probe"},
+		{Name: "timeout", Type: collector.TypeDuration, Description: "per-target timeout (default 10s)"},
+	}
+}
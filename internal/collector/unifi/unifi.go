@@ -0,0 +1,231 @@
+// Package unifi imports a Ubiquiti UniFi controller site: access
+// points, switches and gateways become assets, and every connected
+// client becomes a thin host (keyed by MAC, carrying its IP and
+// hostname) with a CONNECTS_TO edge to the device it is attached to.
+// Branch offices rarely have any other source that knows what is
+// plugged in where, which is the gap this collector fills.
+package unifi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("unifi", New)
+}
+
+// Collector reads one controller site.
+type Collector struct {
+	name     string
+	url      string
+	username string
+	password string
+	site     string
+	client   *http.Client
+}
+
+// New builds the collector from config. Required settings: url (the
+// controller base URL), username, password. Optional: site (defaults
+// to "default") and insecure ("true" skips TLS verification —
+// controllers ship with self-signed certificates).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:     cfg.Name,
+		url:      strings.TrimSuffix(cfg.Settings["url"], "/"),
+		username: cfg.Settings["username"],
+		password: cfg.Settings["password"],
+		site:     cfg.Settings["site"],
+	}
+	if c.url == "" || c.username == "" || c.password == "" {
+		return nil, fmt.Errorf("unifi: url, username and password settings are required")
+	}
+	if c.site == "" {
+		c.site = "default"
+	}
+	transport := &http.Transport{}
+	if cfg.Settings["insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	// The controller authenticates with a session cookie.
+	jar, _ := cookiejar.New(nil)
+	c.client = &http.Client{Timeout: 60 * time.Second, Transport: transport, Jar: jar}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+func (c *Collector) login(ctx context.Context) error {
+	body, _ := json.Marshal(map[string]string{"username": c.username, "password": c.password})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/api/login", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unifi: login: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unifi: login returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *Collector) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unifi: %s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("unifi: decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// deviceTypes maps the controller's short type codes to asset types.
+var deviceTypes = map[string]string{
+	"uap": "access_point",
+	"usw": "switch",
+	"ugw": "router",
+	"udm": "router",
+}
+
+// Collect logs in, then lists the site's devices and active clients.
+// External IDs are MAC addresses, the one identifier both endpoints
+// share.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	if err := c.login(ctx); err != nil {
+		return model.CollectResult{}, err
+	}
+	var res model.CollectResult
+
+	var devices struct {
+		Data []struct {
+			MAC     string `json:"mac"`
+			Type    string `json:"type"`
+			Name    string `json:"name"`
+			IP      string `json:"ip"`
+			Model   string `json:"model"`
+			Version string `json:"version"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/api/s/"+c.site+"/stat/device", &devices); err != nil {
+		return model.CollectResult{}, err
+	}
+	known := map[string]bool{}
+	for _, d := range devices.Data {
+		assetType := deviceTypes[d.Type]
+		if assetType == "" {
+			assetType = "network_device"
+		}
+		a := &model.Asset{
+			Name:       d.Name,
+			AssetType:  assetType,
+			ExternalID: d.MAC,
+			MACs:       []string{d.MAC},
+			Attributes: map[string]any{
+				"model":   d.Model,
+				"version": d.Version,
+			},
+		}
+		if a.Name == "" {
+			a.Name = d.MAC
+		}
+		if net.ParseIP(d.IP) != nil {
+			a.IPs = []string{d.IP}
+		}
+		known[d.MAC] = true
+		res.Assets = append(res.Assets, a)
+	}
+
+	var clients struct {
+		Data []struct {
+			MAC      string `json:"mac"`
+			IP       string `json:"ip"`
+			Hostname string `json:"hostname"`
+			Name     string `json:"name"`
+			ESSID    string `json:"essid"`
+			APMAC    string `json:"ap_mac"`
+			SwMAC    string `json:"sw_mac"`
+			IsWired  bool   `json:"is_wired"`
+		} `json:"data"`
+	}
+	if err := c.get(ctx, "/api/s/"+c.site+"/stat/sta", &clients); err != nil {
+		return model.CollectResult{}, err
+	}
+	for _, cl := range clients.Data {
+		name := cl.Name
+		if name == "" {
+			name = cl.Hostname
+		}
+		if name == "" {
+			name = cl.MAC
+		}
+		a := &model.Asset{
+			Name:       name,
+			AssetType:  "host",
+			ExternalID: cl.MAC,
+			MACs:       []string{cl.MAC},
+			Attributes: map[string]any{"wired": cl.IsWired},
+		}
+		if net.ParseIP(cl.IP) != nil {
+			a.IPs = []string{cl.IP}
+		}
+		res.Assets = append(res.Assets, a)
+
+		uplink := cl.APMAC
+		props := map[string]any{}
+		if cl.ESSID != "" {
+			props["essid"] = cl.ESSID
+		}
+		if cl.IsWired {
+			uplink = cl.SwMAC
+		}
+		if uplink != "" && known[uplink] {
+			res.Relationships = append(res.Relationships, model.Relationship{
+				FromExternalID: cl.MAC,
+				ToExternalID:   uplink,
+				Type:           model.RelConnectsTo,
+				Properties:     props,
+			})
+		}
+	}
+
+	res.Stats.Fetched = len(res.Assets)
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "controller base URL"},
+		{Name: "username", Type: collector.TypeString, Required: true, Description: "controller user"},
+		{Name: "password", Type: collector.TypeString, Required: true, Description: "controller password"},
+		{Name: "site", Type: collector.TypeString, Description: "controller site (default \"default\")"},
+		{Name: "insecure", Type: collector.TypeBool, Description: "skip TLS verification"},
+	}
+}
@@ -0,0 +1,235 @@
+package vmware
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// watchedKinds maps each inventory kind to the properties both the
+// full walk and the incremental filter retrieve.
+var watchedKinds = map[string][]string{
+	"ClusterComputeResource": {"name", "summary"},
+	"HostSystem":             {"name", "summary", "parent"},
+	"VirtualMachine":         {"name", "summary", "runtime.host"},
+	"Datastore":              {"name", "summary"},
+}
+
+// session is the vCenter connection incremental mode keeps alive
+// between runs: the property collector's update stream is only
+// meaningful against the filter and version it was established with.
+type session struct {
+	cli     *govmomi.Client
+	view    *view.ContainerView
+	pc      *property.Collector
+	version string
+	// runs counts incremental runs since the last baseline, so the
+	// collector periodically falls back to a full enumeration in case
+	// the stream silently missed something.
+	runs int
+}
+
+func (s *session) close() {
+	// The run's context may already be cancelled; tear down anyway.
+	ctx := context.Background()
+	if s.pc != nil {
+		_ = s.pc.Destroy(ctx)
+	}
+	if s.view != nil {
+		_ = s.view.Destroy(ctx)
+	}
+	if s.cli != nil {
+		_ = s.cli.Logout(ctx)
+	}
+}
+
+// connect logs in and sets up a property filter over a container view
+// of the watched kinds, against which WaitForUpdates reports changes.
+func (c *Collector) connect(ctx context.Context) (*session, error) {
+	u, err := soap.ParseURL(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("vmware: parse url: %w", err)
+	}
+	u.User = url.UserPassword(c.username, c.password)
+	cli, err := govmomi.NewClient(ctx, u, c.insecure)
+	if err != nil {
+		return nil, fmt.Errorf("vmware: connect: %w", err)
+	}
+	s := &session{cli: cli}
+
+	kinds := make([]string, 0, len(watchedKinds))
+	for kind := range watchedKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	m := view.NewManager(cli.Client)
+	s.view, err = m.CreateContainerView(ctx, cli.ServiceContent.RootFolder, kinds, true)
+	if err != nil {
+		s.close()
+		return nil, fmt.Errorf("vmware: create view: %w", err)
+	}
+	s.pc, err = property.DefaultCollector(cli.Client).Create(ctx)
+	if err != nil {
+		s.close()
+		return nil, fmt.Errorf("vmware: create property collector: %w", err)
+	}
+	filter := types.CreateFilter{Spec: types.PropertyFilterSpec{
+		ObjectSet: []types.ObjectSpec{{
+			Obj:  s.view.Reference(),
+			Skip: types.NewBool(true),
+			SelectSet: []types.BaseSelectionSpec{&types.TraversalSpec{
+				Type: s.view.Reference().Type,
+				Path: "view",
+			}},
+		}},
+	}}
+	for _, kind := range kinds {
+		filter.Spec.PropSet = append(filter.Spec.PropSet, types.PropertySpec{
+			Type:    kind,
+			PathSet: watchedKinds[kind],
+		})
+	}
+	if err := s.pc.CreateFilter(ctx, filter); err != nil {
+		s.close()
+		return nil, fmt.Errorf("vmware: create filter: %w", err)
+	}
+	return s, nil
+}
+
+// collectIncremental drains the pending updates since the last run and
+// emits only the changed objects. The first run against a fresh
+// session is a baseline: every object arrives as an "enter" update, so
+// it is a full (non-partial) snapshot; sessions are recycled every
+// fullSyncEvery runs to force a fresh baseline.
+func (c *Collector) collectIncremental(ctx context.Context) (model.CollectResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sess != nil && c.sess.runs >= c.fullSyncEvery {
+		c.sess.close()
+		c.sess = nil
+	}
+	if c.sess == nil {
+		s, err := c.connect(ctx)
+		if err != nil {
+			return model.CollectResult{}, err
+		}
+		c.sess = s
+	}
+	s := c.sess
+
+	var res model.CollectResult
+	res.Partial = s.version != ""
+
+	changed := map[types.ManagedObjectReference]bool{}
+	for {
+		set, err := s.pc.WaitForUpdates(ctx, s.version, &types.WaitOptions{MaxWaitSeconds: types.NewInt32(0)})
+		if err != nil {
+			// The session or filter is gone; reconnect on the next run.
+			s.close()
+			c.sess = nil
+			return model.CollectResult{}, fmt.Errorf("vmware: wait for updates: %w", err)
+		}
+		if set == nil {
+			break // drained
+		}
+		s.version = set.Version
+		for _, fs := range set.FilterSet {
+			for _, ou := range fs.ObjectSet {
+				if ou.Kind == types.ObjectUpdateKindLeave {
+					// Departures surface as absences at the next
+					// baseline; a partial run cannot report them.
+					res.Stats.Filtered++
+					delete(changed, ou.Obj)
+					continue
+				}
+				changed[ou.Obj] = true
+			}
+		}
+	}
+	s.runs++
+	res.Stats.Fetched = len(changed) + res.Stats.Filtered
+
+	byKind := map[string][]types.ManagedObjectReference{}
+	for ref := range changed {
+		byKind[ref.Type] = append(byKind[ref.Type], ref)
+	}
+	if err := c.emitChanged(ctx, s, byKind, &res); err != nil {
+		s.close()
+		c.sess = nil
+		return model.CollectResult{}, err
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// emitChanged retrieves the full property set for the changed objects
+// of each kind and maps them to assets and edges exactly like the full
+// walk does.
+func (c *Collector) emitChanged(ctx context.Context, s *session, byKind map[string][]types.ManagedObjectReference, res *model.CollectResult) error {
+	if refs := byKind["ClusterComputeResource"]; len(refs) > 0 {
+		var clusters []mo.ClusterComputeResource
+		if err := s.pc.Retrieve(ctx, refs, watchedKinds["ClusterComputeResource"], &clusters); err != nil {
+			return fmt.Errorf("vmware: retrieve clusters: %w", err)
+		}
+		for _, cl := range clusters {
+			res.Assets = append(res.Assets, clusterToAsset(cl))
+		}
+	}
+	if refs := byKind["HostSystem"]; len(refs) > 0 {
+		var hosts []mo.HostSystem
+		if err := s.pc.Retrieve(ctx, refs, watchedKinds["HostSystem"], &hosts); err != nil {
+			return fmt.Errorf("vmware: retrieve hosts: %w", err)
+		}
+		for _, h := range hosts {
+			res.Assets = append(res.Assets, hostToAsset(h))
+			if h.Parent != nil && h.Parent.Type == "ClusterComputeResource" {
+				res.Relationships = append(res.Relationships, model.Relationship{
+					FromExternalID: h.Self.Value,
+					ToExternalID:   h.Parent.Value,
+					Type:           model.RelMemberOf,
+				})
+			}
+		}
+	}
+	if refs := byKind["VirtualMachine"]; len(refs) > 0 {
+		var vms []mo.VirtualMachine
+		if err := s.pc.Retrieve(ctx, refs, watchedKinds["VirtualMachine"], &vms); err != nil {
+			return fmt.Errorf("vmware: retrieve vms: %w", err)
+		}
+		for _, vm := range vms {
+			if vm.Summary.Config.Template {
+				res.Stats.Filtered++
+				continue
+			}
+			res.Assets = append(res.Assets, vmToAsset(vm))
+			if vm.Runtime.Host != nil {
+				res.Relationships = append(res.Relationships, model.Relationship{
+					FromExternalID: vm.Self.Value,
+					ToExternalID:   vm.Runtime.Host.Value,
+					Type:           model.RelRunsOn,
+				})
+			}
+		}
+	}
+	if refs := byKind["Datastore"]; len(refs) > 0 {
+		var datastores []mo.Datastore
+		if err := s.pc.Retrieve(ctx, refs, watchedKinds["Datastore"], &datastores); err != nil {
+			return fmt.Errorf("vmware: retrieve datastores: %w", err)
+		}
+		for _, ds := range datastores {
+			res.Assets = append(res.Assets, datastoreToAsset(ds))
+		}
+	}
+	return nil
+}
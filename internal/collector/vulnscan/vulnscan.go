@@ -0,0 +1,111 @@
+// Package vulnscan imports scanned hosts from a vulnerability scanner
+// — Qualys VMDR or Nessus — with per-severity finding counts as asset
+// attributes. The scanners see every address they are pointed at, so a
+// host only the scanner knows about still enters the inventory, and
+// hosts other sources already track pick up their vulnerability
+// posture through the reconciler's IP and FQDN matching.
+package vulnscan
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("vulnscan", New)
+}
+
+// Collector reads one scanner instance through a backend-specific
+// API client.
+type Collector struct {
+	name    string
+	backend string
+	url     string
+	// Qualys uses basic auth; Nessus uses API keys.
+	username  string
+	password  string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// New builds the collector from config. Required settings: backend
+// ("qualys" or "nessus") and url. Qualys needs username and password;
+// Nessus needs access_key and secret_key. Optional: insecure ("true"
+// skips TLS verification — common on on-prem Nessus appliances).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	c := &Collector{
+		name:      cfg.Name,
+		backend:   cfg.Settings["backend"],
+		url:       strings.TrimSuffix(cfg.Settings["url"], "/"),
+		username:  cfg.Settings["username"],
+		password:  cfg.Settings["password"],
+		accessKey: cfg.Settings["access_key"],
+		secretKey: cfg.Settings["secret_key"],
+	}
+	if c.url == "" {
+		return nil, fmt.Errorf("vulnscan: url setting is required")
+	}
+	switch c.backend {
+	case "qualys":
+		if c.username == "" || c.password == "" {
+			return nil, fmt.Errorf("vulnscan: qualys backend requires username and password settings")
+		}
+	case "nessus":
+		if c.accessKey == "" || c.secretKey == "" {
+			return nil, fmt.Errorf("vulnscan: nessus backend requires access_key and secret_key settings")
+		}
+	default:
+		return nil, fmt.Errorf("vulnscan: backend setting must be \"qualys\" or \"nessus\", got %q", c.backend)
+	}
+	transport := &http.Transport{}
+	if cfg.Settings["insecure"] == "true" {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c.client = &http.Client{Timeout: 120 * time.Second, Transport: transport}
+	return c, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+// Collect dispatches to the configured backend. Both produce host
+// assets with vuln_critical/vuln_high/vuln_medium/vuln_low counts.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	switch c.backend {
+	case "qualys":
+		return c.collectQualys(ctx)
+	default:
+		return c.collectNessus(ctx)
+	}
+}
+
+// severityCounts folds per-severity tallies into the attribute names
+// shared by both backends.
+func severityCounts(a *model.Asset, critical, high, medium, low int) {
+	a.Attributes["vuln_critical"] = critical
+	a.Attributes["vuln_high"] = high
+	a.Attributes["vuln_medium"] = medium
+	a.Attributes["vuln_low"] = low
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "backend", Type: collector.TypeString, Required: true, Description: "\"qualys\" or \"nessus\""},
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "scanner API base URL"},
+		{Name: "username", Type: collector.TypeString, Description: "API user (qualys backend)"},
+		{Name: "password", Type: collector.TypeString, Description: "API password (qualys backend)"},
+		{Name: "access_key", Type: collector.TypeString, Description: "API access key (nessus backend)"},
+		{Name: "secret_key", Type: collector.TypeString, Description: "API secret key (nessus backend)"},
+		{Name: "insecure", Type: collector.TypeBool, Description: "skip TLS verification"},
+	}
+}
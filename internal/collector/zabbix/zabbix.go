@@ -0,0 +1,150 @@
+// Package zabbix imports monitored hosts from the Zabbix JSON-RPC API.
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/qrunner/arch/internal/collector"
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+func init() {
+	collector.Register("zabbix", New)
+}
+
+// Collector reads hosts and their interfaces from a Zabbix server.
+type Collector struct {
+	name   string
+	url    string
+	token  string
+	client *http.Client
+}
+
+// New builds the collector from config. Required settings: url (the
+// api_jsonrpc.php endpoint) and token (an API token).
+func New(cfg config.CollectorConfig) (collector.Collector, error) {
+	url := cfg.Settings["url"]
+	token := cfg.Settings["token"]
+	if url == "" || token == "" {
+		return nil, fmt.Errorf("zabbix: url and token settings are required")
+	}
+	return &Collector{
+		name:   cfg.Name,
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Name implements collector.Collector.
+func (c *Collector) Name() string { return c.name }
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+	ID      int    `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("zabbix API error %d: %s %s", e.Code, e.Message, e.Data)
+}
+
+func (c *Collector) call(ctx context.Context, method string, params, result any) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", Method: method, Params: params, ID: 1})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json-rpc")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("zabbix: decode response: %w", err)
+	}
+	if envelope.Error != nil {
+		return envelope.Error
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
+
+type zabbixHost struct {
+	HostID     string `json:"hostid"`
+	Host       string `json:"host"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Interfaces []struct {
+		IP  string `json:"ip"`
+		DNS string `json:"dns"`
+	} `json:"interfaces"`
+}
+
+// Collect fetches all hosts with their interfaces.
+func (c *Collector) Collect(ctx context.Context) (model.CollectResult, error) {
+	var hosts []zabbixHost
+	err := c.call(ctx, "host.get", map[string]any{
+		"output":           []string{"hostid", "host", "name", "status"},
+		"selectInterfaces": []string{"ip", "dns"},
+	}, &hosts)
+	if err != nil {
+		return model.CollectResult{}, err
+	}
+	var res model.CollectResult
+	res.Stats.Fetched = len(hosts)
+	for _, h := range hosts {
+		a := &model.Asset{
+			Name:       h.Name,
+			AssetType:  "host",
+			ExternalID: h.HostID,
+			Attributes: map[string]any{
+				"zabbix_host": h.Host,
+				"monitored":   h.Status == "0",
+			},
+		}
+		if a.Name == "" {
+			a.Name = h.Host
+		}
+		for _, iface := range h.Interfaces {
+			if iface.IP != "" && iface.IP != "127.0.0.1" {
+				a.IPs = append(a.IPs, iface.IP)
+			}
+			if a.FQDN == "" && iface.DNS != "" {
+				a.FQDN = iface.DNS
+			}
+		}
+		res.Assets = append(res.Assets, a)
+	}
+	res.Stats.Emitted = len(res.Assets)
+	return res, nil
+}
+
+// Schema implements collector.Collector.
+func (c *Collector) Schema() collector.Schema {
+	return collector.Schema{
+		{Name: "url", Type: collector.TypeString, Required: true, Description: "frontend base URL"},
+		{Name: "token", Type: collector.TypeString, Required: true, Description: "API token"},
+	}
+}
@@ -0,0 +1,353 @@
+// Package config loads the archd configuration file.
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration that unmarshals from YAML strings like
+// "30m" or "500ms".
+type Duration time.Duration
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var raw string
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", raw, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Std returns the value as a time.Duration.
+func (d Duration) Std() time.Duration { return time.Duration(d) }
+
+// Config is the top-level archd configuration, loaded from a single
+// YAML file at startup.
+type Config struct {
+	Listen     string            `yaml:"listen"`
+	Postgres   PostgresConfig    `yaml:"postgres"`
+	Neo4j      Neo4jConfig       `yaml:"neo4j"`
+	NATS       NATSConfig        `yaml:"nats"`
+	Auth       AuthConfig        `yaml:"auth"`
+	Collectors []CollectorConfig `yaml:"collectors"`
+	Notify     NotifyConfig      `yaml:"notify"`
+	Export     ExportConfig      `yaml:"export"`
+	Reconcile  ReconcileConfig   `yaml:"reconcile"`
+	Enrich     EnrichConfig      `yaml:"enrich"`
+	Debug      DebugConfig       `yaml:"debug"`
+}
+
+// DebugConfig enables operational debugging facilities.
+type DebugConfig struct {
+	// EnablePprof mounts net/http/pprof under /debug/pprof, protected
+	// by the admin auth scope. Off by default.
+	EnablePprof bool `yaml:"enable_pprof"`
+}
+
+// EnrichConfig configures optional asset enrichment steps applied during
+// reconciliation.
+type EnrichConfig struct {
+	ReverseDNS ReverseDNSConfig `yaml:"reverse_dns"`
+	// Sites maps IP ranges to site names; assets get the site of the
+	// first rule matching one of their IPs.
+	Sites []SiteRuleConfig `yaml:"sites"`
+}
+
+// SiteRuleConfig is one CIDR-to-site mapping rule.
+type SiteRuleConfig struct {
+	CIDR string `yaml:"cidr"`
+	Site string `yaml:"site"`
+}
+
+// ReverseDNSConfig configures PTR lookups for assets that arrive with an
+// IP but no FQDN. Disabled by default since it adds latency and DNS
+// load to every run.
+type ReverseDNSConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Resolver is a host:port DNS server address; empty uses the system
+	// resolver.
+	Resolver string `yaml:"resolver"`
+	// Concurrency bounds in-flight lookups. Defaults to 8.
+	Concurrency int `yaml:"concurrency"`
+	// Timeout is the per-lookup deadline. Defaults to 2s.
+	Timeout Duration `yaml:"timeout"`
+	// CacheTTL is how long results — including negative ones — are
+	// cached. Defaults to 1h.
+	CacheTTL Duration `yaml:"cache_ttl"`
+}
+
+// ReconcileConfig tunes the reconciler.
+type ReconcileConfig struct {
+	// BatchSize chunks large collector runs: results are applied and
+	// reported in batches of this many assets, so a mid-run failure is
+	// isolated to one batch. Defaults to 500.
+	BatchSize int `yaml:"batch_size"`
+	// Workers is the number of assets the reconciler applies
+	// concurrently within a batch. Defaults to 4.
+	Workers int `yaml:"workers"`
+	// NameCollisionPolicy controls how assets sharing a name are
+	// disambiguated in the derived display_name field: "none" (the
+	// default), "append-source" or "append-ip". The raw name is never
+	// changed.
+	NameCollisionPolicy string `yaml:"name_collision_policy"`
+	// MaxDropPercent rejects a run reporting more than this percentage
+	// fewer assets than the source currently has, so a transient source
+	// failure can't gut its inventory. Defaults to 50; 100 disables the
+	// drop check (empty runs are still refused).
+	MaxDropPercent int `yaml:"max_drop_percent"`
+	// MatchThreshold is the minimum matcher confidence (0–1] required
+	// to merge an incoming asset into an existing one; weaker matches
+	// are recorded as merge candidates for review. Defaults to 0.7.
+	MatchThreshold float64 `yaml:"match_threshold"`
+	// Authority grants sources exclusive say over a field: the key is
+	// a first-class field (name, fqdn, asset_type, site) or an
+	// attribute name, the value the sources allowed to set it in
+	// precedence order, e.g.
+	//
+	//   authority:
+	//     name: [vmware, netbox]
+	//     os: [ansible]
+	//
+	// Fields without a rule keep last-writer-wins merging.
+	Authority map[string][]string `yaml:"authority"`
+	// Normalize configures the cleanup stage applied to every incoming
+	// asset before matching.
+	Normalize NormalizeConfig `yaml:"normalize"`
+}
+
+// NormalizeConfig declares source-independent cleanup rules for
+// incoming assets, e.g.
+//
+//	normalize:
+//	  lowercase_fqdn: true
+//	  strip_domains: [corp.example.com]
+//	  asset_types:
+//	    "Virtual Machine": vm
+//	  drop_ips: [loopback, link-local, 169.254.0.0/16]
+//	  rename_attributes:
+//	    serialNumber: serial
+type NormalizeConfig struct {
+	LowercaseFQDN bool              `yaml:"lowercase_fqdn"`
+	StripDomains  []string          `yaml:"strip_domains"`
+	AssetTypes    map[string]string `yaml:"asset_types"`
+	// DropIPs accepts the classes "loopback" and "link-local", or CIDR
+	// blocks.
+	DropIPs          []string          `yaml:"drop_ips"`
+	RenameAttributes map[string]string `yaml:"rename_attributes"`
+}
+
+// PostgresConfig configures the asset store connection.
+type PostgresConfig struct {
+	DSN string `yaml:"dsn"`
+	// SlowQueryThreshold makes the store log any query taking longer
+	// than this at warn level. Zero disables slow-query logging.
+	SlowQueryThreshold Duration `yaml:"slow_query_threshold"`
+}
+
+// Neo4jConfig configures the graph store connection. An empty URI
+// disables the graph store.
+type Neo4jConfig struct {
+	URI      string `yaml:"uri"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// CustomRelationshipTypes extends the built-in edge-label allow-list
+	// with domain-specific types like "BACKS_UP". Labels must match
+	// [A-Z][A-Z0-9_]*.
+	CustomRelationshipTypes []string `yaml:"custom_relationship_types"`
+}
+
+// NATSConfig configures the change-event publisher. An empty URL
+// disables publishing.
+type NATSConfig struct {
+	URL     string `yaml:"url"`
+	Subject string `yaml:"subject"`
+	// ReconnectWait is the delay between reconnect attempts after a
+	// disconnect. Defaults to 2s.
+	ReconnectWait Duration `yaml:"reconnect_wait"`
+	// MaxReconnects caps reconnect attempts; -1 (the default) retries
+	// forever.
+	MaxReconnects int `yaml:"max_reconnects"`
+	// ReconnectBufSize is the size in bytes of the buffer holding
+	// published messages while disconnected. Defaults to 8 MiB.
+	ReconnectBufSize int `yaml:"reconnect_buf_size"`
+}
+
+// AuthConfig lists the API tokens and the scope each one grants.
+type AuthConfig struct {
+	Tokens []TokenConfig `yaml:"tokens"`
+}
+
+// TokenConfig is a single static API token. Scope is one of "read",
+// "write" or "admin"; each scope implies the ones before it.
+type TokenConfig struct {
+	Token string `yaml:"token"`
+	Scope string `yaml:"scope"`
+}
+
+// CollectorConfig configures one collector instance. Settings is
+// collector-specific and interpreted by the named collector type.
+type CollectorConfig struct {
+	Name     string            `yaml:"name"`
+	Type     string            `yaml:"type"`
+	Interval Duration          `yaml:"interval"`
+	Enabled  bool              `yaml:"enabled"`
+	Settings map[string]string `yaml:"settings"`
+	// StaleAfter and RemoveAfter drive the post-run lifecycle sweep:
+	// an asset this collector has not reported for StaleAfter intervals
+	// is marked stale, and after RemoveAfter intervals removed. Zero
+	// disables the respective transition.
+	StaleAfter  int `yaml:"stale_after"`
+	RemoveAfter int `yaml:"remove_after"`
+}
+
+// NotifyConfig configures outbound notifications for change events.
+type NotifyConfig struct {
+	Webhooks []WebhookConfig `yaml:"webhooks"`
+	// CollectorFailureThreshold emits a "collector.failing" alert when
+	// a collector has failed this many runs in a row. Defaults to 3;
+	// -1 disables the alert.
+	CollectorFailureThreshold int `yaml:"collector_failure_threshold"`
+}
+
+// WebhookConfig routes matching change events to an HTTP endpoint. An
+// empty Actions list matches every action.
+type WebhookConfig struct {
+	URL     string   `yaml:"url"`
+	Actions []string `yaml:"actions"`
+	// Team restricts the webhook to events about assets assigned to
+	// this team, so each team's channel only sees its own fleet. Empty
+	// matches every asset.
+	Team string `yaml:"team"`
+}
+
+// ExportConfig configures pushing reconciled assets out to external
+// systems of record.
+type ExportConfig struct {
+	ServiceNow ServiceNowExportConfig `yaml:"servicenow"`
+}
+
+// ServiceNowExportConfig configures the ServiceNow CMDB exporter. An
+// empty URL disables it.
+type ServiceNowExportConfig struct {
+	URL      string `yaml:"url"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// ClassMap maps asset types to the CI class they are exported as,
+	// e.g. host: cmdb_ci_server. Asset types missing from the map are
+	// not exported.
+	ClassMap map[string]string `yaml:"class_map"`
+	// SkipSources lists collector instance names whose assets are never
+	// exported — typically the servicenow collector reading the same
+	// instance, which would otherwise round-trip its own records.
+	SkipSources []string `yaml:"skip_sources"`
+}
+
+// Load reads and validates the configuration file at path.
+func Load(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	cfg := &Config{}
+	if err := yaml.Unmarshal(raw, cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if cfg.Listen == "" {
+		cfg.Listen = ":8080"
+	}
+	if cfg.Postgres.DSN == "" {
+		return nil, fmt.Errorf("postgres.dsn is required")
+	}
+	if cfg.NATS.URL != "" {
+		if cfg.NATS.Subject == "" {
+			cfg.NATS.Subject = "arch.events"
+		}
+		if cfg.NATS.ReconnectWait == 0 {
+			cfg.NATS.ReconnectWait = Duration(2 * time.Second)
+		}
+		if cfg.NATS.MaxReconnects == 0 {
+			cfg.NATS.MaxReconnects = -1
+		}
+		if cfg.NATS.ReconnectBufSize == 0 {
+			cfg.NATS.ReconnectBufSize = 8 << 20
+		}
+	}
+	if cfg.Reconcile.BatchSize <= 0 {
+		cfg.Reconcile.BatchSize = 500
+	}
+	if cfg.Reconcile.Workers <= 0 {
+		cfg.Reconcile.Workers = 4
+	}
+	if cfg.Reconcile.MaxDropPercent <= 0 || cfg.Reconcile.MaxDropPercent > 100 {
+		cfg.Reconcile.MaxDropPercent = 50
+	}
+	if cfg.Reconcile.MatchThreshold < 0 || cfg.Reconcile.MatchThreshold > 1 {
+		return nil, fmt.Errorf("reconcile.match_threshold: %v is not between 0 and 1", cfg.Reconcile.MatchThreshold)
+	}
+	if cfg.Reconcile.MatchThreshold == 0 {
+		cfg.Reconcile.MatchThreshold = 0.7
+	}
+	for field, order := range cfg.Reconcile.Authority {
+		if len(order) == 0 {
+			return nil, fmt.Errorf("reconcile.authority.%s: source list is empty", field)
+		}
+	}
+	for _, spec := range cfg.Reconcile.Normalize.DropIPs {
+		if spec == "loopback" || spec == "link-local" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(spec); err != nil {
+			return nil, fmt.Errorf("reconcile.normalize.drop_ips: %q is neither a class nor a CIDR", spec)
+		}
+	}
+	if cfg.Notify.CollectorFailureThreshold == 0 {
+		cfg.Notify.CollectorFailureThreshold = 3
+	}
+	switch cfg.Reconcile.NameCollisionPolicy {
+	case "", "none":
+		cfg.Reconcile.NameCollisionPolicy = "none"
+	case "append-source", "append-ip":
+	default:
+		return nil, fmt.Errorf("reconcile.name_collision_policy: unknown policy %q", cfg.Reconcile.NameCollisionPolicy)
+	}
+	if cfg.Enrich.ReverseDNS.Enabled {
+		if cfg.Enrich.ReverseDNS.Concurrency <= 0 {
+			cfg.Enrich.ReverseDNS.Concurrency = 8
+		}
+		if cfg.Enrich.ReverseDNS.Timeout == 0 {
+			cfg.Enrich.ReverseDNS.Timeout = Duration(2 * time.Second)
+		}
+		if cfg.Enrich.ReverseDNS.CacheTTL == 0 {
+			cfg.Enrich.ReverseDNS.CacheTTL = Duration(time.Hour)
+		}
+	}
+	for i := range cfg.Collectors {
+		c := &cfg.Collectors[i]
+		if c.Name == "" {
+			return nil, fmt.Errorf("collectors[%d]: name is required", i)
+		}
+		if c.Type == "" {
+			c.Type = c.Name
+		}
+		if c.Interval == 0 {
+			c.Interval = Duration(30 * time.Minute)
+		}
+		if c.StaleAfter < 0 || c.RemoveAfter < 0 {
+			return nil, fmt.Errorf("collectors[%d]: stale_after and remove_after must not be negative", i)
+		}
+		if c.StaleAfter > 0 && c.RemoveAfter > 0 && c.RemoveAfter <= c.StaleAfter {
+			return nil, fmt.Errorf("collectors[%d]: remove_after (%d) must exceed stale_after (%d)", i, c.RemoveAfter, c.StaleAfter)
+		}
+	}
+	return cfg, nil
+}
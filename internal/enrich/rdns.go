@@ -0,0 +1,111 @@
+// Package enrich implements optional asset enrichment steps run by the
+// reconciler. Enrichers follow the repo's nil-receiver convention: a nil
+// pointer is a valid no-op, so callers never need to branch on whether
+// enrichment is configured.
+package enrich
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+// ReverseDNS fills in the FQDN of assets that arrive with only IP
+// addresses by resolving PTR records. Lookups are bounded in
+// concurrency and results — including misses — are cached so repeated
+// runs don't hammer the resolver.
+type ReverseDNS struct {
+	resolver *net.Resolver
+	timeout  time.Duration
+	ttl      time.Duration
+	sem      chan struct{}
+	log      *slog.Logger
+
+	mu    sync.Mutex
+	cache map[string]rdnsEntry
+}
+
+// rdnsEntry caches one lookup result; an empty fqdn is a cached miss.
+type rdnsEntry struct {
+	fqdn    string
+	expires time.Time
+}
+
+// NewReverseDNS builds the enricher, or returns nil when disabled.
+func NewReverseDNS(cfg config.ReverseDNSConfig, log *slog.Logger) *ReverseDNS {
+	if !cfg.Enabled {
+		return nil
+	}
+	resolver := net.DefaultResolver
+	if cfg.Resolver != "" {
+		server := cfg.Resolver
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+	return &ReverseDNS{
+		resolver: resolver,
+		timeout:  cfg.Timeout.Std(),
+		ttl:      cfg.CacheTTL.Std(),
+		sem:      make(chan struct{}, cfg.Concurrency),
+		log:      log,
+		cache:    make(map[string]rdnsEntry),
+	}
+}
+
+// Enrich sets a.FQDN from the first IP whose PTR record resolves. Assets
+// that already have an FQDN, or no IPs, are left alone. A nil receiver
+// is a no-op.
+func (r *ReverseDNS) Enrich(ctx context.Context, a *model.Asset) {
+	if r == nil || a.FQDN != "" || len(a.IPs) == 0 {
+		return
+	}
+	for _, ip := range a.IPs {
+		if fqdn := r.lookup(ctx, ip); fqdn != "" {
+			a.FQDN = fqdn
+			return
+		}
+	}
+}
+
+func (r *ReverseDNS) lookup(ctx context.Context, ip string) string {
+	now := time.Now()
+	r.mu.Lock()
+	if e, ok := r.cache[ip]; ok && now.Before(e.expires) {
+		r.mu.Unlock()
+		return e.fqdn
+	}
+	r.mu.Unlock()
+
+	select {
+	case r.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ""
+	}
+	defer func() { <-r.sem }()
+
+	lctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	names, err := r.resolver.LookupAddr(lctx, ip)
+	fqdn := ""
+	if err == nil && len(names) > 0 {
+		fqdn = strings.TrimSuffix(names[0], ".")
+	} else if err != nil {
+		r.log.Debug("enrich: reverse dns lookup failed", "ip", ip, "error", err)
+	}
+
+	r.mu.Lock()
+	r.cache[ip] = rdnsEntry{fqdn: fqdn, expires: now.Add(r.ttl)}
+	r.mu.Unlock()
+	return fqdn
+}
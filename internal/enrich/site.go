@@ -0,0 +1,60 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+)
+
+// SiteMapper derives an asset's site from its IP addresses using
+// configured CIDR-to-site rules, so location doesn't need per-asset
+// manual entry. Rules are evaluated in configuration order; the first
+// rule matching any of the asset's IPs wins.
+type SiteMapper struct {
+	rules []siteRule
+}
+
+type siteRule struct {
+	network *net.IPNet
+	site    string
+}
+
+// NewSiteMapper parses the configured rules, or returns nil when none
+// are configured.
+func NewSiteMapper(rules []config.SiteRuleConfig) (*SiteMapper, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	m := &SiteMapper{rules: make([]siteRule, 0, len(rules))}
+	for i, r := range rules {
+		if r.Site == "" {
+			return nil, fmt.Errorf("enrich.sites[%d]: site is required", i)
+		}
+		_, network, err := net.ParseCIDR(r.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("enrich.sites[%d]: invalid cidr %q: %w", i, r.CIDR, err)
+		}
+		m.rules = append(m.rules, siteRule{network: network, site: r.Site})
+	}
+	return m, nil
+}
+
+// Enrich sets a.Site from the first matching rule. Assets whose source
+// already reported a site are left alone. A nil receiver is a no-op.
+func (m *SiteMapper) Enrich(ctx context.Context, a *model.Asset) {
+	if m == nil || a.Site != "" {
+		return
+	}
+	for _, rule := range m.rules {
+		for _, raw := range a.IPs {
+			ip := net.ParseIP(raw)
+			if ip != nil && rule.network.Contains(ip) {
+				a.Site = rule.site
+				return
+			}
+		}
+	}
+}
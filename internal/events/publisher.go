@@ -0,0 +1,165 @@
+// Package events delivers change events to NATS. Events are written to
+// the change_events table first (by the reconciler or the API) and a
+// background flusher publishes any unpublished rows, so the table acts
+// as an outbox: a NATS outage delays delivery but never loses events.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// Publisher flushes unpublished change events to a NATS subject. A nil
+// *Publisher is a valid no-op for deployments without NATS.
+type Publisher struct {
+	conn    *nats.Conn
+	subject string
+	st      store.Store
+	log     *slog.Logger
+	wake    chan struct{}
+}
+
+// New connects to NATS with reconnect handling configured from cfg.
+// Transient disconnects are bridged by the client's reconnect buffer;
+// anything that outlives it is re-delivered from the outbox. The
+// returned publisher does nothing until Run is started.
+func New(cfg config.NATSConfig, st store.Store, log *slog.Logger) (*Publisher, error) {
+	p := &Publisher{subject: cfg.Subject, st: st, log: log, wake: make(chan struct{}, 1)}
+	conn, err := nats.Connect(cfg.URL,
+		nats.Name("archd"),
+		nats.ReconnectWait(cfg.ReconnectWait.Std()),
+		nats.MaxReconnects(cfg.MaxReconnects),
+		nats.ReconnectBufSize(cfg.ReconnectBufSize),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			log.Warn("events: nats disconnected", "error", err)
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			log.Info("events: nats reconnected", "url", nc.ConnectedUrl())
+			// Flush anything that accumulated while disconnected.
+			p.Notify()
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			log.Warn("events: nats connection closed")
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.conn = conn
+	return p, nil
+}
+
+// Status describes the publisher's connection for the status endpoint.
+type Status struct {
+	Connected bool   `json:"connected"`
+	State     string `json:"state"`
+	Pending   int    `json:"pending_bytes"`
+}
+
+// ConnStatus reports the current NATS connection state. Safe on a nil
+// publisher.
+func (p *Publisher) ConnStatus() *Status {
+	if p == nil {
+		return nil
+	}
+	pending, _ := p.conn.Buffered()
+	return &Status{
+		Connected: p.conn.IsConnected(),
+		State:     p.conn.Status().String(),
+		Pending:   pending,
+	}
+}
+
+// Subscribe delivers every event published on the shared subject —
+// including those flushed by other replicas — to handler. API replicas
+// use this to feed their SSE hubs, so a change reconciled anywhere
+// reaches clients connected to any replica. Safe on a nil publisher.
+func (p *Publisher) Subscribe(handler func(e *model.ChangeEvent)) error {
+	if p == nil {
+		return nil
+	}
+	_, err := p.conn.Subscribe(p.subject, func(msg *nats.Msg) {
+		e := &model.ChangeEvent{}
+		if err := json.Unmarshal(msg.Data, e); err != nil {
+			p.log.Error("events: decode subscribed event", "error", err)
+			return
+		}
+		handler(e)
+	})
+	return err
+}
+
+// Close drains the NATS connection.
+func (p *Publisher) Close() {
+	if p == nil {
+		return
+	}
+	p.conn.Drain()
+}
+
+// Notify wakes the flusher promptly; callers invoke it after writing
+// new change events. It never blocks.
+func (p *Publisher) Notify() {
+	if p == nil {
+		return
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run flushes the outbox until ctx is cancelled, waking on Notify or on
+// a timer so events are delivered even if a notification was missed.
+func (p *Publisher) Run(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-p.wake:
+		}
+		p.flush(ctx)
+	}
+}
+
+func (p *Publisher) flush(ctx context.Context) {
+	for {
+		evs, err := p.st.ListUnpublishedEvents(ctx, 100)
+		if err != nil {
+			p.log.Error("events: list unpublished", "error", err)
+			return
+		}
+		if len(evs) == 0 {
+			return
+		}
+		for _, e := range evs {
+			payload, err := json.Marshal(e)
+			if err != nil {
+				p.log.Error("events: marshal", "event_id", e.ID, "error", err)
+				continue
+			}
+			if err := p.conn.Publish(p.subject, payload); err != nil {
+				p.log.Warn("events: publish failed, will retry", "event_id", e.ID, "error", err)
+				return
+			}
+			if err := p.st.MarkEventPublished(ctx, e.ID); err != nil {
+				p.log.Error("events: mark published", "event_id", e.ID, "error", err)
+				return
+			}
+		}
+	}
+}
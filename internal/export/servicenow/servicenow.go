@@ -0,0 +1,173 @@
+// Package servicenow pushes reconciled assets back to a ServiceNow
+// CMDB as configuration items, making arch the feed for CI classes the
+// CMDB would otherwise track by hand. It is the outbound half of the
+// servicenow collector: an asset type maps to a CI class through the
+// configured class map, and arch marks the CIs it manages via
+// correlation_id so re-exports update in place instead of duplicating.
+package servicenow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// Exporter mirrors asset changes into a ServiceNow CMDB. It implements
+// reconcile.EventSink. A nil *Exporter is a valid no-op.
+type Exporter struct {
+	cfg    config.ServiceNowExportConfig
+	st     store.Store
+	client *http.Client
+	log    *slog.Logger
+	skip   map[string]bool
+}
+
+// New builds an exporter from config. Returns nil when no instance URL
+// is configured.
+func New(cfg config.ServiceNowExportConfig, st store.Store, log *slog.Logger) *Exporter {
+	if cfg.URL == "" {
+		return nil
+	}
+	skip := make(map[string]bool, len(cfg.SkipSources))
+	for _, s := range cfg.SkipSources {
+		skip[s] = true
+	}
+	return &Exporter{
+		cfg:    cfg,
+		st:     st,
+		client: &http.Client{Timeout: 30 * time.Second},
+		log:    log,
+		skip:   skip,
+	}
+}
+
+// Publish exports the asset behind a created or updated event. Export
+// is best-effort and asynchronous, like webhook delivery: a CMDB
+// outage must not stall reconciliation, and the next change to the
+// asset retries naturally.
+func (e *Exporter) Publish(ev *model.ChangeEvent) {
+	if e == nil {
+		return
+	}
+	if ev.Action != "asset.created" && ev.Action != "asset.updated" {
+		return
+	}
+	// Assets imported from ServiceNow itself would round-trip forever;
+	// skip_sources lists those collector instances.
+	if e.skip[ev.Source] {
+		return
+	}
+	go e.export(ev.AssetID)
+}
+
+func (e *Exporter) export(assetID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	a, err := e.st.GetAssetByID(ctx, assetID)
+	if err != nil {
+		e.log.Warn("servicenow export: load asset", "asset_id", assetID, "error", err)
+		return
+	}
+	class := e.cfg.ClassMap[a.AssetType]
+	if class == "" {
+		return
+	}
+	if err := e.upsert(ctx, class, a); err != nil {
+		e.log.Warn("servicenow export failed", "asset_id", assetID, "ci_class", class, "error", err)
+	}
+}
+
+// correlationID is the stable handle arch stamps on the CIs it
+// manages.
+func correlationID(a *model.Asset) string {
+	return fmt.Sprintf("arch-%d", a.ID)
+}
+
+// upsert finds the CI by correlation_id and patches it, or inserts a
+// new one.
+func (e *Exporter) upsert(ctx context.Context, class string, a *model.Asset) error {
+	sysID, err := e.find(ctx, class, correlationID(a))
+	if err != nil {
+		return err
+	}
+	body := map[string]string{
+		"name":           a.Name,
+		"correlation_id": correlationID(a),
+	}
+	if a.FQDN != "" {
+		body["fqdn"] = a.FQDN
+	}
+	if len(a.IPs) > 0 {
+		body["ip_address"] = a.IPs[0]
+	}
+	method, path := http.MethodPost, "/api/now/table/"+url.PathEscape(class)
+	if sysID != "" {
+		method, path = http.MethodPatch, path+"/"+url.PathEscape(sysID)
+	}
+	return e.do(ctx, method, path, body)
+}
+
+// find returns the sys_id of the CI carrying the correlation id, or ""
+// when arch has not exported this asset before.
+func (e *Exporter) find(ctx context.Context, class, corrID string) (string, error) {
+	path := fmt.Sprintf("/api/now/table/%s?sysparm_query=correlation_id=%s&sysparm_fields=sys_id&sysparm_limit=1",
+		url.PathEscape(class), url.QueryEscape(corrID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.cfg.URL+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(e.cfg.Username, e.cfg.Password)
+	req.Header.Set("Accept", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("lookup returned status %d", resp.StatusCode)
+	}
+	var page struct {
+		Result []struct {
+			SysID string `json:"sys_id"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return "", err
+	}
+	if len(page.Result) == 0 {
+		return "", nil
+	}
+	return page.Result[0].SysID, nil
+}
+
+func (e *Exporter) do(ctx context.Context, method, path string, body map[string]string) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.cfg.URL+path, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(e.cfg.Username, e.cfg.Password)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned status %d", method, path, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,392 @@
+// Package graph maintains the asset topology in Neo4j. Every asset is
+// mirrored as an (:Asset) node keyed by its Postgres ID; relationships
+// between assets are typed edges.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// Store is the Neo4j-backed graph store. A nil *Store is a valid no-op,
+// so callers don't have to guard every call when the graph is disabled.
+type Store struct {
+	driver neo4j.DriverWithContext
+}
+
+// New connects to Neo4j and ensures the Asset id constraint exists.
+func New(ctx context.Context, uri, username, password string) (*Store, error) {
+	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(username, password, ""))
+	if err != nil {
+		return nil, fmt.Errorf("connect neo4j: %w", err)
+	}
+	if err := driver.VerifyConnectivity(ctx); err != nil {
+		driver.Close(ctx)
+		return nil, fmt.Errorf("verify neo4j: %w", err)
+	}
+	s := &Store{driver: driver}
+	for _, constraint := range []string{
+		`CREATE CONSTRAINT asset_id IF NOT EXISTS FOR (a:Asset) REQUIRE a.id IS UNIQUE`,
+		`CREATE CONSTRAINT service_id IF NOT EXISTS FOR (b:Service) REQUIRE b.id IS UNIQUE`,
+	} {
+		if _, err := s.run(ctx, constraint, nil); err != nil {
+			driver.Close(ctx)
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Close shuts down the underlying driver.
+func (s *Store) Close(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	s.driver.Close(ctx)
+}
+
+func (s *Store) run(ctx context.Context, cypher string, params map[string]any) ([]*neo4j.Record, error) {
+	session := s.driver.NewSession(ctx, neo4j.SessionConfig{})
+	defer session.Close(ctx)
+	result, err := session.Run(ctx, cypher, params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Collect(ctx)
+}
+
+// UpsertAsset mirrors an asset into the graph. Tags ride along as a
+// sorted list of "key=value" strings — node properties can't hold maps
+// — so Cypher filters stay simple: WHERE 'env=prod' IN n.tags.
+func (s *Store) UpsertAsset(ctx context.Context, a *model.Asset) error {
+	if s == nil {
+		return nil
+	}
+	tags := make([]string, 0, len(a.Tags))
+	for k, v := range a.Tags {
+		tags = append(tags, k+"="+v)
+	}
+	sort.Strings(tags)
+	_, err := s.run(ctx,
+		`MERGE (n:Asset {id: $id})
+		 SET n.name = $name, n.fqdn = $fqdn, n.asset_type = $asset_type,
+		     n.status = $status, n.source = $source, n.tags = $tags, n.updated_at = datetime()`,
+		map[string]any{
+			"id": a.ID, "name": a.Name, "fqdn": a.FQDN,
+			"asset_type": a.AssetType, "status": string(a.Status), "source": a.Source,
+			"tags": tags,
+		})
+	return err
+}
+
+// SetAssetStatus updates just the status property of an asset node,
+// for lifecycle transitions that don't carry the full asset around.
+func (s *Store) SetAssetStatus(ctx context.Context, id int64, status model.AssetStatus) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.run(ctx,
+		`MATCH (n:Asset {id: $id}) SET n.status = $status, n.updated_at = datetime()`,
+		map[string]any{"id": id, "status": string(status)})
+	return err
+}
+
+// DeleteAsset removes an asset node and all of its edges.
+func (s *Store) DeleteAsset(ctx context.Context, id int64) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.run(ctx, `MATCH (n:Asset {id: $id}) DETACH DELETE n`, map[string]any{"id": id})
+	return err
+}
+
+// UpsertService mirrors a business service into the graph as a
+// (:Service) node with a CONTAINS edge to each resolved member, so
+// traversals can walk from a failing host up to the services it backs.
+// The edge set is rewritten wholesale; membership is computed in
+// Postgres, not here.
+func (s *Store) UpsertService(ctx context.Context, svc *model.BusinessService, memberIDs []int64) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.run(ctx,
+		`MERGE (b:Service {id: $id})
+		 SET b.name = $name, b.owner = $owner, b.criticality = $criticality, b.updated_at = datetime()
+		 WITH b
+		 OPTIONAL MATCH (b)-[r:CONTAINS]->(:Asset)
+		 DELETE r`,
+		map[string]any{
+			"id": svc.ID, "name": svc.Name, "owner": svc.Owner,
+			"criticality": string(svc.Criticality),
+		})
+	if err != nil {
+		return err
+	}
+	if len(memberIDs) == 0 {
+		return nil
+	}
+	_, err = s.run(ctx,
+		`MATCH (b:Service {id: $id})
+		 UNWIND $members AS aid
+		 MATCH (a:Asset {id: aid})
+		 MERGE (b)-[:CONTAINS]->(a)`,
+		map[string]any{"id": svc.ID, "members": memberIDs})
+	return err
+}
+
+// DeleteService removes a service node and its edges.
+func (s *Store) DeleteService(ctx context.Context, id int64) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.run(ctx, `MATCH (b:Service {id: $id}) DETACH DELETE b`, map[string]any{"id": id})
+	return err
+}
+
+// MergeAssets moves every edge of the asset srcID onto dstID and
+// removes the src node. Relationship types cannot be parameterized in
+// Cypher, so the edges are read out and re-created one at a time;
+// edges between the two nodes themselves are dropped.
+func (s *Store) MergeAssets(ctx context.Context, srcID, dstID int64) error {
+	if s == nil {
+		return nil
+	}
+	records, err := s.run(ctx,
+		`MATCH (a:Asset {id: $id})-[r]-(b:Asset)
+		 RETURN type(r) AS type, properties(r) AS props, b.id AS other, startNode(r) = a AS outgoing`,
+		map[string]any{"id": srcID})
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		var (
+			relType  string
+			props    map[string]any
+			otherID  int64
+			outgoing bool
+		)
+		if v, ok := rec.Get("type"); ok {
+			relType, _ = v.(string)
+		}
+		if v, ok := rec.Get("props"); ok {
+			props, _ = v.(map[string]any)
+		}
+		if v, ok := rec.Get("other"); ok {
+			otherID, _ = v.(int64)
+		}
+		if v, ok := rec.Get("outgoing"); ok {
+			outgoing, _ = v.(bool)
+		}
+		if otherID == dstID {
+			continue
+		}
+		source, _ := props["source"].(string)
+		delete(props, "source")
+		delete(props, "updated_at")
+		fromID, toID := dstID, otherID
+		if !outgoing {
+			fromID, toID = otherID, dstID
+		}
+		if err := s.UpsertRelationship(ctx, fromID, toID, model.RelationshipType(relType), source, props); err != nil {
+			return err
+		}
+	}
+	return s.DeleteAsset(ctx, srcID)
+}
+
+// UpsertRelationship writes one typed edge between two assets. The type
+// must already have been validated: it is interpolated into the Cypher
+// text because labels cannot be parameterized. An edge curated by hand
+// (source "manual") is never overwritten by a collector — only another
+// manual write can touch it.
+func (s *Store) UpsertRelationship(ctx context.Context, fromID, toID int64, relType model.RelationshipType, source string, props map[string]any) error {
+	if s == nil {
+		return nil
+	}
+	if !model.ValidRelationshipType(relType) {
+		return fmt.Errorf("invalid relationship type %q", relType)
+	}
+	if props == nil {
+		props = map[string]any{}
+	}
+	_, err := s.run(ctx,
+		`MATCH (a:Asset {id: $from}), (b:Asset {id: $to})
+		 MERGE (a)-[r:`+string(relType)+`]->(b)
+		 WITH r WHERE $source = 'manual' OR r.source IS NULL OR r.source <> 'manual'
+		 SET r += $props, r.source = $source, r.updated_at = datetime()`,
+		map[string]any{"from": fromID, "to": toID, "props": props, "source": source})
+	return err
+}
+
+// RelationshipRef identifies one edge without its properties, for
+// set-comparison against a collector run's reported relationships.
+type RelationshipRef struct {
+	FromID int64
+	ToID   int64
+	Type   string
+}
+
+// ListRelationshipsBySource returns every edge the given source owns.
+func (s *Store) ListRelationshipsBySource(ctx context.Context, source string) ([]RelationshipRef, error) {
+	if s == nil {
+		return nil, nil
+	}
+	records, err := s.run(ctx,
+		`MATCH (a:Asset)-[r]->(b:Asset) WHERE r.source = $source
+		 RETURN a.id AS from, b.id AS to, type(r) AS type`,
+		map[string]any{"source": source})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RelationshipRef, 0, len(records))
+	for _, rec := range records {
+		ref := RelationshipRef{}
+		if v, ok := rec.Get("from"); ok {
+			ref.FromID, _ = v.(int64)
+		}
+		if v, ok := rec.Get("to"); ok {
+			ref.ToID, _ = v.(int64)
+		}
+		if v, ok := rec.Get("type"); ok {
+			ref.Type, _ = v.(string)
+		}
+		out = append(out, ref)
+	}
+	return out, nil
+}
+
+// DeleteRelationship removes one typed edge between two assets if the
+// given source owns it. Like UpsertRelationship the type is validated
+// and then interpolated, since labels cannot be parameterized.
+func (s *Store) DeleteRelationship(ctx context.Context, fromID, toID int64, relType model.RelationshipType, source string) error {
+	if s == nil {
+		return nil
+	}
+	if !model.ValidRelationshipType(relType) {
+		return fmt.Errorf("invalid relationship type %q", relType)
+	}
+	_, err := s.run(ctx,
+		`MATCH (a:Asset {id: $from})-[r:`+string(relType)+`]->(b:Asset {id: $to})
+		 WHERE r.source = $source DELETE r`,
+		map[string]any{"from": fromID, "to": toID, "source": source})
+	return err
+}
+
+// ListRelationshipTypes returns the relationship types that actually
+// occur in the graph, so UIs can offer real filter options instead of
+// hardcoding the model constants.
+func (s *Store) ListRelationshipTypes(ctx context.Context) ([]string, error) {
+	if s == nil {
+		return nil, nil
+	}
+	records, err := s.run(ctx, `CALL db.relationshipTypes() YIELD relationshipType RETURN relationshipType ORDER BY relationshipType`, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(records))
+	for _, rec := range records {
+		if v, ok := rec.Get("relationshipType"); ok {
+			if t, ok := v.(string); ok {
+				out = append(out, t)
+			}
+		}
+	}
+	return out, nil
+}
+
+// IsolatedAssetIDs returns the IDs of assets that have no edges at all,
+// i.e. nodes disconnected from the topology. limit caps the result.
+func (s *Store) IsolatedAssetIDs(ctx context.Context, limit int) ([]int64, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	records, err := s.run(ctx,
+		`MATCH (a:Asset) WHERE NOT (a)--() RETURN a.id AS id ORDER BY id LIMIT $limit`,
+		map[string]any{"limit": limit})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int64, 0, len(records))
+	for _, rec := range records {
+		if v, ok := rec.Get("id"); ok {
+			if id, ok := v.(int64); ok {
+				out = append(out, id)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Neighbor is one asset reachable from a root, with the edge that led
+// to it.
+type Neighbor struct {
+	AssetID   int64  `json:"asset_id"`
+	Name      string `json:"name"`
+	AssetType string `json:"asset_type"`
+	RelType   string `json:"rel_type"`
+	Depth     int    `json:"depth"`
+}
+
+// Dependencies returns what the given asset depends on (outgoing edges)
+// up to the given depth.
+func (s *Store) Dependencies(ctx context.Context, id int64, depth int) ([]Neighbor, error) {
+	return s.traverse(ctx, id, depth, true)
+}
+
+// Impact returns what depends on the given asset (incoming edges) up to
+// the given depth.
+func (s *Store) Impact(ctx context.Context, id int64, depth int) ([]Neighbor, error) {
+	return s.traverse(ctx, id, depth, false)
+}
+
+func (s *Store) traverse(ctx context.Context, id int64, depth int, outgoing bool) ([]Neighbor, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if depth <= 0 || depth > 10 {
+		depth = 3
+	}
+	pattern := fmt.Sprintf(`(root:Asset {id: $id})-[rels*1..%d]->(n:Asset)`, depth)
+	if !outgoing {
+		pattern = fmt.Sprintf(`(root:Asset {id: $id})<-[rels*1..%d]-(n:Asset)`, depth)
+	}
+	records, err := s.run(ctx,
+		`MATCH `+pattern+`
+		 RETURN DISTINCT n.id AS id, n.name AS name, n.asset_type AS asset_type,
+		        type(last(rels)) AS rel_type, size(rels) AS depth
+		 ORDER BY depth, name`,
+		map[string]any{"id": id})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Neighbor, 0, len(records))
+	for _, rec := range records {
+		n := Neighbor{}
+		if v, ok := rec.Get("id"); ok {
+			n.AssetID, _ = v.(int64)
+		}
+		if v, ok := rec.Get("name"); ok {
+			n.Name, _ = v.(string)
+		}
+		if v, ok := rec.Get("asset_type"); ok {
+			n.AssetType, _ = v.(string)
+		}
+		if v, ok := rec.Get("rel_type"); ok {
+			n.RelType, _ = v.(string)
+		}
+		if v, ok := rec.Get("depth"); ok {
+			d, _ := v.(int64)
+			n.Depth = int(d)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
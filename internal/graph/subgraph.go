@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// SubgraphNode is a Neo4j node with all of its properties verbatim.
+type SubgraphNode struct {
+	ID         int64          `json:"id"`
+	Labels     []string       `json:"labels"`
+	Properties map[string]any `json:"properties"`
+}
+
+// SubgraphEdge is a Neo4j relationship with all of its properties
+// verbatim. FromID/ToID reference the asset ids of the endpoints.
+type SubgraphEdge struct {
+	Type       string         `json:"type"`
+	FromID     int64          `json:"from_id"`
+	ToID       int64          `json:"to_id"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Subgraph is the untrimmed neighborhood of a root asset, as stored in
+// Neo4j. Unlike the model-typed dependency/impact views it preserves
+// every node and relationship property, which is what a graph editor
+// needs.
+type Subgraph struct {
+	Nodes []SubgraphNode `json:"nodes"`
+	Edges []SubgraphEdge `json:"edges"`
+}
+
+// Subgraph returns all nodes and edges within depth hops of the root
+// asset, in either direction.
+func (s *Store) Subgraph(ctx context.Context, rootID int64, depth int) (*Subgraph, error) {
+	if s == nil {
+		return nil, fmt.Errorf("graph store is not configured")
+	}
+	if depth <= 0 || depth > 10 {
+		depth = 2
+	}
+	out := &Subgraph{Nodes: []SubgraphNode{}, Edges: []SubgraphEdge{}}
+
+	nodeRecords, err := s.run(ctx,
+		fmt.Sprintf(`MATCH path = (root:Asset {id: $id})-[*0..%d]-(:Asset)
+		 UNWIND nodes(path) AS n
+		 RETURN DISTINCT n`, depth),
+		map[string]any{"id": rootID})
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range nodeRecords {
+		v, ok := rec.Get("n")
+		if !ok {
+			continue
+		}
+		node, ok := v.(dbtype.Node)
+		if !ok {
+			continue
+		}
+		sn := SubgraphNode{Labels: node.Labels, Properties: node.Props}
+		if id, ok := node.Props["id"].(int64); ok {
+			sn.ID = id
+		}
+		out.Nodes = append(out.Nodes, sn)
+	}
+
+	edgeRecords, err := s.run(ctx,
+		fmt.Sprintf(`MATCH path = (root:Asset {id: $id})-[*1..%d]-(:Asset)
+		 UNWIND relationships(path) AS r
+		 RETURN DISTINCT r, startNode(r).id AS from_id, endNode(r).id AS to_id`, depth),
+		map[string]any{"id": rootID})
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range edgeRecords {
+		v, ok := rec.Get("r")
+		if !ok {
+			continue
+		}
+		rel, ok := v.(dbtype.Relationship)
+		if !ok {
+			continue
+		}
+		edge := SubgraphEdge{Type: rel.Type, Properties: rel.Props}
+		if from, ok := rec.Get("from_id"); ok {
+			edge.FromID, _ = from.(int64)
+		}
+		if to, ok := rec.Get("to_id"); ok {
+			edge.ToID, _ = to.(int64)
+		}
+		out.Edges = append(out.Edges, edge)
+	}
+	return out, nil
+}
@@ -0,0 +1,163 @@
+// Package model defines the core domain types shared by the store, the
+// reconciler, the collectors and the HTTP API.
+package model
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+// AssetStatus describes the lifecycle state of an asset.
+type AssetStatus string
+
+const (
+	StatusDiscovered AssetStatus = "discovered"
+	StatusActive     AssetStatus = "active"
+	StatusStale      AssetStatus = "stale"
+	StatusRemoved    AssetStatus = "removed"
+)
+
+// Asset is a single inventory item: a host, VM, container, load balancer
+// or anything else a collector can discover. Attributes carries the
+// source-specific payload as free-form JSON; first-class fields are the
+// ones the reconciler and the API filter on.
+type Asset struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// DisplayName is a derived, disambiguated name set by the
+	// reconciler when several assets share a Name (per the configured
+	// collision policy). Empty when Name is unambiguous.
+	DisplayName string   `json:"display_name,omitempty"`
+	FQDN        string   `json:"fqdn,omitempty"`
+	IPs         []string `json:"ips,omitempty"`
+	// MACs are the asset's known MAC addresses in canonical
+	// colon-separated uppercase form (see NormalizeMAC). Hardware
+	// addresses survive reinstalls and DHCP churn, which makes them a
+	// stronger cross-source matching signal than IPs.
+	MACs []string `json:"macs,omitempty"`
+	// SerialNumber and HardwareUUID identify the machine itself: the
+	// chassis serial and the SMBIOS/BIOS UUID. An out-of-band BMC view
+	// and an in-OS agent view of the same host report the same values,
+	// so the reconciler matches on them ahead of network identity.
+	SerialNumber string         `json:"serial_number,omitempty"`
+	HardwareUUID string         `json:"hardware_uuid,omitempty"`
+	AssetType    string         `json:"asset_type"`
+	Status       AssetStatus    `json:"status"`
+	Source       string         `json:"source"`
+	ExternalID   string         `json:"external_id"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	// Site is the datacenter or location, either reported by the source
+	// or derived from the configured CIDR-to-site rules.
+	Site string `json:"site,omitempty"`
+	// Tags are operator-managed key/value labels; collectors never
+	// write them.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Notes is operator-entered free text. It is edited only through
+	// the notes endpoint; reconciliation never touches it.
+	Notes string `json:"notes,omitempty"`
+	// Custom holds values for the admin-defined custom fields, keyed by
+	// field name. Like Notes it is edited only through its own
+	// endpoint; reconciliation never touches it.
+	Custom map[string]any `json:"custom,omitempty"`
+	// Owner is the person and Team the group accountable for the
+	// asset. Assigned through the API, never by collectors; the
+	// notifier can route alerts by Team.
+	Owner string `json:"owner,omitempty"`
+	Team  string `json:"team,omitempty"`
+	// SeenCount is the number of reconcile runs that observed this
+	// asset; AbsentCount the number of runs of its source that did not.
+	// A large absent-to-seen ratio flags flapping or stale data.
+	SeenCount   int64     `json:"seen_count"`
+	AbsentCount int64     `json:"absent_count"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// DeletedAt marks a soft-deleted asset: the row and its history are
+	// kept, but default listings hide it until it is restored or a
+	// source reports it again.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Aliases lists the (source, external_id) pairs this asset is
+	// known by across sources. Populated on single-asset reads only.
+	Aliases []Alias `json:"aliases,omitempty"`
+	// Sources holds each source's own record of this asset — its
+	// external ID there and the raw attribute payload it last reported.
+	// Populated on single-asset reads only.
+	Sources []AssetSource `json:"sources,omitempty"`
+	// Software lists installed packages. On incoming assets it is what
+	// the collector reported this run (nil when the collector doesn't
+	// track software); on stored assets it is served through the
+	// software sub-resource, not populated on reads.
+	Software []SoftwarePackage `json:"software,omitempty"`
+	// Vulnerabilities lists scanner findings, with the same nil-means-
+	// not-reported convention as Software.
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// AssetSource is one source's view of a canonical asset. The merged
+// Asset.Attributes are recomputed from these payloads, so no source
+// can clobber another's data wholesale and the provenance of every
+// attribute stays reconstructable.
+type AssetSource struct {
+	AssetID    int64          `json:"asset_id"`
+	Source     string         `json:"source"`
+	ExternalID string         `json:"external_id"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	FirstSeen  time.Time      `json:"first_seen"`
+	LastSeen   time.Time      `json:"last_seen"`
+}
+
+// Alias maps one source's external ID to a canonical asset. Aliases are
+// recorded when the reconciler matches an incoming asset to an existing
+// one from another source, making that identity durable.
+type Alias struct {
+	Source     string    `json:"source"`
+	ExternalID string    `json:"external_id"`
+	AssetID    int64     `json:"asset_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NormalizeMAC canonicalizes a MAC address to colon-separated
+// uppercase ("AA:BB:CC:DD:EE:FF"), accepting the hyphenated and
+// Cisco dotted forms sources use. It returns "" for anything that is
+// not a valid hardware address.
+func NormalizeMAC(raw string) string {
+	hw, err := net.ParseMAC(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	return strings.ToUpper(hw.String())
+}
+
+// NormalizeMACs canonicalizes a list of MAC addresses, dropping
+// invalid entries and duplicates.
+func NormalizeMACs(raw []string) []string {
+	seen := make(map[string]bool, len(raw))
+	var out []string
+	for _, r := range raw {
+		mac := NormalizeMAC(r)
+		if mac == "" || seen[mac] {
+			continue
+		}
+		seen[mac] = true
+		out = append(out, mac)
+	}
+	return out
+}
+
+// NormalizeHardwareUUID canonicalizes a SMBIOS/BIOS UUID to trimmed
+// lowercase; sources disagree on case but never on the digits.
+func NormalizeHardwareUUID(raw string) string {
+	return strings.ToLower(strings.TrimSpace(raw))
+}
+
+// HasIP reports whether the asset lists the given IP address.
+func (a *Asset) HasIP(ip string) bool {
+	for _, have := range a.IPs {
+		if have == ip {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package model
+
+import "fmt"
+
+// AttributeType is the expected value type of one declared attribute.
+type AttributeType string
+
+const (
+	AttrString AttributeType = "string"
+	AttrInt    AttributeType = "int"
+	AttrFloat  AttributeType = "float"
+	AttrBool   AttributeType = "bool"
+	AttrList   AttributeType = "list"
+	AttrMap    AttributeType = "map"
+)
+
+// AttributeDef declares one well-known attribute of an asset type, so
+// consumers can rely on its shape instead of probing free-form JSON.
+type AttributeDef struct {
+	Name        string        `json:"name"`
+	Type        AttributeType `json:"type"`
+	Description string        `json:"description,omitempty"`
+}
+
+// assetTypeSchemas declares the well-known attributes per asset type.
+// The declarations are deliberately not exhaustive: sources attach
+// whatever extra attributes they have, and undeclared keys always
+// pass. Declared keys, however, must carry the declared type — that is
+// the contract queries and exports build on. Beyond the built-ins,
+// types can be registered at startup via RegisterAssetType.
+var assetTypeSchemas = map[string][]AttributeDef{
+	"host": {
+		{Name: "os", Type: AttrString, Description: "operating system label"},
+		{Name: "kernel", Type: AttrString, Description: "kernel name and release"},
+		{Name: "manufacturer", Type: AttrString, Description: "hardware vendor"},
+		{Name: "model", Type: AttrString, Description: "hardware model"},
+		{Name: "cpu_count", Type: AttrInt, Description: "physical or logical CPUs"},
+		{Name: "memory_gib", Type: AttrFloat, Description: "installed memory in GiB"},
+	},
+	"vm": {
+		{Name: "power_state", Type: AttrString, Description: "hypervisor power state"},
+		{Name: "guest_os", Type: AttrString, Description: "guest OS label"},
+		{Name: "cpu_count", Type: AttrInt, Description: "virtual CPUs"},
+		{Name: "memory_mb", Type: AttrInt, Description: "allocated memory in MB"},
+	},
+	"hypervisor": {
+		{Name: "power_state", Type: AttrString, Description: "host power state"},
+		{Name: "vendor", Type: AttrString, Description: "hardware vendor"},
+		{Name: "model", Type: AttrString, Description: "hardware model"},
+		{Name: "cpu_cores", Type: AttrInt, Description: "physical cores"},
+		{Name: "memory_bytes", Type: AttrInt, Description: "installed memory in bytes"},
+	},
+	"service": {
+		{Name: "tls_version", Type: AttrString, Description: "negotiated TLS version"},
+	},
+	"subnet": {
+		{Name: "cidr", Type: AttrString, Description: "network in CIDR notation"},
+	},
+}
+
+// RegisterAssetType declares (or extends) an asset type's attribute
+// schema. Like RegisterRelationshipTypes it must be called during
+// startup, before collectors run, so reads need no locking.
+func RegisterAssetType(assetType string, defs []AttributeDef) {
+	assetTypeSchemas[assetType] = append(assetTypeSchemas[assetType], defs...)
+}
+
+// AssetTypeSchema returns the declared attributes of an asset type;
+// nil for types with no declarations.
+func AssetTypeSchema(assetType string) []AttributeDef {
+	return assetTypeSchemas[assetType]
+}
+
+// ValidateAttributes checks an asset's attributes against its type's
+// declarations and returns one message per violation. Undeclared
+// attributes are fine; a declared attribute carrying the wrong type is
+// not.
+func ValidateAttributes(assetType string, attrs map[string]any) []string {
+	defs := assetTypeSchemas[assetType]
+	if len(defs) == 0 || len(attrs) == 0 {
+		return nil
+	}
+	var problems []string
+	for _, def := range defs {
+		v, ok := attrs[def.Name]
+		if !ok || v == nil {
+			continue
+		}
+		if !attrTypeOK(def.Type, v) {
+			problems = append(problems, fmt.Sprintf("attribute %q should be %s, got %T", def.Name, def.Type, v))
+		}
+	}
+	return problems
+}
+
+// attrTypeOK reports whether v is acceptable for the declared type.
+// Numbers are checked loosely: JSON decoding turns everything into
+// float64, so an int declaration accepts any integral number.
+func attrTypeOK(t AttributeType, v any) bool {
+	switch t {
+	case AttrString:
+		_, ok := v.(string)
+		return ok
+	case AttrBool:
+		_, ok := v.(bool)
+		return ok
+	case AttrInt:
+		switch n := v.(type) {
+		case int, int32, int64:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		}
+		return false
+	case AttrFloat:
+		switch v.(type) {
+		case int, int32, int64, float32, float64:
+			return true
+		}
+		return false
+	case AttrList:
+		switch v.(type) {
+		case []any, []string, []int, []map[string]any:
+			return true
+		}
+		return false
+	case AttrMap:
+		switch v.(type) {
+		case map[string]any, map[string]string:
+			return true
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package model
+
+// CollectResult is everything a single collector run produced. Assets
+// and relationships are handed to the reconciler as one unit so that
+// edges can be resolved against the assets of the same run.
+type CollectResult struct {
+	Assets        []*Asset       `json:"assets"`
+	Relationships []Relationship `json:"relationships,omitempty"`
+	Stats         CollectStats   `json:"stats"`
+	// Partial marks a result that covers only what changed since the
+	// source's last run, not its full inventory. The reconciler skips
+	// the snapshot guard and absence tracking for partial results —
+	// assets missing from them are simply unchanged.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// CollectStats counts what a collector saw versus what it emitted, so
+// "the scan found 200 hosts but only 40 assets appeared" is explainable
+// from the run record alone.
+type CollectStats struct {
+	// Fetched is the number of items seen at the source.
+	Fetched int `json:"fetched"`
+	// Filtered is the number of fetched items the collector dropped
+	// (down hosts, out-of-scope addresses, ...).
+	Filtered int `json:"filtered"`
+	// Emitted is the number of assets handed to the reconciler.
+	Emitted int `json:"emitted"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// CustomFieldType is the value type of an admin-defined custom field.
+type CustomFieldType string
+
+const (
+	CustomString CustomFieldType = "string"
+	CustomNumber CustomFieldType = "number"
+	CustomDate   CustomFieldType = "date"
+	CustomEnum   CustomFieldType = "enum"
+)
+
+// ValidCustomFieldType reports whether t is a defined field type.
+func ValidCustomFieldType(t CustomFieldType) bool {
+	switch t {
+	case CustomString, CustomNumber, CustomDate, CustomEnum:
+		return true
+	}
+	return false
+}
+
+// CustomField is an admin-defined field on assets — owner, cost
+// center, warranty expiry — for data no collector provides. Unlike the
+// per-type attribute schemas, which describe what sources report,
+// custom fields are defined at runtime and their values are entered
+// through the API, validated against the definition on every write.
+type CustomField struct {
+	ID   int64           `json:"id"`
+	Name string          `json:"name"`
+	Type CustomFieldType `json:"type"`
+	// EnumValues lists the allowed values of an enum field; empty for
+	// the other types.
+	EnumValues  []string  `json:"enum_values,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Validate checks one value against the field definition. Numbers
+// arrive as float64 from JSON; dates are ISO days (2006-01-02).
+func (f *CustomField) Validate(value any) error {
+	switch f.Type {
+	case CustomString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: want a string", f.Name)
+		}
+	case CustomNumber:
+		switch value.(type) {
+		case float64, int, int64:
+		default:
+			return fmt.Errorf("%s: want a number", f.Name)
+		}
+	case CustomDate:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: want a date string (2006-01-02)", f.Name)
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return fmt.Errorf("%s: %q is not a date (2006-01-02)", f.Name, s)
+		}
+	case CustomEnum:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: want one of %v", f.Name, f.EnumValues)
+		}
+		for _, v := range f.EnumValues {
+			if v == s {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %q is not one of %v", f.Name, s, f.EnumValues)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// Change event actions. Kept as plain strings so downstream consumers
+// (NATS subscribers, the notifier) can match on them without importing
+// this package.
+const (
+	ActionAssetCreated   = "asset.created"
+	ActionAssetUpdated   = "asset.updated"
+	ActionAssetRemoved   = "asset.removed"
+	ActionAssetMerged    = "asset.merged"
+	ActionAssetAnnotated = "asset.annotated"
+	// ActionRelationshipChanged records a topology change: currently a
+	// previously reported edge disappearing from its source's runs.
+	ActionRelationshipChanged = "relationship.changed"
+	// ActionCollectorFailing is a synthetic notification-only event
+	// emitted when a collector crosses its consecutive-failure
+	// threshold; it is never persisted to the change log.
+	ActionCollectorFailing = "collector.failing"
+)
+
+// Reasons recorded in the reconcile conflict log.
+const (
+	ConflictFuzzyMatch     = "fuzzy_match"     // matched cross-source by an inferred signal
+	ConflictAmbiguousMatch = "ambiguous_match" // several candidates; a new asset was created
+	ConflictMergeCandidate = "merge_candidate" // match below the confidence threshold; kept separate
+)
+
+// ReconcileConflict records a non-obvious reconciler decision for human
+// review: the incoming payload, the asset that was chosen (nil when a
+// new one was created) and why.
+type ReconcileConflict struct {
+	ID         int64     `json:"id"`
+	Source     string    `json:"source"`
+	ExternalID string    `json:"external_id"`
+	AssetID    *int64    `json:"asset_id,omitempty"`
+	Reason     string    `json:"reason"`
+	Incoming   *Asset    `json:"incoming"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ChangeEvent records one observed change to an asset. Before and After
+// hold the relevant field values (not necessarily the whole asset) so
+// the history endpoint can render a diff.
+type ChangeEvent struct {
+	ID        int64          `json:"id"`
+	AssetID   int64          `json:"asset_id"`
+	Action    string         `json:"action"`
+	Source    string         `json:"source"`
+	Actor     string         `json:"actor,omitempty"`
+	Before    map[string]any `json:"before,omitempty"`
+	After     map[string]any `json:"after,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// MaintenanceWindow is a planned time range during which lifecycle
+// sweeps and notifications leave the covered assets alone, so a patch
+// weekend doesn't mark half the fleet stale or page anyone about it.
+// Coverage is the union of the explicitly listed assets and, when
+// Selector is non-empty, every asset carrying all of its tags.
+type MaintenanceWindow struct {
+	ID       int64     `json:"id"`
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	// AssetIDs lists explicitly covered assets.
+	AssetIDs []int64 `json:"asset_ids,omitempty"`
+	// Selector is a tag selector like BusinessService.Selector: an
+	// empty selector covers nothing, not everything.
+	Selector  map[string]string `json:"selector,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Active reports whether the window covers the given instant.
+func (w *MaintenanceWindow) Active(at time.Time) bool {
+	return !at.Before(w.StartsAt) && at.Before(w.EndsAt)
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RelationshipType is the label of a graph edge between two assets. The
+// set is an allow-list so that types can be interpolated into Cypher
+// safely; everything else about an edge goes into Properties. Beyond
+// the built-in constants, domain-specific types can be registered from
+// config at startup via RegisterRelationshipTypes.
+type RelationshipType string
+
+const (
+	RelDependsOn    RelationshipType = "DEPENDS_ON"
+	RelRunsOn       RelationshipType = "RUNS_ON"
+	RelConnectsTo   RelationshipType = "CONNECTS_TO"
+	RelContains     RelationshipType = "CONTAINS"
+	RelMemberOf     RelationshipType = "MEMBER_OF"
+	RelLoadBalances RelationshipType = "LOAD_BALANCES"
+	RelMonitors     RelationshipType = "MONITORS"
+)
+
+// customRelTypes holds types registered at startup. It is written once
+// before any collector or API traffic runs, so reads need no locking.
+var customRelTypes = map[RelationshipType]bool{}
+
+// relTypePattern constrains registered labels to the shape of the
+// built-in constants, which is what keeps interpolating them into
+// Cypher safe.
+var relTypePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// RegisterRelationshipTypes adds domain-specific edge labels (e.g.
+// "BACKS_UP") to the allow-list. It must be called during startup,
+// before collectors run. Labels not matching [A-Z][A-Z0-9_]* are
+// rejected to preserve the Cypher-injection guarantee.
+func RegisterRelationshipTypes(types []string) error {
+	for _, raw := range types {
+		if !relTypePattern.MatchString(raw) {
+			return fmt.Errorf("invalid relationship type %q: must match %s", raw, relTypePattern)
+		}
+		customRelTypes[RelationshipType(raw)] = true
+	}
+	return nil
+}
+
+// ValidRelationshipType reports whether t is one of the allowed edge
+// labels. Callers must check this before handing a type to the graph
+// store, since labels cannot be parameterized in Cypher.
+func ValidRelationshipType(t RelationshipType) bool {
+	switch t {
+	case RelDependsOn, RelRunsOn, RelConnectsTo, RelContains, RelMemberOf, RelLoadBalances, RelMonitors:
+		return true
+	}
+	return customRelTypes[t]
+}
+
+// Relationship is an edge between two assets as reported by a collector.
+// Endpoints are addressed by external ID within the emitting source; the
+// reconciler resolves them to canonical asset IDs before the edge is
+// written to the graph.
+type Relationship struct {
+	FromExternalID string           `json:"from_external_id"`
+	ToExternalID   string           `json:"to_external_id"`
+	Type           RelationshipType `json:"type"`
+	Source         string           `json:"source"`
+	Properties     map[string]any   `json:"properties,omitempty"`
+}
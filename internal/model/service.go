@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// ServiceCriticality ranks how much a business service matters when
+// triaging impact.
+type ServiceCriticality string
+
+const (
+	CriticalityLow      ServiceCriticality = "low"
+	CriticalityMedium   ServiceCriticality = "medium"
+	CriticalityHigh     ServiceCriticality = "high"
+	CriticalityCritical ServiceCriticality = "critical"
+)
+
+// ValidServiceCriticality reports whether c is a defined criticality.
+func ValidServiceCriticality(c ServiceCriticality) bool {
+	switch c {
+	case CriticalityLow, CriticalityMedium, CriticalityHigh, CriticalityCritical:
+		return true
+	}
+	return false
+}
+
+// BusinessService groups assets into something the organization
+// recognizes — "payments", "internal wiki" — so impact analysis can
+// name the services behind a failing host instead of listing raw
+// assets. Membership is the union of explicitly attached assets and,
+// when Selector is non-empty, every non-deleted asset carrying all of
+// its tags.
+type BusinessService struct {
+	ID          int64              `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Owner       string             `json:"owner,omitempty"`
+	Criticality ServiceCriticality `json:"criticality"`
+	// Selector is a tag selector: an asset matches when its tags
+	// contain every pair. An empty selector selects nothing, not
+	// everything.
+	Selector  map[string]string `json:"selector,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	// Assets holds the resolved members. Populated on single-service
+	// reads only.
+	Assets []*Asset `json:"assets,omitempty"`
+}
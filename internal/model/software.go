@@ -0,0 +1,59 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SoftwarePackage is one installed package on an asset, as reported by
+// a collector that can see inside the host (ansible facts, osquery,
+// SCCM). Source is filled in by the store from the reporting run.
+type SoftwarePackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  string `json:"source,omitempty"`
+}
+
+// CompareVersions orders two version strings the way package versions
+// are read: split on dots and dashes, numeric segments compared as
+// numbers, everything else lexically, missing segments as zero. It
+// returns -1, 0 or 1. This is deliberately scheme-agnostic — good
+// enough for "openssl < 3.0.13" across packaging ecosystems, not a
+// full implementation of any one of them.
+func CompareVersions(a, b string) int {
+	as := splitVersion(a)
+	bs := splitVersion(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		av, bv := "0", "0"
+		if i < len(as) {
+			av = as[i]
+		}
+		if i < len(bs) {
+			bv = bs[i]
+		}
+		an, aerr := strconv.Atoi(av)
+		bn, berr := strconv.Atoi(bv)
+		if aerr == nil && berr == nil {
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if av != bv {
+			if av < bv {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func splitVersion(v string) []string {
+	return strings.FieldsFunc(strings.TrimSpace(v), func(r rune) bool {
+		return r == '.' || r == '-' || r == '_' || r == '+' || r == '~'
+	})
+}
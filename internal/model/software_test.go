@@ -0,0 +1,25 @@
+package model
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"3.0.12", "3.0.13", -1},
+		{"3.0.13", "3.0.12", 1},
+		{"3.0.9", "3.0.13", -1},
+		{"10.0", "9.9", 1},
+		{"1.0.0-rc1", "1.0.0", 1},
+		{"2.4.41", "2.4.41-1ubuntu1", -1},
+		{"1.1.1k", "1.1.1w", -1},
+	}
+	for _, c := range cases {
+		if got := CompareVersions(c.a, c.b); got != c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// VulnSeverity ranks a vulnerability finding.
+type VulnSeverity string
+
+const (
+	SeverityCritical VulnSeverity = "critical"
+	SeverityHigh     VulnSeverity = "high"
+	SeverityMedium   VulnSeverity = "medium"
+	SeverityLow      VulnSeverity = "low"
+	SeverityInfo     VulnSeverity = "info"
+)
+
+// ValidVulnSeverity reports whether sev is a defined severity.
+func ValidVulnSeverity(sev VulnSeverity) bool {
+	switch sev {
+	case SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo:
+		return true
+	}
+	return false
+}
+
+// VulnStatus is the lifecycle of a finding on one asset.
+const (
+	VulnOpen     = "open"
+	VulnResolved = "resolved"
+)
+
+// Vulnerability is one scanner finding on one asset, keyed by CVE
+// within the reporting source. Scanners report their current findings
+// each run; a finding that stops appearing is marked resolved rather
+// than deleted, so remediation progress stays visible.
+type Vulnerability struct {
+	CVE      string       `json:"cve"`
+	Severity VulnSeverity `json:"severity"`
+	Status   string       `json:"status,omitempty"`
+	Source   string       `json:"source,omitempty"`
+	// Title is the scanner's one-line summary of the finding.
+	Title      string     `json:"title,omitempty"`
+	DetectedAt time.Time  `json:"detected_at,omitempty"`
+	LastSeen   time.Time  `json:"last_seen,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
@@ -0,0 +1,137 @@
+// Package notify fans change events out to configured notification
+// channels (currently webhooks). It subscribes in-process: the
+// reconciler and API hand events to Publish after persisting them.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/qrunner/arch/internal/config"
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// Notifier delivers change events to the configured channels. A nil
+// *Notifier is a valid no-op.
+type Notifier struct {
+	webhooks []config.WebhookConfig
+	store    store.Store
+	client   *http.Client
+	log      *slog.Logger
+}
+
+// New builds a notifier from config. Returns nil when no channels are
+// configured.
+func New(cfg config.NotifyConfig, st store.Store, log *slog.Logger) *Notifier {
+	if len(cfg.Webhooks) == 0 {
+		return nil
+	}
+	return &Notifier{
+		webhooks: cfg.Webhooks,
+		store:    st,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		log:      log,
+	}
+}
+
+// Publish sends the event to every channel whose action filter matches.
+// Events about assets covered by an active maintenance window are
+// dropped — planned work is not worth paging about. Delivery is
+// best-effort and asynchronous; failures are logged.
+func (n *Notifier) Publish(e *model.ChangeEvent) {
+	if n == nil {
+		return
+	}
+	if e.AssetID != 0 && n.inMaintenance(e.AssetID) {
+		n.log.Debug("notify: suppressed by maintenance window", "asset_id", e.AssetID, "action", e.Action)
+		return
+	}
+	team, teamKnown := "", false
+	for _, wh := range n.webhooks {
+		if !matchesAction(wh.Actions, e.Action) {
+			continue
+		}
+		if wh.Team != "" {
+			if !teamKnown {
+				team = n.assetTeam(e.AssetID)
+				teamKnown = true
+			}
+			if team != wh.Team {
+				continue
+			}
+		}
+		go n.deliver(wh.URL, e)
+	}
+}
+
+// assetTeam resolves the owning team of the event's asset, looked up
+// at most once per event. Unknown assets have no team, so team-scoped
+// webhooks skip them.
+func (n *Notifier) assetTeam(assetID int64) string {
+	if assetID == 0 {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a, err := n.store.GetAssetByID(ctx, assetID)
+	if err != nil {
+		n.log.Warn("notify: owner lookup failed", "asset_id", assetID, "error", err)
+		return ""
+	}
+	return a.Team
+}
+
+// inMaintenance checks maintenance coverage for an asset, erring on
+// the side of delivery when the lookup fails.
+func (n *Notifier) inMaintenance(assetID int64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	covered, err := n.store.AssetInMaintenance(ctx, assetID)
+	if err != nil {
+		n.log.Warn("notify: maintenance lookup failed", "asset_id", assetID, "error", err)
+		return false
+	}
+	return covered
+}
+
+func matchesAction(actions []string, action string) bool {
+	if len(actions) == 0 {
+		return true
+	}
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) deliver(url string, e *model.ChangeEvent) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		n.log.Error("notify: marshal event", "event_id", e.ID, "error", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		n.log.Error("notify: build request", "url", url, "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		n.log.Warn("notify: webhook delivery failed", "url", url, "error", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		n.log.Warn("notify: webhook rejected event", "url", url, "status", resp.StatusCode)
+	}
+}
@@ -0,0 +1,448 @@
+// Package query implements the small expression language accepted by
+// the asset list endpoint, e.g.
+//
+//	asset_type = "vm" AND attributes.os ~ "Ubuntu" AND last_seen < now()-7d
+//
+// Parse builds an AST and Compile turns it into a parameterized SQL
+// condition. Field names come from a fixed allow-list and every value —
+// including JSONB keys — travels as a bind parameter, so no user input
+// is ever interpolated into SQL.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error reports why and where parsing a query failed. Pos is a byte
+// offset into the query string.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Msg, e.Pos)
+}
+
+func errf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Expr is a parsed query expression.
+type Expr interface {
+	isExpr()
+}
+
+type andExpr struct{ left, right Expr }
+type orExpr struct{ left, right Expr }
+type notExpr struct{ expr Expr }
+
+// cmpExpr is a single field comparison. col and key are taken from the
+// allow-list during parsing, never from raw input.
+type cmpExpr struct {
+	col  string
+	key  string // JSONB key for attributes./tags./custom. fields
+	kind fieldKind
+	op   string
+	val  value
+}
+
+func (andExpr) isExpr() {}
+func (orExpr) isExpr()  {}
+func (notExpr) isExpr() {}
+func (cmpExpr) isExpr() {}
+
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindNumber
+	kindTime
+	kindArray // text[] columns, matched element-wise
+	kindJSON  // JSONB keys, compared as text
+)
+
+// fields maps queryable field names to their kind. The names double as
+// column names, which is safe because lookups go through this map.
+var fields = map[string]fieldKind{
+	"name": kindString, "display_name": kindString, "fqdn": kindString,
+	"asset_type": kindString, "status": kindString, "source": kindString,
+	"external_id": kindString, "site": kindString, "owner": kindString,
+	"team": kindString, "notes": kindString, "serial_number": kindString,
+	"hardware_uuid": kindString,
+	"id":            kindNumber, "seen_count": kindNumber, "absent_count": kindNumber,
+	"first_seen": kindTime, "last_seen": kindTime,
+	"created_at": kindTime, "updated_at": kindTime,
+	"ips": kindArray, "macs": kindArray,
+}
+
+// jsonColumns are the JSONB columns addressable as <column>.<key>.
+var jsonColumns = map[string]bool{"attributes": true, "tags": true, "custom": true}
+
+type valueKind int
+
+const (
+	valString valueKind = iota
+	valNumber
+	valTime
+)
+
+type value struct {
+	kind valueKind
+	str  string
+	num  float64
+	at   time.Time
+}
+
+// timeNow is swapped out in tests to pin now() expressions.
+var timeNow = time.Now
+
+// Parse parses one query expression. Errors are *Error values carrying
+// the offset of the offending token.
+func Parse(input string) (Expr, error) {
+	toks, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errf(t.pos, "unexpected %q after expression", t.text)
+	}
+	return e, nil
+}
+
+// --- lexer ---
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func lex(input string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == '"':
+			start := i
+			i++
+			var sb strings.Builder
+			for {
+				if i >= len(input) {
+					return nil, errf(start, "unterminated string")
+				}
+				if input[i] == '\\' && i+1 < len(input) {
+					sb.WriteByte(input[i+1])
+					i += 2
+					continue
+				}
+				if input[i] == '"' {
+					i++
+					break
+				}
+				sb.WriteByte(input[i])
+				i++
+			}
+			toks = append(toks, token{tokString, sb.String(), start})
+		case c == '=' || c == '~' || c == '+' || c == '-':
+			toks = append(toks, token{tokOp, string(c), i})
+			i++
+		case c == '!':
+			if i+1 < len(input) && (input[i+1] == '=' || input[i+1] == '~') {
+				toks = append(toks, token{tokOp, input[i : i+2], i})
+				i += 2
+			} else {
+				return nil, errf(i, "unexpected %q", "!")
+			}
+		case c == '<' || c == '>':
+			if i+1 < len(input) && input[i+1] == '=' {
+				toks = append(toks, token{tokOp, input[i : i+2], i})
+				i += 2
+			} else {
+				toks = append(toks, token{tokOp, string(c), i})
+				i++
+			}
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(input) && (input[i] >= '0' && input[i] <= '9' || input[i] == '.') {
+				i++
+			}
+			toks = append(toks, token{tokNumber, input[start:i], start})
+		case isIdentRune(c):
+			start := i
+			for i < len(input) && (isIdentRune(input[i]) || input[i] >= '0' && input[i] <= '9' || input[i] == '.') {
+				i++
+			}
+			toks = append(toks, token{tokIdent, input[start:i], start})
+		default:
+			return nil, errf(i, "unexpected %q", string(c))
+		}
+	}
+	return append(toks, token{tokEOF, "", len(input)}), nil
+}
+
+func isIdentRune(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+// --- parser ---
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	p.i++
+	return t
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokIdent && strings.EqualFold(t.text, word) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, errf(t.pos, "expected closing parenthesis, got %q", t.text)
+		}
+		return e, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *parser) parseCmp() (Expr, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return nil, errf(t.pos, "expected field name, got %q", t.text)
+	}
+	var cmp cmpExpr
+	if base, key, ok := strings.Cut(t.text, "."); ok && jsonColumns[base] && key != "" {
+		cmp.col, cmp.key, cmp.kind = base, key, kindJSON
+	} else if kind, ok := fields[t.text]; ok {
+		cmp.col, cmp.kind = t.text, kind
+	} else {
+		return nil, errf(t.pos, "unknown field %q", t.text)
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp || opTok.text == "+" || opTok.text == "-" {
+		return nil, errf(opTok.pos, "expected comparison operator, got %q", opTok.text)
+	}
+	cmp.op = opTok.text
+
+	val, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	cmp.val = val
+	if err := checkCmp(&cmp, opTok.pos); err != nil {
+		return nil, err
+	}
+	return cmp, nil
+}
+
+func (p *parser) parseValue() (value, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return value{kind: valString, str: t.text}, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return value{}, errf(t.pos, "invalid number %q", t.text)
+		}
+		return value{kind: valNumber, num: n}, nil
+	case tokIdent:
+		if !strings.EqualFold(t.text, "now") {
+			return value{}, errf(t.pos, "expected a value, got %q", t.text)
+		}
+		if lp := p.next(); lp.kind != tokLParen {
+			return value{}, errf(lp.pos, "expected ( after now")
+		}
+		if rp := p.next(); rp.kind != tokRParen {
+			return value{}, errf(rp.pos, "expected ) after now(")
+		}
+		at := timeNow()
+		if s := p.peek(); s.kind == tokOp && (s.text == "-" || s.text == "+") {
+			p.next()
+			num := p.next()
+			if num.kind != tokNumber {
+				return value{}, errf(num.pos, "expected a duration after now()%s", s.text)
+			}
+			n, err := strconv.ParseFloat(num.text, 64)
+			if err != nil {
+				return value{}, errf(num.pos, "invalid number %q", num.text)
+			}
+			unit := p.next()
+			d, ok := durationUnit(unit.text)
+			if !ok {
+				return value{}, errf(unit.pos, "invalid duration unit %q, want s, m, h, d or w", unit.text)
+			}
+			offset := time.Duration(n * float64(d))
+			if s.text == "-" {
+				offset = -offset
+			}
+			at = at.Add(offset)
+		}
+		return value{kind: valTime, at: at}, nil
+	default:
+		return value{}, errf(t.pos, "expected a value, got %q", t.text)
+	}
+}
+
+func durationUnit(text string) (time.Duration, bool) {
+	switch text {
+	case "s":
+		return time.Second, true
+	case "m":
+		return time.Minute, true
+	case "h":
+		return time.Hour, true
+	case "d":
+		return 24 * time.Hour, true
+	case "w":
+		return 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
+
+// checkCmp verifies the operator and value fit the field's type, so
+// mismatches fail at parse time instead of as SQL errors.
+func checkCmp(c *cmpExpr, pos int) error {
+	// Time fields also accept quoted timestamps and dates.
+	if c.kind == kindTime && c.val.kind == valString {
+		at, err := time.Parse(time.RFC3339, c.val.str)
+		if err != nil {
+			at, err = time.Parse("2006-01-02", c.val.str)
+		}
+		if err != nil {
+			return errf(pos, "invalid time %q, want RFC 3339 or YYYY-MM-DD", c.val.str)
+		}
+		c.val = value{kind: valTime, at: at}
+	}
+	switch c.kind {
+	case kindString, kindArray:
+		if c.val.kind != valString {
+			return errf(pos, "field %s needs a quoted string value", c.col)
+		}
+		if !textOp(c.op) {
+			return errf(pos, "operator %s does not apply to field %s", c.op, c.col)
+		}
+	case kindNumber:
+		if c.val.kind != valNumber {
+			return errf(pos, "field %s needs a number value", c.col)
+		}
+		if !orderedOp(c.op) {
+			return errf(pos, "operator %s does not apply to field %s", c.op, c.col)
+		}
+	case kindTime:
+		if c.val.kind != valTime {
+			return errf(pos, "field %s needs a time value", c.col)
+		}
+		if !orderedOp(c.op) {
+			return errf(pos, "operator %s does not apply to field %s", c.op, c.col)
+		}
+	case kindJSON:
+		switch c.val.kind {
+		case valString:
+			if !textOp(c.op) {
+				return errf(pos, "operator %s does not apply to %s.%s", c.op, c.col, c.key)
+			}
+		case valNumber:
+			if !orderedOp(c.op) {
+				return errf(pos, "operator %s does not apply to %s.%s", c.op, c.col, c.key)
+			}
+		default:
+			return errf(pos, "field %s.%s needs a string or number value", c.col, c.key)
+		}
+	}
+	return nil
+}
+
+func textOp(op string) bool {
+	return op == "=" || op == "!=" || op == "~" || op == "!~"
+}
+
+func orderedOp(op string) bool {
+	return op == "=" || op == "!=" || op == "<" || op == "<=" || op == ">" || op == ">="
+}
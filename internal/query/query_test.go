@@ -0,0 +1,124 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAndCompile(t *testing.T) {
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		in      string
+		sql     string
+		args    []any
+		wantErr string
+	}{
+		{
+			in:   `asset_type = "vm"`,
+			sql:  `asset_type = $1`,
+			args: []any{"vm"},
+		},
+		{
+			in:   `name ~ "db" AND status != "retired"`,
+			sql:  `(name ILIKE '%' || $1 || '%' AND status <> $2)`,
+			args: []any{"db", "retired"},
+		},
+		{
+			in:   `seen_count >= 3`,
+			sql:  `seen_count >= $1`,
+			args: []any{3.0},
+		},
+		{
+			in:   `last_seen < now()-7d`,
+			sql:  `last_seen < $1`,
+			args: []any{now.Add(-7 * 24 * time.Hour)},
+		},
+		{
+			in:   `first_seen >= "2026-01-01"`,
+			sql:  `first_seen >= $1`,
+			args: []any{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			in:   `attributes.os ~ "Ubuntu"`,
+			sql:  `coalesce(attributes->>$1, '') ILIKE '%' || $2 || '%'`,
+			args: []any{"os", "Ubuntu"},
+		},
+		{
+			in:   `attributes.cpu_count > 4`,
+			sql:  `(coalesce(attributes->>$1, '') ~ '^-?[0-9]+(\.[0-9]+)?$' AND (coalesce(attributes->>$1, ''))::numeric > $2)`,
+			args: []any{"cpu_count", 4.0},
+		},
+		{
+			in:   `tags.env = "prod" OR tags.env = "staging"`,
+			sql:  `(coalesce(tags->>$1, '') = $2 OR coalesce(tags->>$3, '') = $4)`,
+			args: []any{"env", "prod", "env", "staging"},
+		},
+		{
+			in:   `ips = "10.1.2.3"`,
+			sql:  `$1 = ANY(ips)`,
+			args: []any{"10.1.2.3"},
+		},
+		{
+			in:   `NOT (site = "ams" OR site = "fra")`,
+			sql:  `NOT ((site = $1 OR site = $2))`,
+			args: []any{"ams", "fra"},
+		},
+		{
+			in:   `asset_type = "vm" AND (owner = "alice" OR owner = "bob") AND macs ~ "de:ad"`,
+			sql:  `((asset_type = $1 AND (owner = $2 OR owner = $3)) AND array_to_string(macs, ' ') ILIKE '%' || $4 || '%')`,
+			args: []any{"vm", "alice", "bob", "de:ad"},
+		},
+		{in: ``, wantErr: "expected field name"},
+		{in: `bogus = "x"`, wantErr: `unknown field "bogus"`},
+		{in: `name = vm`, wantErr: "expected a value"},
+		{in: `name < "a"`, wantErr: "operator < does not apply"},
+		{in: `seen_count = "3"`, wantErr: "needs a number"},
+		{in: `last_seen < "yesterday"`, wantErr: "invalid time"},
+		{in: `last_seen < now()-7x`, wantErr: "invalid duration unit"},
+		{in: `name = "a`, wantErr: "unterminated string"},
+		{in: `(name = "a"`, wantErr: "closing parenthesis"},
+		{in: `name = "a" bogus`, wantErr: `unexpected "bogus" after expression`},
+		{in: `attributes. = "x"`, wantErr: "unknown field"},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.in)
+		if tt.wantErr != "" {
+			if err == nil {
+				t.Errorf("Parse(%q): expected error containing %q, got none", tt.in, tt.wantErr)
+			} else if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q): error %q does not contain %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error %v", tt.in, err)
+			continue
+		}
+		sql, args := Compile(e, 0)
+		if sql != tt.sql {
+			t.Errorf("Compile(%q):\n got %s\nwant %s", tt.in, sql, tt.sql)
+		}
+		if !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("Compile(%q): args %v, want %v", tt.in, args, tt.args)
+		}
+	}
+}
+
+func TestCompileArgOffset(t *testing.T) {
+	e, err := Parse(`name = "web" AND seen_count > 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sql, args := Compile(e, 4)
+	if want := `(name = $5 AND seen_count > $6)`; sql != want {
+		t.Errorf("got %s, want %s", sql, want)
+	}
+	if len(args) != 2 {
+		t.Errorf("got %d args, want 2", len(args))
+	}
+}
@@ -0,0 +1,92 @@
+package query
+
+import "fmt"
+
+// Compile renders a parsed expression as one SQL condition against the
+// assets table, numbering bind parameters from argOffset+1 so the
+// result can be appended to an existing WHERE clause.
+func Compile(e Expr, argOffset int) (string, []any) {
+	c := &compiler{n: argOffset}
+	return c.compile(e), c.args
+}
+
+type compiler struct {
+	n    int
+	args []any
+}
+
+// bind adds one argument and returns its placeholder.
+func (c *compiler) bind(v any) string {
+	c.n++
+	c.args = append(c.args, v)
+	return fmt.Sprintf("$%d", c.n)
+}
+
+func (c *compiler) compile(e Expr) string {
+	switch e := e.(type) {
+	case andExpr:
+		return "(" + c.compile(e.left) + " AND " + c.compile(e.right) + ")"
+	case orExpr:
+		return "(" + c.compile(e.left) + " OR " + c.compile(e.right) + ")"
+	case notExpr:
+		return "NOT (" + c.compile(e.expr) + ")"
+	case cmpExpr:
+		return c.compileCmp(e)
+	}
+	panic(fmt.Sprintf("query: unknown expression %T", e))
+}
+
+func (c *compiler) compileCmp(e cmpExpr) string {
+	switch e.kind {
+	case kindString:
+		return textCond(e.col, e.op, c.bind(e.val.str))
+	case kindNumber:
+		return fmt.Sprintf("%s %s %s", e.col, sqlOp(e.op), c.bind(e.val.num))
+	case kindTime:
+		return fmt.Sprintf("%s %s %s", e.col, sqlOp(e.op), c.bind(e.val.at))
+	case kindArray:
+		p := c.bind(e.val.str)
+		switch e.op {
+		case "=":
+			return fmt.Sprintf("%s = ANY(%s)", p, e.col)
+		case "!=":
+			return fmt.Sprintf("NOT (%s = ANY(%s))", p, e.col)
+		default: // ~ / !~
+			return textCond(fmt.Sprintf("array_to_string(%s, ' ')", e.col), e.op, p)
+		}
+	case kindJSON:
+		// Missing keys read as empty string, so != and !~ match assets
+		// that do not carry the key at all.
+		text := fmt.Sprintf("coalesce(%s->>%s, '')", e.col, c.bind(e.key))
+		if e.val.kind == valString {
+			return textCond(text, e.op, c.bind(e.val.str))
+		}
+		// Numeric comparisons only apply where the stored text is a
+		// number; anything else simply does not match.
+		return fmt.Sprintf(`(%s ~ '^-?[0-9]+(\.[0-9]+)?$' AND (%s)::numeric %s %s)`,
+			text, text, sqlOp(e.op), c.bind(e.val.num))
+	}
+	panic(fmt.Sprintf("query: unknown field kind %d", e.kind))
+}
+
+// textCond renders the string operators against an arbitrary text
+// expression; ~ is the same substring ILIKE the search filter uses.
+func textCond(expr, op, placeholder string) string {
+	switch op {
+	case "=":
+		return fmt.Sprintf("%s = %s", expr, placeholder)
+	case "!=":
+		return fmt.Sprintf("%s <> %s", expr, placeholder)
+	case "~":
+		return fmt.Sprintf("%s ILIKE '%%' || %s || '%%'", expr, placeholder)
+	default: // !~
+		return fmt.Sprintf("%s NOT ILIKE '%%' || %s || '%%'", expr, placeholder)
+	}
+}
+
+func sqlOp(op string) string {
+	if op == "!=" {
+		return "<>"
+	}
+	return op
+}
@@ -0,0 +1,36 @@
+package reconcile
+
+// authorityRules grant sources exclusive say over a field: the key is
+// a first-class field ("name", "fqdn", "asset_type", "site") or an
+// attribute name, the value the sources allowed to set it in
+// precedence order. A field without a rule keeps last-writer-wins
+// semantics; a field with one is never set by an unlisted source, and
+// among listed sources the earliest one reporting a value wins.
+type authorityRules map[string][]string
+
+// allows reports whether the source may set the field at all.
+func (rules authorityRules) allows(field, source string) bool {
+	order, ok := rules[field]
+	if !ok {
+		return true
+	}
+	for _, s := range order {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRuled overlays the ruled attribute keys onto merged, taking each
+// key from the highest-precedence source that reports it. bySource maps
+// source name to that source's stored attribute payload.
+func (rules authorityRules) mergeRuled(merged map[string]any, bySource map[string]map[string]any) {
+	for field, order := range rules {
+		for i := len(order) - 1; i >= 0; i-- {
+			if v, ok := bySource[order[i]][field]; ok {
+				merged[field] = v
+			}
+		}
+	}
+}
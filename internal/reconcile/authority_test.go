@@ -0,0 +1,52 @@
+package reconcile
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+func TestAuthorityRules(t *testing.T) {
+	rules := authorityRules{
+		"name": {"vmware", "netbox"},
+		"os":   {"ansible"},
+	}
+	if !rules.allows("name", "vmware") || !rules.allows("name", "netbox") {
+		t.Error("listed sources should be allowed to set name")
+	}
+	if rules.allows("name", "nmap") {
+		t.Error("unlisted source allowed to set ruled field name")
+	}
+	if !rules.allows("fqdn", "nmap") {
+		t.Error("unruled field should allow any source")
+	}
+
+	merged := map[string]any{}
+	rules.mergeRuled(merged, map[string]map[string]any{
+		"vmware":  {"name": "vm-view", "os": "should-not-win"},
+		"netbox":  {"name": "netbox-view"},
+		"ansible": {"os": "Debian 12"},
+	})
+	want := map[string]any{"name": "vm-view", "os": "Debian 12"}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("mergeRuled = %v, want %v", merged, want)
+	}
+}
+
+func TestMergeHonorsAuthority(t *testing.T) {
+	rules := authorityRules{"name": {"vmware"}}
+	existing := &model.Asset{Name: "esx-name", FQDN: "a.example.com"}
+	incoming := &model.Asset{Source: "nmap", Name: "scanner-name", FQDN: "b.example.com"}
+	merge(existing, incoming, rules)
+	if existing.Name != "esx-name" {
+		t.Errorf("unauthoritative source overwrote name: %q", existing.Name)
+	}
+	if existing.FQDN != "b.example.com" {
+		t.Errorf("unruled field not merged: %q", existing.FQDN)
+	}
+	before, after := diffAssets(&model.Asset{Name: "esx-name"}, incoming, rules)
+	if _, ok := after["name"]; ok {
+		t.Errorf("diff reports a name change merge would not apply: %v -> %v", before, after)
+	}
+}
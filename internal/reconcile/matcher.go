@@ -0,0 +1,166 @@
+package reconcile
+
+import (
+	"context"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// Matcher is one strategy in the reconciler's ordered matching chain.
+// A strategy returns every existing asset the incoming one could be;
+// the reconciler owns the ambiguity rules (only a single candidate is
+// accepted) and weighs the strategy's confidence against the configured
+// merge threshold.
+type Matcher interface {
+	// Name identifies the strategy in logs and conflict records.
+	Name() string
+	// Confidence scores an unambiguous match from this strategy in
+	// (0, 1]. Exactly 1 means the identity is authoritative (the
+	// source's own external ID or a recorded alias); anything lower is
+	// an inference.
+	Confidence() float64
+	// Match returns the candidate assets, empty when the strategy has
+	// no opinion (e.g. the incoming asset lacks the field it keys on).
+	Match(ctx context.Context, st store.Store, incoming *model.Asset) ([]*model.Asset, error)
+}
+
+// defaultMatchers is the built-in chain, strongest signal first. The
+// chain stops at the first strategy producing a single candidate.
+func defaultMatchers() []Matcher {
+	return []Matcher{
+		externalIDMatcher{},
+		aliasMatcher{},
+		serialMatcher{},
+		macMatcher{},
+		ipMatcher{},
+		fqdnMatcher{},
+		nameMatcher{},
+	}
+}
+
+// externalIDMatcher resolves the source's own (source, external_id)
+// key — the authoritative identity for re-observations.
+type externalIDMatcher struct{}
+
+func (externalIDMatcher) Name() string        { return "external_id" }
+func (externalIDMatcher) Confidence() float64 { return 1 }
+
+func (externalIDMatcher) Match(ctx context.Context, st store.Store, incoming *model.Asset) ([]*model.Asset, error) {
+	a, err := st.GetAssetByExternalID(ctx, incoming.Source, incoming.ExternalID)
+	if err == store.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Asset{a}, nil
+}
+
+// aliasMatcher resolves the incoming (source, external_id) through the
+// alias table, where earlier cross-source matches were made durable.
+type aliasMatcher struct{}
+
+func (aliasMatcher) Name() string        { return "alias" }
+func (aliasMatcher) Confidence() float64 { return 1 }
+
+func (aliasMatcher) Match(ctx context.Context, st store.Store, incoming *model.Asset) ([]*model.Asset, error) {
+	id, err := st.GetAssetIDByAlias(ctx, incoming.Source, incoming.ExternalID)
+	if err == store.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	a, err := st.GetAssetByID(ctx, id)
+	if err == store.ErrNotFound {
+		// Dangling alias; the cascade should prevent this, but don't
+		// fail the asset over it.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Asset{a}, nil
+}
+
+// serialMatcher keys on hardware identity: the chassis serial or the
+// SMBIOS/BIOS UUID.
+type serialMatcher struct{}
+
+func (serialMatcher) Name() string        { return "serial" }
+func (serialMatcher) Confidence() float64 { return 0.95 }
+
+func (serialMatcher) Match(ctx context.Context, st store.Store, incoming *model.Asset) ([]*model.Asset, error) {
+	if incoming.SerialNumber == "" && incoming.HardwareUUID == "" {
+		return nil, nil
+	}
+	return st.FindAssetsBySerial(ctx, incoming.SerialNumber, incoming.HardwareUUID)
+}
+
+// macMatcher keys on shared MAC addresses.
+type macMatcher struct{}
+
+func (macMatcher) Name() string        { return "mac" }
+func (macMatcher) Confidence() float64 { return 0.85 }
+
+func (macMatcher) Match(ctx context.Context, st store.Store, incoming *model.Asset) ([]*model.Asset, error) {
+	if len(incoming.MACs) == 0 {
+		return nil, nil
+	}
+	return st.FindAssetsByMAC(ctx, incoming.MACs)
+}
+
+// ipMatcher keys on shared IP addresses. IPs get reassigned, so this
+// ranks below hardware identity.
+type ipMatcher struct{}
+
+func (ipMatcher) Name() string        { return "ip" }
+func (ipMatcher) Confidence() float64 { return 0.7 }
+
+func (ipMatcher) Match(ctx context.Context, st store.Store, incoming *model.Asset) ([]*model.Asset, error) {
+	if len(incoming.IPs) == 0 {
+		return nil, nil
+	}
+	return st.FindAssetsByIPs(ctx, incoming.IPs)
+}
+
+// fqdnMatcher keys on an exact FQDN.
+type fqdnMatcher struct{}
+
+func (fqdnMatcher) Name() string        { return "fqdn" }
+func (fqdnMatcher) Confidence() float64 { return 0.7 }
+
+func (fqdnMatcher) Match(ctx context.Context, st store.Store, incoming *model.Asset) ([]*model.Asset, error) {
+	if incoming.FQDN == "" {
+		return nil, nil
+	}
+	return st.FindAssetsByIPOrFQDN(ctx, nil, incoming.FQDN)
+}
+
+// nameMatcher keys on a case-insensitive name match. Names are chosen
+// by humans and collide freely, so this never clears the default
+// threshold on its own — it exists to surface merge candidates.
+type nameMatcher struct{}
+
+func (nameMatcher) Name() string        { return "name" }
+func (nameMatcher) Confidence() float64 { return 0.5 }
+
+func (nameMatcher) Match(ctx context.Context, st store.Store, incoming *model.Asset) ([]*model.Asset, error) {
+	if incoming.Name == "" {
+		return nil, nil
+	}
+	candidates, err := st.FindAssetsByName(ctx, incoming.Name)
+	if err != nil {
+		return nil, err
+	}
+	// Drop same-source hits: a colliding name within one source is two
+	// distinct assets by definition (their external IDs differ).
+	out := candidates[:0]
+	for _, c := range candidates {
+		if c.Source != incoming.Source {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package reconcile
+
+import (
+	"net"
+	"strings"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// NormalizeConfig defines the source-independent cleanup applied to
+// every incoming asset before enrichment and matching, so the rules
+// live in configuration rather than being re-implemented (slightly
+// differently) in each collector.
+type NormalizeConfig struct {
+	// LowercaseFQDN lowercases FQDNs, so a case-mangling source still
+	// matches the asset everyone else reports in lowercase.
+	LowercaseFQDN bool
+	// StripDomains removes the listed domain suffixes from asset
+	// names, so "db01.corp.example.com" and "db01" agree. The FQDN
+	// keeps the full form.
+	StripDomains []string
+	// AssetTypes maps vendor-specific type strings to canonical ones,
+	// e.g. "Virtual Machine" -> "vm". Keys match exactly.
+	AssetTypes map[string]string
+	// DropIPs removes matching addresses from incoming assets: the
+	// named classes "loopback" and "link-local", or CIDR blocks.
+	DropIPs []string
+	// RenameAttributes moves attribute values between keys, e.g.
+	// "serialNumber" -> "serial", so differently-named source fields
+	// land under one key. An existing value under the target key is
+	// never overwritten.
+	RenameAttributes map[string]string
+}
+
+// normalizer is the compiled form of NormalizeConfig.
+type normalizer struct {
+	cfg           NormalizeConfig
+	stripSuffixes []string
+	dropNets      []*net.IPNet
+	dropLoopback  bool
+	dropLinkLocal bool
+}
+
+func newNormalizer(cfg NormalizeConfig) *normalizer {
+	n := &normalizer{cfg: cfg}
+	for _, d := range cfg.StripDomains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			n.stripSuffixes = append(n.stripSuffixes, "."+d)
+		}
+	}
+	for _, spec := range cfg.DropIPs {
+		switch spec {
+		case "loopback":
+			n.dropLoopback = true
+		case "link-local":
+			n.dropLinkLocal = true
+		default:
+			// Config validation already rejected unparsable entries.
+			if _, ipnet, err := net.ParseCIDR(spec); err == nil {
+				n.dropNets = append(n.dropNets, ipnet)
+			}
+		}
+	}
+	return n
+}
+
+// apply rewrites one incoming asset in place.
+func (n *normalizer) apply(a *model.Asset) {
+	if n.cfg.LowercaseFQDN && a.FQDN != "" {
+		a.FQDN = strings.ToLower(a.FQDN)
+	}
+	if a.Name != "" && len(n.stripSuffixes) > 0 {
+		lower := strings.ToLower(a.Name)
+		for _, suffix := range n.stripSuffixes {
+			if strings.HasSuffix(lower, suffix) && len(lower) > len(suffix) {
+				a.Name = a.Name[:len(a.Name)-len(suffix)]
+				break
+			}
+		}
+	}
+	if mapped, ok := n.cfg.AssetTypes[a.AssetType]; ok {
+		a.AssetType = mapped
+	}
+	if len(a.IPs) > 0 && (n.dropLoopback || n.dropLinkLocal || len(n.dropNets) > 0) {
+		kept := a.IPs[:0]
+		for _, raw := range a.IPs {
+			if !n.dropIP(raw) {
+				kept = append(kept, raw)
+			}
+		}
+		a.IPs = kept
+	}
+	for from, to := range n.cfg.RenameAttributes {
+		v, ok := a.Attributes[from]
+		if !ok {
+			continue
+		}
+		delete(a.Attributes, from)
+		if _, taken := a.Attributes[to]; !taken {
+			a.Attributes[to] = v
+		}
+	}
+}
+
+func (n *normalizer) dropIP(raw string) bool {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return false
+	}
+	if n.dropLoopback && ip.IsLoopback() {
+		return true
+	}
+	if n.dropLinkLocal && (ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()) {
+		return true
+	}
+	for _, ipnet := range n.dropNets {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
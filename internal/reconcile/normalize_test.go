@@ -0,0 +1,60 @@
+package reconcile
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+func TestNormalizerApply(t *testing.T) {
+	n := newNormalizer(NormalizeConfig{
+		LowercaseFQDN: true,
+		StripDomains:  []string{"corp.example.com"},
+		AssetTypes:    map[string]string{"Virtual Machine": "vm"},
+		DropIPs:       []string{"loopback", "link-local", "10.99.0.0/16"},
+		RenameAttributes: map[string]string{
+			"serialNumber": "serial",
+			"osName":       "os",
+		},
+	})
+	a := &model.Asset{
+		Name:      "DB01.corp.example.com",
+		FQDN:      "DB01.Corp.Example.COM",
+		AssetType: "Virtual Machine",
+		IPs:       []string{"127.0.0.1", "169.254.10.1", "10.99.3.4", "192.168.1.5"},
+		Attributes: map[string]any{
+			"serialNumber": "ABC123",
+			"osName":       "linux",
+			"os":           "debian", // existing value must win over the rename
+		},
+	}
+	n.apply(a)
+	if a.Name != "DB01" {
+		t.Errorf("Name = %q, want DB01", a.Name)
+	}
+	if a.FQDN != "db01.corp.example.com" {
+		t.Errorf("FQDN = %q, want db01.corp.example.com", a.FQDN)
+	}
+	if a.AssetType != "vm" {
+		t.Errorf("AssetType = %q, want vm", a.AssetType)
+	}
+	if want := []string{"192.168.1.5"}; !reflect.DeepEqual(a.IPs, want) {
+		t.Errorf("IPs = %v, want %v", a.IPs, want)
+	}
+	want := map[string]any{"serial": "ABC123", "os": "debian"}
+	if !reflect.DeepEqual(a.Attributes, want) {
+		t.Errorf("Attributes = %v, want %v", a.Attributes, want)
+	}
+}
+
+func TestNormalizerNameNotEmptied(t *testing.T) {
+	// A name that is exactly the stripped domain must survive: better a
+	// redundant name than none at all.
+	n := newNormalizer(NormalizeConfig{StripDomains: []string{"corp.example.com"}})
+	a := &model.Asset{Name: "corp.example.com"}
+	n.apply(a)
+	if a.Name != "corp.example.com" {
+		t.Errorf("Name = %q, want corp.example.com", a.Name)
+	}
+}
@@ -0,0 +1,69 @@
+package reconcile
+
+import (
+	"context"
+	"time"
+
+	"github.com/qrunner/arch/internal/store"
+)
+
+// RebuildReport summarizes one rebuild pass over the stored inventory.
+type RebuildReport struct {
+	Scanned  int           `json:"scanned"`
+	Updated  int           `json:"updated"`
+	Errors   int           `json:"errors"`
+	Batches  int           `json:"batches"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Rebuild re-runs the derivation logic — enrichers and the name
+// collision policy — over every stored asset and re-mirrors each into
+// the graph, without re-collecting from sources. It lets rule changes
+// (new site mappings, a different collision policy, custom relationship
+// types) apply retroactively instead of waiting for the next collection
+// cycle. Assets are processed in pages; per-asset errors are counted
+// and logged but do not abort the pass.
+func (r *Reconciler) Rebuild(ctx context.Context) (*RebuildReport, error) {
+	start := time.Now()
+	report := &RebuildReport{}
+	const pageSize = 100
+	for offset := 0; ; offset += pageSize {
+		assets, _, err := r.store.ListAssets(ctx, store.AssetFilter{Limit: pageSize, Offset: offset})
+		if err != nil {
+			return report, err
+		}
+		if len(assets) == 0 {
+			break
+		}
+		report.Batches++
+		for _, a := range assets {
+			report.Scanned++
+			beforeFQDN, beforeSite, beforeDisplay := a.FQDN, a.Site, a.DisplayName
+			for _, e := range r.enrichers {
+				e.Enrich(ctx, a)
+			}
+			r.applyCollisionPolicy(ctx, a)
+			if a.FQDN != beforeFQDN || a.Site != beforeSite || a.DisplayName != beforeDisplay {
+				if err := r.store.UpdateAsset(ctx, a); err != nil {
+					r.log.Error("rebuild: update failed", "asset_id", a.ID, "error", err)
+					report.Errors++
+					continue
+				}
+				report.Updated++
+			}
+			if err := r.graph.UpsertAsset(ctx, a); err != nil {
+				r.log.Warn("rebuild: graph upsert failed", "asset_id", a.ID, "error", err)
+				report.Errors++
+			}
+		}
+		r.log.Info("rebuild: batch complete", "offset", offset,
+			"scanned", report.Scanned, "updated", report.Updated, "errors", report.Errors)
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+	}
+	report.Duration = time.Since(start)
+	r.log.Info("rebuild: pass complete", "scanned", report.Scanned,
+		"updated", report.Updated, "errors", report.Errors, "duration", report.Duration)
+	return report, nil
+}
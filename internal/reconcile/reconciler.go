@@ -0,0 +1,964 @@
+// Package reconcile merges collector output into the asset store. The
+// reconciler owns matching (is this incoming asset one we already
+// know?), change detection and change-event emission, and mirrors the
+// result into the graph store.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qrunner/arch/internal/events"
+	"github.com/qrunner/arch/internal/graph"
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// EventSink receives change events after they have been persisted. The
+// notifier and the live-update hub both implement it.
+type EventSink interface {
+	Publish(e *model.ChangeEvent)
+}
+
+// Enricher fills in derived asset fields before matching; the reverse
+// DNS and site-mapping enrichers implement it.
+type Enricher interface {
+	Enrich(ctx context.Context, a *model.Asset)
+}
+
+// Config tunes reconciler behavior.
+type Config struct {
+	// BatchSize chunks runs into batches of this many assets; each
+	// batch is applied and reported separately.
+	BatchSize int
+	// Workers is the number of assets applied concurrently within a
+	// batch. Exact-identity lookups are prefetched per batch, so workers
+	// mostly pay for fuzzy matching and writes. Defaults to 4.
+	Workers int
+	// NameCollisionPolicy disambiguates assets sharing a name in the
+	// derived display_name field: "none", "append-source" or
+	// "append-ip".
+	NameCollisionPolicy string
+	// MaxDropPercent refuses runs reporting more than this percentage
+	// fewer assets than the source already has; 100 disables the drop
+	// check. Empty runs against a non-empty source are always refused.
+	MaxDropPercent int
+	// MatchThreshold is the minimum matcher confidence required to
+	// merge an incoming asset into an existing one. A match below the
+	// threshold creates the asset anyway and records a merge candidate
+	// for review instead. Defaults to 0.7.
+	MatchThreshold float64
+	// Authority grants sources exclusive say over fields: the key is a
+	// first-class field (name, fqdn, asset_type, site) or an attribute
+	// name, the value the sources allowed to set it in precedence
+	// order. Unruled fields keep last-writer-wins semantics.
+	Authority map[string][]string
+	// Normalize is the configurable cleanup stage run on every
+	// incoming asset before enrichment and matching.
+	Normalize NormalizeConfig
+}
+
+// SnapshotGuardError reports a run refused by the snapshot guard
+// because it would imply a suspicious shrink of the source's inventory.
+// The run can be re-issued with the force flag once verified.
+type SnapshotGuardError struct {
+	Source   string
+	Existing int64
+	Incoming int
+}
+
+// Error implements the error interface.
+func (e *SnapshotGuardError) Error() string {
+	return fmt.Sprintf("snapshot guard: source %q reported %d assets but has %d; refusing without force",
+		e.Source, e.Incoming, e.Existing)
+}
+
+// Reconciler applies CollectResults to the store and graph.
+type Reconciler struct {
+	store     store.Store
+	graph     *graph.Store
+	publisher *events.Publisher
+	enrichers []Enricher
+	matchers  []Matcher
+	rules     authorityRules
+	norm      *normalizer
+	log       *slog.Logger
+	cfg       Config
+	sinks     []EventSink
+}
+
+// New builds a reconciler. graph and publisher may be nil; enrichers run
+// on every incoming asset before matching; sinks are notified of every
+// persisted change event.
+func New(st store.Store, gr *graph.Store, pub *events.Publisher, enrichers []Enricher, cfg Config, log *slog.Logger, sinks ...EventSink) *Reconciler {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.MatchThreshold <= 0 || cfg.MatchThreshold > 1 {
+		cfg.MatchThreshold = 0.7
+	}
+	return &Reconciler{store: st, graph: gr, publisher: pub, enrichers: enrichers,
+		matchers: defaultMatchers(), rules: cfg.Authority, norm: newNormalizer(cfg.Normalize),
+		log: log, cfg: cfg, sinks: sinks}
+}
+
+// Report summarizes what one reconcile run did.
+type Report struct {
+	Source        string        `json:"source"`
+	Created       int           `json:"created"`
+	Updated       int           `json:"updated"`
+	Unchanged     int           `json:"unchanged"`
+	Errors        int           `json:"errors"`
+	Relationships int           `json:"relationships"`
+	Duration      time.Duration `json:"duration"`
+	Batches       []BatchReport `json:"batches,omitempty"`
+}
+
+// BatchReport summarizes one batch of a chunked run.
+type BatchReport struct {
+	Offset    int           `json:"offset"`
+	Assets    int           `json:"assets"`
+	Created   int           `json:"created"`
+	Updated   int           `json:"updated"`
+	Unchanged int           `json:"unchanged"`
+	Errors    int           `json:"errors"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Reconcile merges one collector run into the inventory. Assets are
+// applied in batches of cfg.BatchSize so a failure is isolated to one
+// batch and progress is visible per batch. Per-asset errors are counted
+// and logged but do not abort the run. Runs that look dangerous as a
+// whole — a suspicious inventory drop or too much malformed data — are
+// quarantined for review instead of applied, reported as a
+// *QuarantinedError, unless force is set. Partial results (incremental
+// runs covering only changed assets) bypass the drop check and absence
+// tracking entirely.
+func (r *Reconciler) Reconcile(ctx context.Context, source string, res model.CollectResult, force bool) (*Report, error) {
+	if !force {
+		if reason := r.quarantineReason(ctx, source, res); reason != "" {
+			return nil, r.quarantine(ctx, source, res, reason)
+		}
+	}
+	start := time.Now()
+	report := &Report{Source: source}
+	// external ID -> canonical asset ID, for resolving relationship
+	// endpoints emitted by the same run.
+	resolved := make(map[string]int64, len(res.Assets))
+
+	for offset := 0; offset < len(res.Assets); offset += r.cfg.BatchSize {
+		end := offset + r.cfg.BatchSize
+		if end > len(res.Assets) {
+			end = len(res.Assets)
+		}
+		batch := r.reconcileBatch(ctx, source, res.Assets[offset:end], offset, resolved)
+		report.Created += batch.Created
+		report.Updated += batch.Updated
+		report.Unchanged += batch.Unchanged
+		report.Errors += batch.Errors
+		report.Batches = append(report.Batches, batch)
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+	}
+
+	// Every asset of this source that the run did not report was absent
+	// from it; the seen/absent ratio is the flapping signal. Partial
+	// runs say nothing about unreported assets, so they don't count.
+	if !res.Partial {
+		seenIDs := make([]int64, 0, len(resolved))
+		for _, id := range resolved {
+			seenIDs = append(seenIDs, id)
+		}
+		if _, err := r.store.IncrementAbsentCounts(ctx, source, seenIDs); err != nil {
+			r.log.Warn("reconcile: increment absent counts failed", "source", source, "error", err)
+		}
+	}
+
+	applied := make(map[graph.RelationshipRef]bool, len(res.Relationships))
+	for _, rel := range res.Relationships {
+		if err := r.applyRelationship(ctx, source, rel, resolved, applied); err != nil {
+			r.log.Warn("reconcile: relationship failed", "source", source,
+				"from", rel.FromExternalID, "to", rel.ToExternalID, "type", rel.Type, "error", err)
+			report.Errors++
+			continue
+		}
+		report.Relationships++
+	}
+	// A full run is the source's complete view of its topology too:
+	// edges it stopped reporting are gone, not merely unmentioned.
+	if !res.Partial {
+		r.pruneRelationships(ctx, source, applied)
+	}
+
+	report.Duration = time.Since(start)
+	// Persist the run so operators can see history beyond the
+	// scheduler's in-memory last-run snapshot. A recording failure
+	// doesn't fail the run — the inventory changes already landed.
+	run := &store.ReconcileRun{
+		Source: source, StartedAt: start.UTC(), FinishedAt: time.Now().UTC(),
+		Created: report.Created, Updated: report.Updated, Unchanged: report.Unchanged,
+		Errors: report.Errors, Relationships: report.Relationships, Duration: report.Duration,
+	}
+	if err := r.store.CreateReconcileRun(ctx, run); err != nil {
+		r.log.Warn("reconcile: record run failed", "source", source, "error", err)
+	}
+	r.publisher.Notify()
+	r.log.Info("reconcile: run complete", "source", source,
+		"created", report.Created, "updated", report.Updated,
+		"unchanged", report.Unchanged, "errors", report.Errors,
+		"relationships", report.Relationships, "duration", report.Duration)
+	return report, nil
+}
+
+// Sweep applies the source's lifecycle thresholds after a full run:
+// assets the source has not reported for removeAfter are transitioned
+// to removed (with asset.removed events), ones quiet for staleAfter to
+// stale. A zero duration disables the respective transition. Removal
+// runs first so a long-gone asset doesn't detour through stale.
+func (r *Reconciler) Sweep(ctx context.Context, source string, staleAfter, removeAfter time.Duration) error {
+	now := time.Now().UTC()
+	var moved int
+	if removeAfter > 0 {
+		transitions, err := r.store.TransitionAssets(ctx, source, now.Add(-removeAfter),
+			[]model.AssetStatus{model.StatusDiscovered, model.StatusActive, model.StatusStale}, model.StatusRemoved)
+		if err != nil {
+			return fmt.Errorf("sweep %s: %w", source, err)
+		}
+		for _, t := range transitions {
+			r.applyTransition(ctx, source, t, model.StatusRemoved, model.ActionAssetRemoved)
+		}
+		moved += len(transitions)
+	}
+	if staleAfter > 0 {
+		transitions, err := r.store.TransitionAssets(ctx, source, now.Add(-staleAfter),
+			[]model.AssetStatus{model.StatusDiscovered, model.StatusActive}, model.StatusStale)
+		if err != nil {
+			return fmt.Errorf("sweep %s: %w", source, err)
+		}
+		for _, t := range transitions {
+			r.applyTransition(ctx, source, t, model.StatusStale, model.ActionAssetUpdated)
+		}
+		moved += len(transitions)
+	}
+	if moved > 0 {
+		r.log.Info("reconcile: lifecycle sweep", "source", source, "transitioned", moved)
+		r.publisher.Notify()
+	}
+	return nil
+}
+
+// applyTransition mirrors one lifecycle move into the graph and the
+// change log.
+func (r *Reconciler) applyTransition(ctx context.Context, source string, t store.LifecycleTransition, to model.AssetStatus, action string) {
+	if err := r.graph.SetAssetStatus(ctx, t.ID, to); err != nil {
+		r.log.Warn("reconcile: graph status update failed", "asset_id", t.ID, "error", err)
+	}
+	r.emitEvent(ctx, &model.ChangeEvent{
+		AssetID: t.ID,
+		Action:  action,
+		Source:  source,
+		Before:  map[string]any{"status": t.From},
+		After:   map[string]any{"status": to, "name": t.Name, "last_seen": t.LastSeen},
+	})
+}
+
+// QuarantinedError reports a run held in the quarantine queue instead
+// of applied. Reviewing the queue entry or re-running the collector
+// with force releases it.
+type QuarantinedError struct {
+	ID     int64
+	Source string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *QuarantinedError) Error() string {
+	return fmt.Sprintf("run quarantined for review (id %d): %s", e.ID, e.Reason)
+}
+
+// maxMalformedPercent is the share of malformed assets (empty external
+// ID or unparsable IP) past which a whole result is suspect. Below it
+// the per-asset handling — drop and count — is protection enough.
+const maxMalformedPercent = 10
+
+// quarantineReason decides whether a run must be held for review
+// rather than applied; an empty string means the run is fine.
+func (r *Reconciler) quarantineReason(ctx context.Context, source string, res model.CollectResult) string {
+	if !res.Partial {
+		if err := r.checkSnapshotGuard(ctx, source, len(res.Assets)); err != nil {
+			return err.Error()
+		}
+	}
+	malformed := 0
+	for _, a := range res.Assets {
+		if a.ExternalID == "" || hasGarbageIP(a.IPs) {
+			malformed++
+		}
+	}
+	if len(res.Assets) > 0 && malformed*100 > len(res.Assets)*maxMalformedPercent {
+		return fmt.Sprintf("%d of %d assets are malformed (empty external_id or unparsable IP)",
+			malformed, len(res.Assets))
+	}
+	return ""
+}
+
+func hasGarbageIP(ips []string) bool {
+	for _, ip := range ips {
+		if net.ParseIP(ip) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// quarantine stores the refused result and returns a *QuarantinedError
+// pointing at the queue entry. Even when storing fails the run is not
+// applied — the original reason is surfaced alongside the failure.
+func (r *Reconciler) quarantine(ctx context.Context, source string, res model.CollectResult, reason string) error {
+	q := &store.QuarantinedRun{Source: source, Reason: reason, AssetCount: len(res.Assets), Result: &res}
+	if err := r.store.CreateQuarantinedRun(ctx, q); err != nil {
+		r.log.Error("reconcile: quarantine failed", "source", source, "reason", reason, "error", err)
+		return fmt.Errorf("quarantine (%s): %w", reason, err)
+	}
+	r.log.Warn("reconcile: run quarantined", "source", source, "id", q.ID,
+		"assets", len(res.Assets), "reason", reason)
+	return &QuarantinedError{ID: q.ID, Source: source, Reason: reason}
+}
+
+// checkSnapshotGuard compares the run's asset count against the
+// source's current inventory and refuses empty runs or drops beyond
+// cfg.MaxDropPercent. A misconfigured or half-failing source returning
+// a truncated snapshot otherwise marks everything absent (and, with
+// lifecycle sweeps, would remove it).
+func (r *Reconciler) checkSnapshotGuard(ctx context.Context, source string, incoming int) error {
+	counts, err := r.store.ListAssetCounts(ctx)
+	if err != nil {
+		return fmt.Errorf("snapshot guard: %w", err)
+	}
+	var existing int64
+	for _, c := range counts {
+		if c.Source == source {
+			existing += c.Count
+		}
+	}
+	if existing == 0 {
+		return nil
+	}
+	if incoming == 0 {
+		r.log.Error("reconcile: refusing empty run", "source", source, "existing", existing)
+		return &SnapshotGuardError{Source: source, Existing: existing, Incoming: incoming}
+	}
+	maxDrop := r.cfg.MaxDropPercent
+	if maxDrop <= 0 {
+		maxDrop = 50
+	}
+	if maxDrop < 100 && int64(incoming)*100 < existing*int64(100-maxDrop) {
+		r.log.Error("reconcile: refusing run with suspicious drop",
+			"source", source, "existing", existing, "incoming", incoming, "max_drop_percent", maxDrop)
+		return &SnapshotGuardError{Source: source, Existing: existing, Incoming: incoming}
+	}
+	return nil
+}
+
+// batchCache holds the batch-wide result of the exact-identity lookups:
+// the source's own external IDs and its recorded aliases, resolved in
+// two bulk queries instead of two round trips per asset. A key absent
+// from both maps is a definitive miss for the batch.
+type batchCache struct {
+	assets  map[string]*model.Asset
+	aliases map[string]int64
+}
+
+// prefetch bulk-resolves the batch's external IDs. A prefetch failure
+// degrades to nil — the per-asset lookups still work — rather than
+// failing the whole batch.
+func (r *Reconciler) prefetch(ctx context.Context, source string, assets []*model.Asset) *batchCache {
+	ids := make([]string, 0, len(assets))
+	for _, a := range assets {
+		if a.ExternalID != "" {
+			ids = append(ids, a.ExternalID)
+		}
+	}
+	byExternalID, err := r.store.GetAssetsByExternalIDs(ctx, source, ids)
+	if err != nil {
+		r.log.Warn("reconcile: prefetch assets failed", "source", source, "error", err)
+		return nil
+	}
+	byAlias, err := r.store.GetAssetIDsByAliases(ctx, source, ids)
+	if err != nil {
+		r.log.Warn("reconcile: prefetch aliases failed", "source", source, "error", err)
+		return nil
+	}
+	return &batchCache{assets: byExternalID, aliases: byAlias}
+}
+
+// reconcileBatch applies one chunk of a run's assets across a pool of
+// cfg.Workers goroutines, after bulk-prefetching the batch's exact
+// identities. Two in-flight assets sharing only a fuzzy signal can each
+// be created before the other lands; the duplicate review flow surfaces
+// those, and the next run resolves them through aliases. Assets
+// repeating an external ID within the batch are held back and applied
+// serially afterwards so two workers never race on the same row.
+func (r *Reconciler) reconcileBatch(ctx context.Context, source string, assets []*model.Asset, offset int, resolved map[string]int64) BatchReport {
+	start := time.Now()
+	batch := BatchReport{Offset: offset, Assets: len(assets)}
+	cache := r.prefetch(ctx, source, assets)
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		jobs  = make(chan *model.Asset)
+		seen  = make(map[string]bool, len(assets))
+		dupes []*model.Asset
+	)
+	for i := 0; i < r.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for incoming := range jobs {
+				r.reconcileOne(ctx, source, incoming, cache, resolved, &batch, &mu)
+			}
+		}()
+	}
+	for _, incoming := range assets {
+		if incoming.ExternalID != "" {
+			if seen[incoming.ExternalID] {
+				dupes = append(dupes, incoming)
+				continue
+			}
+			seen[incoming.ExternalID] = true
+		}
+		jobs <- incoming
+	}
+	close(jobs)
+	wg.Wait()
+	// Re-observations of an ID the parallel phase just created must not
+	// use the prefetch: it predates the create.
+	for _, incoming := range dupes {
+		r.reconcileOne(ctx, source, incoming, nil, resolved, &batch, &mu)
+	}
+	batch.Duration = time.Since(start)
+	return batch
+}
+
+// reconcileOne validates, enriches and applies a single incoming asset,
+// folding the result into the batch counters under mu.
+func (r *Reconciler) reconcileOne(ctx context.Context, source string, incoming *model.Asset, cache *batchCache, resolved map[string]int64, batch *BatchReport, mu *sync.Mutex) {
+	if incoming.ExternalID == "" {
+		r.log.Warn("reconcile: dropping asset without external_id", "source", source, "name", incoming.Name)
+		mu.Lock()
+		batch.Errors++
+		mu.Unlock()
+		return
+	}
+	incoming.Source = source
+	// Source-independent cleanup first, so matching, enrichment and
+	// storage all see canonical values.
+	r.norm.apply(incoming)
+	// Canonicalize hardware identity up front so matching and
+	// storage see one form regardless of how the source formats it.
+	incoming.MACs = model.NormalizeMACs(incoming.MACs)
+	incoming.SerialNumber = strings.TrimSpace(incoming.SerialNumber)
+	incoming.HardwareUUID = model.NormalizeHardwareUUID(incoming.HardwareUUID)
+	// Enrich before matching so derived fields (e.g. a resolved
+	// FQDN) also feed the fuzzy matcher.
+	for _, e := range r.enrichers {
+		e.Enrich(ctx, incoming)
+	}
+	// Schema violations are logged, not fatal: a collector sending
+	// a malformed declared attribute is a bug to fix at the source,
+	// but dropping the whole asset over it would hide the asset.
+	if problems := model.ValidateAttributes(incoming.AssetType, incoming.Attributes); len(problems) > 0 {
+		r.log.Warn("reconcile: attribute schema violations", "source", source,
+			"external_id", incoming.ExternalID, "asset_type", incoming.AssetType,
+			"problems", strings.Join(problems, "; "))
+	}
+	id, outcome, err := r.reconcileAsset(ctx, incoming, cache)
+	if err != nil {
+		r.log.Error("reconcile: asset failed", "source", source, "external_id", incoming.ExternalID, "error", err)
+		mu.Lock()
+		batch.Errors++
+		mu.Unlock()
+		return
+	}
+	mu.Lock()
+	resolved[incoming.ExternalID] = id
+	switch outcome {
+	case outcomeCreated:
+		batch.Created++
+	case outcomeUpdated:
+		batch.Updated++
+	default:
+		batch.Unchanged++
+	}
+	mu.Unlock()
+}
+
+type outcome int
+
+const (
+	outcomeUnchanged outcome = iota
+	outcomeCreated
+	outcomeUpdated
+)
+
+func (r *Reconciler) reconcileAsset(ctx context.Context, incoming *model.Asset, cache *batchCache) (int64, outcome, error) {
+	existing, matched, err := r.matchAsset(ctx, incoming, cache)
+	if err != nil {
+		return 0, outcomeUnchanged, err
+	}
+	if existing == nil {
+		id, out, err := r.createAsset(ctx, incoming)
+		if err == nil {
+			r.persistSoftware(ctx, id, incoming)
+			r.persistVulnerabilities(ctx, id, incoming)
+		}
+		return id, out, err
+	}
+	if matched.Confidence() < 1 && existing.Source != incoming.Source {
+		// Make the inferred cross-source identity durable so future
+		// runs resolve it through the alias table.
+		if err := r.store.CreateAlias(ctx, incoming.Source, incoming.ExternalID, existing.ID); err != nil {
+			r.log.Warn("reconcile: record alias failed", "source", incoming.Source,
+				"external_id", incoming.ExternalID, "asset_id", existing.ID, "error", err)
+		}
+		r.recordConflict(ctx, incoming, &existing.ID, model.ConflictFuzzyMatch)
+	}
+	id, out, err := r.updateAsset(ctx, existing, incoming)
+	if err == nil {
+		r.persistSoftware(ctx, id, incoming)
+		r.persistVulnerabilities(ctx, id, incoming)
+	}
+	return id, out, err
+}
+
+// persistSoftware rewrites the source's package inventory for an asset
+// when the run reported one. A nil slice means the collector doesn't
+// track software and the stored inventory is left alone; failures are
+// logged but never fail the asset.
+func (r *Reconciler) persistSoftware(ctx context.Context, assetID int64, incoming *model.Asset) {
+	if incoming.Software == nil {
+		return
+	}
+	if err := r.store.ReplaceAssetSoftware(ctx, assetID, incoming.Source, incoming.Software); err != nil {
+		r.log.Warn("reconcile: persist software failed", "asset_id", assetID,
+			"source", incoming.Source, "error", err)
+	}
+}
+
+// persistVulnerabilities applies the source's reported findings, with
+// the same nil-means-not-reported convention as persistSoftware.
+func (r *Reconciler) persistVulnerabilities(ctx context.Context, assetID int64, incoming *model.Asset) {
+	if incoming.Vulnerabilities == nil {
+		return
+	}
+	if err := r.store.ReplaceAssetVulnerabilities(ctx, assetID, incoming.Source, incoming.Vulnerabilities); err != nil {
+		r.log.Warn("reconcile: persist vulnerabilities failed", "asset_id", assetID,
+			"source", incoming.Source, "error", err)
+	}
+}
+
+// matchAsset walks the matcher chain, strongest signal first, and
+// returns the first acceptable match along with the strategy that
+// produced it. Several candidates from one strategy is ambiguous — it
+// is recorded and the next strategy gets a try. A single candidate
+// below the confidence threshold is recorded as a merge candidate and
+// ends the search (any later strategy would be weaker still), so the
+// incoming asset is created separately and left for human review.
+func (r *Reconciler) matchAsset(ctx context.Context, incoming *model.Asset, cache *batchCache) (*model.Asset, Matcher, error) {
+	if cache != nil {
+		if a := cache.assets[incoming.ExternalID]; a != nil {
+			return a, externalIDMatcher{}, nil
+		}
+		if id, ok := cache.aliases[incoming.ExternalID]; ok {
+			a, err := r.store.GetAssetByID(ctx, id)
+			if err != nil && err != store.ErrNotFound {
+				return nil, nil, fmt.Errorf("alias match: %w", err)
+			}
+			if a != nil {
+				return a, aliasMatcher{}, nil
+			}
+		}
+	}
+	for _, m := range r.matchers {
+		// The prefetch answered both exact strategies above — hit or
+		// definitive miss — so only the fuzzy ones remain.
+		if cache != nil && m.Confidence() == 1 {
+			continue
+		}
+		candidates, err := m.Match(ctx, r.store, incoming)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s match: %w", m.Name(), err)
+		}
+		switch {
+		case len(candidates) == 0:
+			continue
+		case len(candidates) > 1:
+			r.log.Warn("reconcile: ambiguous match", "matcher", m.Name(),
+				"source", incoming.Source, "external_id", incoming.ExternalID, "candidates", len(candidates))
+			r.recordConflict(ctx, incoming, nil, model.ConflictAmbiguousMatch)
+			continue
+		case m.Confidence() < r.cfg.MatchThreshold:
+			r.log.Info("reconcile: match below threshold, recording merge candidate",
+				"matcher", m.Name(), "confidence", m.Confidence(), "threshold", r.cfg.MatchThreshold,
+				"source", incoming.Source, "external_id", incoming.ExternalID, "asset_id", candidates[0].ID)
+			r.recordConflict(ctx, incoming, &candidates[0].ID, model.ConflictMergeCandidate)
+			return nil, nil, nil
+		}
+		return candidates[0], m, nil
+	}
+	return nil, nil, nil
+}
+
+// recordConflict writes a non-obvious matching decision to the conflict
+// log so operators can audit and correct it. Failures to record are
+// logged but never fail the asset.
+func (r *Reconciler) recordConflict(ctx context.Context, incoming *model.Asset, assetID *int64, reason string) {
+	c := &model.ReconcileConflict{
+		Source:     incoming.Source,
+		ExternalID: incoming.ExternalID,
+		AssetID:    assetID,
+		Reason:     reason,
+		Incoming:   incoming,
+	}
+	if err := r.store.CreateReconcileConflict(ctx, c); err != nil {
+		r.log.Warn("reconcile: record conflict failed", "source", incoming.Source,
+			"external_id", incoming.ExternalID, "reason", reason, "error", err)
+	}
+}
+
+// observedAt returns the sighting timestamp for an incoming asset: the
+// source-provided LastSeen when the collector set one (e.g. Zabbix's
+// last check-in), otherwise now. Future timestamps are clamped so a
+// source with a skewed clock can't mark assets as seen ahead of time.
+func observedAt(incoming *model.Asset) time.Time {
+	now := time.Now().UTC()
+	if incoming.LastSeen.IsZero() || incoming.LastSeen.After(now) {
+		return now
+	}
+	return incoming.LastSeen.UTC()
+}
+
+// applyCollisionPolicy fills the derived display_name when the asset's
+// name collides with another asset, per the configured policy. The raw
+// name is left untouched so search and matching still see source data.
+func (r *Reconciler) applyCollisionPolicy(ctx context.Context, a *model.Asset) {
+	if r.cfg.NameCollisionPolicy == "" || r.cfg.NameCollisionPolicy == "none" || a.Name == "" {
+		return
+	}
+	n, err := r.store.CountAssetsByName(ctx, a.Name, a.ID)
+	if err != nil {
+		r.log.Warn("reconcile: name collision check failed", "name", a.Name, "error", err)
+		return
+	}
+	if n == 0 {
+		a.DisplayName = ""
+		return
+	}
+	switch r.cfg.NameCollisionPolicy {
+	case "append-source":
+		a.DisplayName = fmt.Sprintf("%s (%s)", a.Name, a.Source)
+	case "append-ip":
+		if len(a.IPs) > 0 {
+			a.DisplayName = fmt.Sprintf("%s (%s)", a.Name, a.IPs[0])
+		} else {
+			a.DisplayName = fmt.Sprintf("%s (%s)", a.Name, a.Source)
+		}
+	}
+}
+
+func (r *Reconciler) createAsset(ctx context.Context, incoming *model.Asset) (int64, outcome, error) {
+	incoming.LastSeen = observedAt(incoming)
+	incoming.FirstSeen = incoming.LastSeen
+	incoming.SeenCount = 1
+	r.applyCollisionPolicy(ctx, incoming)
+	if err := r.store.CreateAsset(ctx, incoming); err != nil {
+		return 0, outcomeUnchanged, err
+	}
+	r.recordSource(ctx, incoming.ID, incoming, incoming.LastSeen)
+	if err := r.graph.UpsertAsset(ctx, incoming); err != nil {
+		r.log.Warn("reconcile: graph upsert failed", "asset_id", incoming.ID, "error", err)
+	}
+	r.emitEvent(ctx, &model.ChangeEvent{
+		AssetID: incoming.ID,
+		Action:  model.ActionAssetCreated,
+		Source:  incoming.Source,
+		After:   assetSummary(incoming),
+	})
+	return incoming.ID, outcomeCreated, nil
+}
+
+func (r *Reconciler) updateAsset(ctx context.Context, existing, incoming *model.Asset) (int64, outcome, error) {
+	r.recordSource(ctx, existing.ID, incoming, observedAt(incoming))
+	// Replace the incoming overlay with the recomputed merge of every
+	// source's stored payload, so one source reporting a key another
+	// source owns can't clobber it for good — the other source's value
+	// comes back as soon as its payload ranks higher again.
+	if merged, ok := r.mergedAttributes(ctx, existing.ID); ok {
+		incoming.Attributes = merged
+	}
+	before, after := diffAssets(existing, incoming, r.rules)
+	merge(existing, incoming, r.rules)
+	// Never move last_seen backwards: a delayed source report must not
+	// make a recently-seen asset look stale.
+	if seen := observedAt(incoming); seen.After(existing.LastSeen) {
+		existing.LastSeen = seen
+	}
+	existing.SeenCount++
+	r.applyCollisionPolicy(ctx, existing)
+	if err := r.store.UpdateAsset(ctx, existing); err != nil {
+		return 0, outcomeUnchanged, err
+	}
+	if len(after) == 0 {
+		return existing.ID, outcomeUnchanged, nil
+	}
+	if err := r.graph.UpsertAsset(ctx, existing); err != nil {
+		r.log.Warn("reconcile: graph upsert failed", "asset_id", existing.ID, "error", err)
+	}
+	r.emitEvent(ctx, &model.ChangeEvent{
+		AssetID: existing.ID,
+		Action:  model.ActionAssetUpdated,
+		Source:  incoming.Source,
+		Before:  before,
+		After:   after,
+	})
+	return existing.ID, outcomeUpdated, nil
+}
+
+// recordSource upserts the source's own record of the asset — its
+// external ID there and its raw attribute payload. Failures are logged
+// but never fail the asset: the merged view still lands.
+func (r *Reconciler) recordSource(ctx context.Context, assetID int64, incoming *model.Asset, seen time.Time) {
+	rec := &model.AssetSource{
+		AssetID:    assetID,
+		Source:     incoming.Source,
+		ExternalID: incoming.ExternalID,
+		Attributes: incoming.Attributes,
+		LastSeen:   seen,
+	}
+	if err := r.store.UpsertAssetSource(ctx, rec); err != nil {
+		r.log.Warn("reconcile: record source failed", "asset_id", assetID,
+			"source", incoming.Source, "error", err)
+	}
+}
+
+// mergedAttributes overlays every source's stored payload, oldest
+// sighting first, so the most recently seen source wins each key —
+// except keys under an authority rule, which are taken from the
+// highest-precedence listed source that reports them (and from nobody
+// else). The second return is false when the records can't be read;
+// the caller falls back to the plain incoming overlay.
+func (r *Reconciler) mergedAttributes(ctx context.Context, assetID int64) (map[string]any, bool) {
+	sources, err := r.store.ListAssetSources(ctx, assetID)
+	if err != nil {
+		r.log.Warn("reconcile: list asset sources failed", "asset_id", assetID, "error", err)
+		return nil, false
+	}
+	if len(sources) == 0 {
+		return nil, false
+	}
+	merged := map[string]any{}
+	bySource := make(map[string]map[string]any, len(sources))
+	for _, src := range sources {
+		bySource[src.Source] = src.Attributes
+		for k, v := range src.Attributes {
+			if _, ruled := r.rules[k]; ruled {
+				continue
+			}
+			merged[k] = v
+		}
+	}
+	r.rules.mergeRuled(merged, bySource)
+	return merged, true
+}
+
+// merge copies the incoming collector view onto the existing asset,
+// honoring the field authority rules. Attribute maps are merged
+// key-wise so one source doesn't wipe out another source's attributes
+// wholesale.
+func merge(existing, incoming *model.Asset, rules authorityRules) {
+	if incoming.Name != "" && rules.allows("name", incoming.Source) {
+		existing.Name = incoming.Name
+	}
+	if incoming.FQDN != "" && rules.allows("fqdn", incoming.Source) {
+		existing.FQDN = incoming.FQDN
+	}
+	if len(incoming.IPs) > 0 {
+		existing.IPs = unionStrings(existing.IPs, incoming.IPs)
+	}
+	if len(incoming.MACs) > 0 {
+		existing.MACs = unionStrings(existing.MACs, incoming.MACs)
+	}
+	if incoming.SerialNumber != "" {
+		existing.SerialNumber = incoming.SerialNumber
+	}
+	if incoming.HardwareUUID != "" {
+		existing.HardwareUUID = incoming.HardwareUUID
+	}
+	if incoming.AssetType != "" && rules.allows("asset_type", incoming.Source) {
+		existing.AssetType = incoming.AssetType
+	}
+	if incoming.Site != "" && rules.allows("site", incoming.Source) {
+		existing.Site = incoming.Site
+	}
+	if len(incoming.Attributes) > 0 {
+		if existing.Attributes == nil {
+			existing.Attributes = map[string]any{}
+		}
+		for k, v := range incoming.Attributes {
+			existing.Attributes[k] = v
+		}
+	}
+}
+
+// diffAssets returns the before/after values of fields that would
+// change if incoming were merged onto existing, mirroring merge's
+// authority checks so the change log matches what was applied.
+func diffAssets(existing, incoming *model.Asset, rules authorityRules) (before, after map[string]any) {
+	before = map[string]any{}
+	after = map[string]any{}
+	if incoming.Name != "" && incoming.Name != existing.Name && rules.allows("name", incoming.Source) {
+		before["name"], after["name"] = existing.Name, incoming.Name
+	}
+	if incoming.FQDN != "" && incoming.FQDN != existing.FQDN && rules.allows("fqdn", incoming.Source) {
+		before["fqdn"], after["fqdn"] = existing.FQDN, incoming.FQDN
+	}
+	if len(incoming.IPs) > 0 {
+		merged := unionStrings(existing.IPs, incoming.IPs)
+		if !reflect.DeepEqual(merged, existing.IPs) {
+			before["ips"], after["ips"] = existing.IPs, merged
+		}
+	}
+	if len(incoming.MACs) > 0 {
+		merged := unionStrings(existing.MACs, incoming.MACs)
+		if !reflect.DeepEqual(merged, existing.MACs) {
+			before["macs"], after["macs"] = existing.MACs, merged
+		}
+	}
+	if incoming.SerialNumber != "" && incoming.SerialNumber != existing.SerialNumber {
+		before["serial_number"], after["serial_number"] = existing.SerialNumber, incoming.SerialNumber
+	}
+	if incoming.HardwareUUID != "" && incoming.HardwareUUID != existing.HardwareUUID {
+		before["hardware_uuid"], after["hardware_uuid"] = existing.HardwareUUID, incoming.HardwareUUID
+	}
+	if incoming.AssetType != "" && incoming.AssetType != existing.AssetType && rules.allows("asset_type", incoming.Source) {
+		before["asset_type"], after["asset_type"] = existing.AssetType, incoming.AssetType
+	}
+	if incoming.Site != "" && incoming.Site != existing.Site && rules.allows("site", incoming.Source) {
+		before["site"], after["site"] = existing.Site, incoming.Site
+	}
+	for k, v := range incoming.Attributes {
+		if old, ok := existing.Attributes[k]; !ok || !reflect.DeepEqual(old, v) {
+			if ok {
+				before["attributes."+k] = old
+			}
+			after["attributes."+k] = v
+		}
+	}
+	if len(after) == 0 {
+		return nil, nil
+	}
+	return before, after
+}
+
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, lists := range [][]string{a, b} {
+		for _, s := range lists {
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func assetSummary(a *model.Asset) map[string]any {
+	return map[string]any{
+		"name":       a.Name,
+		"fqdn":       a.FQDN,
+		"ips":        a.IPs,
+		"asset_type": a.AssetType,
+		"status":     a.Status,
+	}
+}
+
+func (r *Reconciler) emitEvent(ctx context.Context, e *model.ChangeEvent) {
+	if err := r.store.CreateChangeEvent(ctx, e); err != nil {
+		r.log.Error("reconcile: record change event", "asset_id", e.AssetID, "action", e.Action, "error", err)
+		return
+	}
+	for _, s := range r.sinks {
+		s.Publish(e)
+	}
+}
+
+func (r *Reconciler) applyRelationship(ctx context.Context, source string, rel model.Relationship, resolved map[string]int64, applied map[graph.RelationshipRef]bool) error {
+	if !model.ValidRelationshipType(rel.Type) {
+		return fmt.Errorf("invalid relationship type %q", rel.Type)
+	}
+	fromID, err := r.resolveEndpoint(ctx, source, rel.FromExternalID, resolved)
+	if err != nil {
+		return err
+	}
+	toID, err := r.resolveEndpoint(ctx, source, rel.ToExternalID, resolved)
+	if err != nil {
+		return err
+	}
+	if err := r.graph.UpsertRelationship(ctx, fromID, toID, rel.Type, source, rel.Properties); err != nil {
+		return err
+	}
+	applied[graph.RelationshipRef{FromID: fromID, ToID: toID, Type: string(rel.Type)}] = true
+	return nil
+}
+
+// pruneRelationships deletes the source's graph edges that the run no
+// longer reported and records the disappearance in the change log,
+// keyed on the edge's origin asset.
+func (r *Reconciler) pruneRelationships(ctx context.Context, source string, applied map[graph.RelationshipRef]bool) {
+	refs, err := r.graph.ListRelationshipsBySource(ctx, source)
+	if err != nil {
+		r.log.Warn("reconcile: list relationships failed", "source", source, "error", err)
+		return
+	}
+	for _, ref := range refs {
+		if applied[ref] {
+			continue
+		}
+		if err := r.graph.DeleteRelationship(ctx, ref.FromID, ref.ToID, model.RelationshipType(ref.Type), source); err != nil {
+			r.log.Warn("reconcile: prune relationship failed", "source", source,
+				"from", ref.FromID, "to", ref.ToID, "type", ref.Type, "error", err)
+			continue
+		}
+		r.emitEvent(ctx, &model.ChangeEvent{
+			AssetID: ref.FromID,
+			Action:  model.ActionRelationshipChanged,
+			Source:  source,
+			Before:  map[string]any{"to_asset_id": ref.ToID, "type": ref.Type},
+		})
+	}
+}
+
+func (r *Reconciler) resolveEndpoint(ctx context.Context, source, externalID string, resolved map[string]int64) (int64, error) {
+	if id, ok := resolved[externalID]; ok {
+		return id, nil
+	}
+	a, err := r.store.GetAssetByExternalID(ctx, source, externalID)
+	if err != nil {
+		return 0, fmt.Errorf("resolve %q: %w", externalID, err)
+	}
+	resolved[externalID] = a.ID
+	return a.ID, nil
+}
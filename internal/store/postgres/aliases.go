@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// CreateAlias records that a source knows the given asset under the
+// given external ID. Re-recording an existing alias is a no-op; an
+// alias is never silently repointed at a different asset.
+func (s *Store) CreateAlias(ctx context.Context, source, externalID string, assetID int64) error {
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO asset_aliases (source, external_id, asset_id) VALUES ($1, $2, $3)
+		 ON CONFLICT (source, external_id) DO NOTHING`,
+		source, externalID, assetID)
+	return err
+}
+
+// GetAssetIDByAlias resolves a (source, external_id) pair to the
+// canonical asset it is an alias for.
+func (s *Store) GetAssetIDByAlias(ctx context.Context, source, externalID string) (int64, error) {
+	var id int64
+	err := s.pool.QueryRow(ctx,
+		`SELECT asset_id FROM asset_aliases WHERE source = $1 AND external_id = $2`,
+		source, externalID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return 0, store.ErrNotFound
+	}
+	return id, err
+}
+
+// GetAssetIDsByAliases bulk-resolves a source's external IDs through
+// the alias table in one round trip, keyed by external ID.
+func (s *Store) GetAssetIDsByAliases(ctx context.Context, source string, externalIDs []string) (map[string]int64, error) {
+	if len(externalIDs) == 0 {
+		return map[string]int64{}, nil
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT external_id, asset_id FROM asset_aliases
+		 WHERE source = $1 AND external_id = ANY($2)`, source, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]int64)
+	for rows.Next() {
+		var extID string
+		var id int64
+		if err := rows.Scan(&extID, &id); err != nil {
+			return nil, err
+		}
+		out[extID] = id
+	}
+	return out, rows.Err()
+}
+
+// ListAliasesByAssetID returns every alias pointing at the asset.
+func (s *Store) ListAliasesByAssetID(ctx context.Context, assetID int64) ([]model.Alias, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT source, external_id, asset_id, created_at FROM asset_aliases
+		 WHERE asset_id = $1 ORDER BY source, external_id`, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.Alias
+	for rows.Next() {
+		var a model.Alias
+		if err := rows.Scan(&a.Source, &a.ExternalID, &a.AssetID, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// CreateReconcileConflict appends one entry to the conflict log.
+func (s *Store) CreateReconcileConflict(ctx context.Context, c *model.ReconcileConflict) error {
+	incoming, err := json.Marshal(c.Incoming)
+	if err != nil {
+		return err
+	}
+	row := s.pool.QueryRow(ctx,
+		`INSERT INTO reconcile_conflicts (source, external_id, asset_id, reason, incoming)
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		c.Source, c.ExternalID, c.AssetID, c.Reason, incoming)
+	return row.Scan(&c.ID, &c.CreatedAt)
+}
+
+// ListReconcileConflicts returns a page of the conflict log, newest
+// first, plus the total count.
+func (s *Store) ListReconcileConflicts(ctx context.Context, limit, offset int) ([]*model.ReconcileConflict, int64, error) {
+	var total int64
+	if err := s.pool.QueryRow(ctx, `SELECT count(*) FROM reconcile_conflicts`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, source, external_id, asset_id, reason, incoming, created_at
+		 FROM reconcile_conflicts ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`,
+		limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []*model.ReconcileConflict
+	for rows.Next() {
+		c := &model.ReconcileConflict{}
+		var incoming []byte
+		if err := rows.Scan(&c.ID, &c.Source, &c.ExternalID, &c.AssetID, &c.Reason, &incoming, &c.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		if len(incoming) > 0 {
+			json.Unmarshal(incoming, &c.Incoming)
+		}
+		out = append(out, c)
+	}
+	return out, total, rows.Err()
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// The asset_counts summary table holds one row per (source, asset_type,
+// status) bucket so the dashboard and facet queries never scan the
+// assets table. It is maintained incrementally by the asset write
+// paths: sightings only ever grow the bucket and push max_last_seen
+// forward, so those are pure upserts; removals from a bucket recompute
+// that bucket's extremes with a bucket-scoped aggregate, which keeps the
+// table exact without ever scanning more than one bucket.
+
+// countsAdd records one asset entering a bucket.
+func countsAdd(ctx context.Context, q querier, a *model.Asset) error {
+	_, err := q.Exec(ctx,
+		`INSERT INTO asset_counts (source, asset_type, status, count, min_last_seen, max_last_seen)
+		 VALUES ($1, $2, $3, 1, $4, $4)
+		 ON CONFLICT (source, asset_type, status) DO UPDATE SET
+		   count = asset_counts.count + 1,
+		   min_last_seen = LEAST(asset_counts.min_last_seen, EXCLUDED.min_last_seen),
+		   max_last_seen = GREATEST(asset_counts.max_last_seen, EXCLUDED.max_last_seen)`,
+		a.Source, a.AssetType, a.Status, a.LastSeen)
+	return err
+}
+
+// countsTouch refreshes a bucket's last_seen extremes for an asset that
+// stayed in the same bucket.
+func countsTouch(ctx context.Context, q querier, source, assetType string, status model.AssetStatus, lastSeen time.Time) error {
+	_, err := q.Exec(ctx,
+		`UPDATE asset_counts SET
+		   min_last_seen = LEAST(min_last_seen, $4),
+		   max_last_seen = GREATEST(max_last_seen, $4)
+		 WHERE source = $1 AND asset_type = $2 AND status = $3`,
+		source, assetType, status, lastSeen)
+	return err
+}
+
+// countsRemove records one asset leaving a bucket and recomputes the
+// bucket's extremes.
+func countsRemove(ctx context.Context, q querier, source, assetType string, status model.AssetStatus) error {
+	_, err := q.Exec(ctx,
+		`UPDATE asset_counts SET
+		   count = count - 1,
+		   (min_last_seen, max_last_seen) = (
+		     SELECT min(last_seen), max(last_seen) FROM assets
+		     WHERE source = $1 AND asset_type = $2 AND status = $3)
+		 WHERE source = $1 AND asset_type = $2 AND status = $3`,
+		source, assetType, status)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx,
+		`DELETE FROM asset_counts
+		 WHERE source = $1 AND asset_type = $2 AND status = $3 AND count <= 0`,
+		source, assetType, status)
+	return err
+}
+
+// ListAssetCounts returns the summary rows backing the facets endpoint.
+func (s *Store) ListAssetCounts(ctx context.Context) ([]store.AssetCount, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT source, asset_type, status, count, min_last_seen, max_last_seen
+		 FROM asset_counts ORDER BY source, asset_type, status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []store.AssetCount
+	for rows.Next() {
+		var c store.AssetCount
+		if err := rows.Scan(&c.Source, &c.AssetType, &c.Status, &c.Count, &c.MinLastSeen, &c.MaxLastSeen); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
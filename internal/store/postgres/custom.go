@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// CreateCustomField inserts a field definition and fills in its
+// generated fields. The name is unique; a duplicate surfaces as the
+// constraint violation from the database.
+func (s *Store) CreateCustomField(ctx context.Context, f *model.CustomField) error {
+	row := s.pool.QueryRow(ctx,
+		`INSERT INTO custom_fields (name, field_type, enum_values, description)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`,
+		f.Name, f.Type, f.EnumValues, f.Description)
+	return row.Scan(&f.ID, &f.CreatedAt)
+}
+
+// ListCustomFields returns all field definitions, ordered by name.
+func (s *Store) ListCustomFields(ctx context.Context) ([]*model.CustomField, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, name, field_type, enum_values, description, created_at FROM custom_fields ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*model.CustomField
+	for rows.Next() {
+		f := &model.CustomField{}
+		if err := rows.Scan(&f.ID, &f.Name, &f.Type, &f.EnumValues, &f.Description, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// DeleteCustomField removes a field definition. Values already stored
+// on assets under its name are deliberately left alone: they stop being
+// validated and filterable but remain visible, which beats silently
+// rewriting every asset row.
+func (s *Store) DeleteCustomField(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM custom_fields WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateAssetCustom applies set and remove to an asset's custom field
+// values under a row lock and returns the values before and after.
+// Validation against the field definitions is the caller's job.
+func (s *Store) UpdateAssetCustom(ctx context.Context, id int64, set map[string]any, remove []string) (before, after map[string]any, err error) {
+	err = s.inTx(ctx, func(tx pgx.Tx) error {
+		var raw []byte
+		if err := tx.QueryRow(ctx, `SELECT custom FROM assets WHERE id = $1 FOR UPDATE`, id).Scan(&raw); err != nil {
+			if err == pgx.ErrNoRows {
+				return store.ErrNotFound
+			}
+			return err
+		}
+		before = map[string]any{}
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &before); err != nil {
+				return fmt.Errorf("decode custom fields for asset %d: %w", id, err)
+			}
+		}
+		after = make(map[string]any, len(before)+len(set))
+		for k, v := range before {
+			after[k] = v
+		}
+		for k, v := range set {
+			after[k] = v
+		}
+		for _, k := range remove {
+			delete(after, k)
+		}
+		out, err := json.Marshal(after)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `UPDATE assets SET custom = $2, updated_at = now() WHERE id = $1`, id, out)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
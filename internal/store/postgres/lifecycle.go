@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// TransitionAssets moves the source's assets whose last_seen is before
+// cutoff from any of the given statuses to the target status, returning
+// one record per moved asset so the caller can emit change events and
+// update the graph. The count summaries move buckets along with the
+// assets. Assets covered by an active maintenance window are skipped:
+// a host that is quiet because someone is patching it is not stale.
+func (s *Store) TransitionAssets(ctx context.Context, source string, cutoff time.Time, from []model.AssetStatus, to model.AssetStatus) ([]store.LifecycleTransition, error) {
+	fromStrs := make([]string, len(from))
+	for i, st := range from {
+		fromStrs[i] = string(st)
+	}
+	var out []store.LifecycleTransition
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		out = out[:0]
+		rows, err := tx.Query(ctx,
+			`UPDATE assets AS new SET status = $1, updated_at = now()
+			 FROM assets AS old
+			 WHERE new.id = old.id AND new.source = $2 AND new.last_seen < $3 AND new.status = ANY($4) AND new.deleted_at IS NULL
+			   AND NOT `+fmt.Sprintf(maintenanceCoverage, "new")+`
+			 RETURNING new.id, new.name, new.asset_type, new.last_seen, old.status`,
+			to, source, cutoff, fromStrs)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var t store.LifecycleTransition
+			if err := rows.Scan(&t.ID, &t.Name, &t.AssetType, &t.LastSeen, &t.From); err != nil {
+				rows.Close()
+				return err
+			}
+			out = append(out, t)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, t := range out {
+			if err := countsRemove(ctx, tx, source, t.AssetType, t.From); err != nil {
+				return err
+			}
+			moved := &model.Asset{Source: source, AssetType: t.AssetType, Status: to, LastSeen: t.LastSeen}
+			if err := countsAdd(ctx, tx, moved); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+const windowColumns = `id, name, starts_at, ends_at, asset_ids, selector, created_at, updated_at`
+
+// maintenanceCoverage is the SQL condition for "the asset aliased a is
+// covered by an active maintenance window right now". It is shared with
+// the lifecycle sweep so both suppression paths agree on what covered
+// means.
+const maintenanceCoverage = `EXISTS (
+	SELECT 1 FROM maintenance_windows w
+	WHERE now() >= w.starts_at AND now() < w.ends_at
+	  AND (%[1]s.id = ANY(w.asset_ids) OR (w.selector <> '{}'::jsonb AND %[1]s.tags @> w.selector))
+)`
+
+func scanWindow(row pgx.Row) (*model.MaintenanceWindow, error) {
+	w := &model.MaintenanceWindow{}
+	var selector []byte
+	err := row.Scan(&w.ID, &w.Name, &w.StartsAt, &w.EndsAt, &w.AssetIDs, &selector, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+	if len(selector) > 0 {
+		if err := json.Unmarshal(selector, &w.Selector); err != nil {
+			return nil, fmt.Errorf("decode selector for window %d: %w", w.ID, err)
+		}
+	}
+	return w, nil
+}
+
+func marshalWindowSelector(w *model.MaintenanceWindow) []byte {
+	if len(w.Selector) == 0 {
+		return []byte("{}")
+	}
+	// Marshaling can't fail for a map[string]string.
+	out, _ := json.Marshal(w.Selector)
+	return out
+}
+
+// CreateMaintenanceWindow inserts a window and fills in its generated
+// fields.
+func (s *Store) CreateMaintenanceWindow(ctx context.Context, w *model.MaintenanceWindow) error {
+	row := s.pool.QueryRow(ctx,
+		`INSERT INTO maintenance_windows (name, starts_at, ends_at, asset_ids, selector)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, created_at, updated_at`,
+		w.Name, w.StartsAt, w.EndsAt, w.AssetIDs, marshalWindowSelector(w))
+	return row.Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+// UpdateMaintenanceWindow rewrites the mutable fields of a window.
+func (s *Store) UpdateMaintenanceWindow(ctx context.Context, w *model.MaintenanceWindow) error {
+	row := s.pool.QueryRow(ctx,
+		`UPDATE maintenance_windows SET name = $2, starts_at = $3, ends_at = $4, asset_ids = $5, selector = $6, updated_at = now()
+		 WHERE id = $1
+		 RETURNING updated_at`,
+		w.ID, w.Name, w.StartsAt, w.EndsAt, w.AssetIDs, marshalWindowSelector(w))
+	if err := row.Scan(&w.UpdatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return store.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteMaintenanceWindow removes a window.
+func (s *Store) DeleteMaintenanceWindow(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// GetMaintenanceWindow returns one window.
+func (s *Store) GetMaintenanceWindow(ctx context.Context, id int64) (*model.MaintenanceWindow, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+windowColumns+` FROM maintenance_windows WHERE id = $1`, id)
+	return scanWindow(row)
+}
+
+// ListMaintenanceWindows returns all windows, upcoming and current
+// first. Expired windows stay until someone deletes them; they double
+// as a record of past maintenance.
+func (s *Store) ListMaintenanceWindows(ctx context.Context) ([]*model.MaintenanceWindow, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+windowColumns+` FROM maintenance_windows ORDER BY ends_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*model.MaintenanceWindow
+	for rows.Next() {
+		w, err := scanWindow(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// AssetInMaintenance reports whether the asset is covered by a window
+// active right now.
+func (s *Store) AssetInMaintenance(ctx context.Context, assetID int64) (bool, error) {
+	var covered bool
+	err := s.pool.QueryRow(ctx,
+		`SELECT `+fmt.Sprintf(maintenanceCoverage, "a")+` FROM assets a WHERE a.id = $1`,
+		assetID).Scan(&covered)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	return covered, err
+}
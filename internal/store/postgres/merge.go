@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// ListDuplicateAssets returns groups of assets from different sources
+// that share an IP or an FQDN. These are the pairs automatic matching
+// declined to merge (ambiguity, threshold); the limit caps the number
+// of groups, not assets.
+func (s *Store) ListDuplicateAssets(ctx context.Context, limit int) ([]store.DuplicateGroup, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	ipKeys, err := s.duplicateKeys(ctx,
+		`SELECT ip FROM (SELECT DISTINCT unnest(ips) AS ip, source FROM assets WHERE deleted_at IS NULL) t
+		 GROUP BY ip HAVING count(*) > 1 ORDER BY ip LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	fqdnKeys, err := s.duplicateKeys(ctx,
+		`SELECT fqdn FROM assets WHERE fqdn <> '' AND deleted_at IS NULL
+		 GROUP BY fqdn HAVING count(DISTINCT source) > 1 ORDER BY fqdn LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	var groups []store.DuplicateGroup
+	for _, key := range ipKeys {
+		assets, err := s.FindAssetsByIPOrFQDN(ctx, []string{key}, "")
+		if err != nil {
+			return nil, err
+		}
+		if g := crossSourceGroup(key, assets); g != nil {
+			groups = append(groups, *g)
+		}
+		if len(groups) >= limit {
+			return groups, nil
+		}
+	}
+	for _, key := range fqdnKeys {
+		assets, err := s.FindAssetsByIPOrFQDN(ctx, nil, key)
+		if err != nil {
+			return nil, err
+		}
+		if g := crossSourceGroup(key, assets); g != nil {
+			groups = append(groups, *g)
+		}
+		if len(groups) >= limit {
+			break
+		}
+	}
+	return groups, nil
+}
+
+func (s *Store) duplicateKeys(ctx context.Context, query string, limit int) ([]string, error) {
+	rows, err := s.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// crossSourceGroup keeps a key's assets only when at least two sources
+// are represented; a key shared within one source is two real assets.
+func crossSourceGroup(key string, assets []*model.Asset) *store.DuplicateGroup {
+	sources := map[string]bool{}
+	for _, a := range assets {
+		sources[a.Source] = true
+	}
+	if len(sources) < 2 {
+		return nil
+	}
+	return &store.DuplicateGroup{Key: key, Assets: assets}
+}
+
+// MergeAssets implements store.Store: it re-points the src asset's
+// aliases and change history at dst, records src's own identity as an
+// alias of dst (so its source re-resolves there next run) and deletes
+// the src row, adjusting the count summaries.
+func (s *Store) MergeAssets(ctx context.Context, dstID, srcID int64) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var source, assetType, externalID string
+		var status model.AssetStatus
+		err := tx.QueryRow(ctx,
+			`SELECT source, external_id, asset_type, status FROM assets WHERE id = $1 FOR UPDATE`,
+			srcID).Scan(&source, &externalID, &assetType, &status)
+		if err == pgx.ErrNoRows {
+			return store.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE asset_aliases SET asset_id = $1 WHERE asset_id = $2`, dstID, srcID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO asset_aliases (source, external_id, asset_id) VALUES ($1, $2, $3)
+			 ON CONFLICT (source, external_id) DO NOTHING`,
+			source, externalID, dstID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE change_events SET asset_id = $1 WHERE asset_id = $2`, dstID, srcID); err != nil {
+			return err
+		}
+		// Move per-source records over unless dst already has one for
+		// that source; leftovers fall away with the src row's cascade.
+		if _, err := tx.Exec(ctx,
+			`UPDATE asset_sources SET asset_id = $1 WHERE asset_id = $2
+			 AND source NOT IN (SELECT source FROM asset_sources WHERE asset_id = $1)`,
+			dstID, srcID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM assets WHERE id = $1`, srcID); err != nil {
+			return err
+		}
+		return countsRemove(ctx, tx, source, assetType, status)
+	})
+}
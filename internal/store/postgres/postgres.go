@@ -0,0 +1,860 @@
+// Package postgres implements the store.Store interface on top of
+// PostgreSQL using pgx.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/query"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// Store is the PostgreSQL-backed asset store.
+type Store struct {
+	pool *pgxpool.Pool
+}
+
+// querier is the subset of pgx satisfied by both the pool and a
+// transaction, so helpers can run inside either.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// inTx runs fn inside a transaction, committing on success.
+func (s *Store) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// New connects to PostgreSQL with the given DSN and applies any pending
+// schema migrations. When slowQuery is non-zero, statements taking
+// longer than it are logged at warn level.
+func New(ctx context.Context, dsn string, log *slog.Logger, slowQuery time.Duration) (*Store, error) {
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres dsn: %w", err)
+	}
+	if slowQuery > 0 {
+		poolCfg.ConnConfig.Tracer = &slowQueryTracer{threshold: slowQuery, log: log}
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect postgres: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+	s := &Store{pool: pool}
+	if err := s.migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close releases the connection pool.
+func (s *Store) Close() { s.pool.Close() }
+
+const assetColumns = `id, name, display_name, fqdn, ips, macs, serial_number, hardware_uuid, asset_type, status, source, external_id, attributes, site, tags, notes, seen_count, absent_count, first_seen, last_seen, created_at, updated_at, deleted_at, custom, owner, team`
+
+func scanAsset(row pgx.Row) (*model.Asset, error) {
+	a := &model.Asset{}
+	var attrs, tags, custom []byte
+	err := row.Scan(&a.ID, &a.Name, &a.DisplayName, &a.FQDN, &a.IPs, &a.MACs, &a.SerialNumber, &a.HardwareUUID, &a.AssetType, &a.Status,
+		&a.Source, &a.ExternalID, &attrs, &a.Site, &tags, &a.Notes, &a.SeenCount, &a.AbsentCount,
+		&a.FirstSeen, &a.LastSeen, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt, &custom, &a.Owner, &a.Team)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+	if len(attrs) > 0 {
+		if err := json.Unmarshal(attrs, &a.Attributes); err != nil {
+			return nil, fmt.Errorf("decode attributes for asset %d: %w", a.ID, err)
+		}
+	}
+	if len(tags) > 0 {
+		if err := json.Unmarshal(tags, &a.Tags); err != nil {
+			return nil, fmt.Errorf("decode tags for asset %d: %w", a.ID, err)
+		}
+	}
+	if len(custom) > 0 {
+		if err := json.Unmarshal(custom, &a.Custom); err != nil {
+			return nil, fmt.Errorf("decode custom fields for asset %d: %w", a.ID, err)
+		}
+	}
+	return a, nil
+}
+
+func marshalAttrs(a *model.Asset) ([]byte, error) {
+	if a.Attributes == nil {
+		return []byte("{}"), nil
+	}
+	raw, err := json.Marshal(a.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > store.MaxAttributesBytes {
+		return nil, &store.AttributesTooLargeError{
+			Source:     a.Source,
+			ExternalID: a.ExternalID,
+			Size:       len(raw),
+			Limit:      store.MaxAttributesBytes,
+		}
+	}
+	return raw, nil
+}
+
+// CreateAsset inserts a new asset and fills in its generated fields.
+func (s *Store) CreateAsset(ctx context.Context, a *model.Asset) error {
+	attrs, err := marshalAttrs(a)
+	if err != nil {
+		return err
+	}
+	if a.Status == "" {
+		a.Status = model.StatusDiscovered
+	}
+	if a.LastSeen.IsZero() {
+		a.LastSeen = time.Now().UTC()
+	}
+	if a.FirstSeen.IsZero() {
+		a.FirstSeen = a.LastSeen
+	}
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx,
+			`INSERT INTO assets (name, display_name, fqdn, ips, macs, serial_number, hardware_uuid, asset_type, status, source, external_id, attributes, site, seen_count, first_seen, last_seen)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			 RETURNING id, created_at, updated_at`,
+			a.Name, a.DisplayName, a.FQDN, a.IPs, a.MACs, a.SerialNumber, a.HardwareUUID, a.AssetType, a.Status, a.Source, a.ExternalID, attrs, a.Site, a.SeenCount, a.FirstSeen, a.LastSeen)
+		if err := row.Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return err
+		}
+		return countsAdd(ctx, tx, a)
+	})
+}
+
+// UpdateAsset rewrites the mutable fields of an existing asset. A full
+// update also revives a soft-deleted asset: every caller writes on
+// behalf of something asserting the asset exists.
+func (s *Store) UpdateAsset(ctx context.Context, a *model.Asset) error {
+	attrs, err := marshalAttrs(a)
+	if err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var oldType string
+		var oldStatus model.AssetStatus
+		var oldDeleted *time.Time
+		row := tx.QueryRow(ctx,
+			`UPDATE assets AS new SET name = $2, display_name = $3, fqdn = $4, ips = $5, macs = $6, serial_number = $7, hardware_uuid = $8, asset_type = $9, status = $10,
+			        attributes = $11, site = $12, last_seen = $13, seen_count = $14, absent_count = $15, updated_at = now(), deleted_at = NULL
+			 FROM assets AS old
+			 WHERE new.id = $1 AND old.id = new.id
+			 RETURNING old.asset_type, old.status, old.deleted_at`,
+			a.ID, a.Name, a.DisplayName, a.FQDN, a.IPs, a.MACs, a.SerialNumber, a.HardwareUUID, a.AssetType, a.Status, attrs, a.Site, a.LastSeen, a.SeenCount, a.AbsentCount)
+		if err := row.Scan(&oldType, &oldStatus, &oldDeleted); err != nil {
+			if err == pgx.ErrNoRows {
+				return store.ErrNotFound
+			}
+			return err
+		}
+		a.DeletedAt = nil
+		if oldDeleted != nil {
+			// Revived assets weren't in the count summaries at all, so
+			// this is an add, not a bucket move.
+			return countsAdd(ctx, tx, a)
+		}
+		if oldType == a.AssetType && oldStatus == a.Status {
+			return countsTouch(ctx, tx, a.Source, a.AssetType, a.Status, a.LastSeen)
+		}
+		if err := countsRemove(ctx, tx, a.Source, oldType, oldStatus); err != nil {
+			return err
+		}
+		return countsAdd(ctx, tx, a)
+	})
+}
+
+// SoftDeleteAsset marks an asset deleted without dropping its row or
+// history: it leaves the default listings and the count summaries, and
+// keeps its status for an eventual restore. Deleting an already
+// soft-deleted asset is a no-op.
+func (s *Store) SoftDeleteAsset(ctx context.Context, id int64) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var source, assetType string
+		var status model.AssetStatus
+		row := tx.QueryRow(ctx,
+			`UPDATE assets SET deleted_at = now(), updated_at = now()
+			 WHERE id = $1 AND deleted_at IS NULL
+			 RETURNING source, asset_type, status`, id)
+		if err := row.Scan(&source, &assetType, &status); err != nil {
+			if err == pgx.ErrNoRows {
+				var exists bool
+				if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM assets WHERE id = $1)`, id).Scan(&exists); err != nil {
+					return err
+				}
+				if exists {
+					return nil
+				}
+				return store.ErrNotFound
+			}
+			return err
+		}
+		return countsRemove(ctx, tx, source, assetType, status)
+	})
+}
+
+// RestoreAsset clears an asset's soft-delete marker and returns the
+// restored asset. Restoring an asset that isn't deleted returns it
+// unchanged.
+func (s *Store) RestoreAsset(ctx context.Context, id int64) (*model.Asset, error) {
+	var restored *model.Asset
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		a, err := scanAsset(tx.QueryRow(ctx,
+			`UPDATE assets SET deleted_at = NULL, updated_at = now()
+			 WHERE id = $1 AND deleted_at IS NOT NULL
+			 RETURNING `+assetColumns, id))
+		if err == store.ErrNotFound {
+			restored, err = scanAsset(tx.QueryRow(ctx,
+				`SELECT `+assetColumns+` FROM assets WHERE id = $1`, id))
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		restored = a
+		return countsAdd(ctx, tx, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// DeleteAsset removes an asset; its change events go with it via the
+// foreign key cascade.
+func (s *Store) DeleteAsset(ctx context.Context, id int64) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var source, assetType string
+		var status model.AssetStatus
+		row := tx.QueryRow(ctx,
+			`DELETE FROM assets WHERE id = $1 RETURNING source, asset_type, status`, id)
+		if err := row.Scan(&source, &assetType, &status); err != nil {
+			if err == pgx.ErrNoRows {
+				return store.ErrNotFound
+			}
+			return err
+		}
+		return countsRemove(ctx, tx, source, assetType, status)
+	})
+}
+
+// GetAssetByID fetches one asset by primary key.
+func (s *Store) GetAssetByID(ctx context.Context, id int64) (*model.Asset, error) {
+	return scanAsset(s.pool.QueryRow(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE id = $1`, id))
+}
+
+// GetAssetByExternalID fetches the asset a source knows under the given
+// external ID.
+func (s *Store) GetAssetByExternalID(ctx context.Context, source, externalID string) (*model.Asset, error) {
+	return scanAsset(s.pool.QueryRow(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE source = $1 AND external_id = $2`, source, externalID))
+}
+
+// GetAssetsByExternalIDs bulk-resolves a source's external IDs to its
+// assets in one round trip, keyed by external ID. IDs the source has no
+// asset for are simply absent from the map.
+func (s *Store) GetAssetsByExternalIDs(ctx context.Context, source string, externalIDs []string) (map[string]*model.Asset, error) {
+	if len(externalIDs) == 0 {
+		return map[string]*model.Asset{}, nil
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE source = $1 AND external_id = ANY($2)`,
+		source, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	assets, err := collectAssets(rows)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*model.Asset, len(assets))
+	for _, a := range assets {
+		out[a.ExternalID] = a
+	}
+	return out, nil
+}
+
+// FindAssetsByIPs returns assets sharing any of the given IPs, the bulk
+// counterpart of FindAssetsByIPOrFQDN's address leg.
+func (s *Store) FindAssetsByIPs(ctx context.Context, ips []string) ([]*model.Asset, error) {
+	if len(ips) == 0 {
+		return nil, nil
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE ips && $1 AND deleted_at IS NULL`, ips)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectAssets(rows)
+}
+
+// FindAssetsByName returns assets whose name matches case-insensitively,
+// used by the weakest reconciler matching strategy.
+func (s *Store) FindAssetsByName(ctx context.Context, name string) ([]*model.Asset, error) {
+	if name == "" {
+		return nil, nil
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE lower(name) = lower($1) AND deleted_at IS NULL`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectAssets(rows)
+}
+
+// FindAssetsBySerial returns assets carrying the given serial number
+// or hardware UUID, used by the reconciler for cross-source matching.
+func (s *Store) FindAssetsBySerial(ctx context.Context, serial, hardwareUUID string) ([]*model.Asset, error) {
+	if serial == "" && hardwareUUID == "" {
+		return nil, nil
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+assetColumns+` FROM assets
+		 WHERE ((serial_number <> '' AND serial_number = $1)
+		    OR (hardware_uuid <> '' AND hardware_uuid = $2)) AND deleted_at IS NULL`, serial, hardwareUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectAssets(rows)
+}
+
+// FindAssetsByMAC returns assets sharing any of the given MAC
+// addresses, used by the reconciler for cross-source matching.
+func (s *Store) FindAssetsByMAC(ctx context.Context, macs []string) ([]*model.Asset, error) {
+	if len(macs) == 0 {
+		return nil, nil
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE macs && $1 AND deleted_at IS NULL`, macs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectAssets(rows)
+}
+
+// FindAssetsByIPOrFQDN returns assets sharing any of the given IPs or
+// the given FQDN, used by the reconciler for cross-source matching.
+func (s *Store) FindAssetsByIPOrFQDN(ctx context.Context, ips []string, fqdn string) ([]*model.Asset, error) {
+	if len(ips) == 0 && fqdn == "" {
+		return nil, nil
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+assetColumns+` FROM assets WHERE (ips && $1 OR (fqdn <> '' AND fqdn = $2)) AND deleted_at IS NULL`,
+		ips, fqdn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectAssets(rows)
+}
+
+func collectAssets(rows pgx.Rows) ([]*model.Asset, error) {
+	var out []*model.Asset
+	for rows.Next() {
+		a, err := scanAsset(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// CountAssetsByName returns how many other assets share the given name.
+func (s *Store) CountAssetsByName(ctx context.Context, name string, excludeID int64) (int64, error) {
+	var n int64
+	err := s.pool.QueryRow(ctx,
+		`SELECT count(*) FROM assets WHERE name = $1 AND id <> $2 AND deleted_at IS NULL`, name, excludeID).Scan(&n)
+	return n, err
+}
+
+// maxPageSize caps one listing page. Cursor pagination makes larger
+// pages safe, since no offset scan grows with the page number.
+const maxPageSize = 500
+
+// assetSortColumns are the ORDER BY targets the filter may name; the
+// lookup doubles as the injection guard.
+var assetSortColumns = map[string]string{
+	"": "id", "id": "id", "name": "name", "last_seen": "last_seen", "created_at": "created_at",
+}
+
+// ListAssets returns a page of assets matching the filter plus the
+// total match count (of the whole filter, not the page).
+func (s *Store) ListAssets(ctx context.Context, f store.AssetFilter) ([]*model.Asset, int64, error) {
+	where, args := buildAssetWhere(f)
+	var total int64
+	if err := s.pool.QueryRow(ctx, `SELECT count(*) FROM assets`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sortCol, ok := assetSortColumns[f.Sort]
+	if !ok {
+		sortCol = "id"
+	}
+	dir, cmp := "ASC", ">"
+	if f.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	// The cursor condition applies to the page only, never the total.
+	if f.AfterID != 0 || f.AfterValue != nil {
+		var cond string
+		if sortCol == "id" {
+			args = append(args, f.AfterID)
+			cond = fmt.Sprintf("id %s $%d", cmp, len(args))
+		} else {
+			args = append(args, f.AfterValue, f.AfterID)
+			cond = fmt.Sprintf("(%s, id) %s ($%d, $%d)", sortCol, cmp, len(args)-1, len(args))
+		}
+		if where == "" {
+			where = " WHERE " + cond
+		} else {
+			where += " AND " + cond
+		}
+	}
+	order := fmt.Sprintf(" ORDER BY %s %s", sortCol, dir)
+	if sortCol != "id" {
+		order += fmt.Sprintf(", id %s", dir)
+	}
+
+	limit := f.Limit
+	if limit <= 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+	args = append(args, limit, f.Offset)
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+assetColumns+` FROM assets`+where+order+
+			fmt.Sprintf(` LIMIT $%d OFFSET $%d`, len(args)-1, len(args)),
+		args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	assets, err := collectAssets(rows)
+	return assets, total, err
+}
+
+func buildAssetWhere(f store.AssetFilter) (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, val any) {
+		args = append(args, val)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.Source != "" {
+		add("source = $%d", f.Source)
+	}
+	if f.AssetType != "" {
+		add("asset_type = $%d", f.AssetType)
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	if f.Site != "" {
+		add("site = $%d", f.Site)
+	}
+	if f.Owner != "" {
+		add("owner = $%d", f.Owner)
+	}
+	if f.Team != "" {
+		add("team = $%d", f.Team)
+	}
+	if f.MaxSeenCount > 0 {
+		add("seen_count <= $%d", f.MaxSeenCount)
+	}
+	if len(f.Tags) > 0 {
+		// Marshaling can't fail for a map[string]string.
+		tagJSON, _ := json.Marshal(f.Tags)
+		add("tags @> $%d::jsonb", tagJSON)
+	}
+	for k, v := range f.Custom {
+		args = append(args, k, v)
+		conds = append(conds, fmt.Sprintf("custom->>$%d = $%d", len(args)-1, len(args)))
+	}
+	if f.Query != nil {
+		cond, qargs := query.Compile(f.Query, len(args))
+		args = append(args, qargs...)
+		conds = append(conds, cond)
+	}
+	if f.Search != "" {
+		args = append(args, f.Search)
+		p := fmt.Sprintf("$%d", len(args))
+		conds = append(conds, fmt.Sprintf(
+			"(name ILIKE '%%' || %s || '%%' OR fqdn ILIKE '%%' || %s || '%%' OR array_to_string(ips, ' ') ILIKE '%%' || %s || '%%')",
+			p, p, p))
+	}
+	if !f.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// IncrementAbsentCounts bumps absent_count for every asset of the given
+// source that is not in seenIDs, i.e. assets a run failed to observe. It
+// returns the number of assets marked absent.
+func (s *Store) IncrementAbsentCounts(ctx context.Context, source string, seenIDs []int64) (int64, error) {
+	if seenIDs == nil {
+		seenIDs = []int64{}
+	}
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE assets SET absent_count = absent_count + 1 WHERE source = $1 AND NOT (id = ANY($2)) AND deleted_at IS NULL`,
+		source, seenIDs)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// TouchAssets bumps last_seen for heartbeat confirmations in one
+// statement, then refreshes the extremes of each bucket that had a hit
+// so asset_counts stays exact. GREATEST preserves the rule that
+// last_seen never moves backwards.
+func (s *Store) TouchAssets(ctx context.Context, source string, externalIDs []string, seenAt time.Time) (int64, error) {
+	if len(externalIDs) == 0 {
+		return 0, nil
+	}
+	var touched int64
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			`UPDATE assets SET last_seen = GREATEST(last_seen, $3), updated_at = now()
+			 WHERE source = $1 AND external_id = ANY($2)
+			 RETURNING asset_type, status`,
+			source, externalIDs, seenAt)
+		if err != nil {
+			return err
+		}
+		type bucket struct {
+			assetType string
+			status    model.AssetStatus
+		}
+		buckets := map[bucket]bool{}
+		for rows.Next() {
+			var b bucket
+			if err := rows.Scan(&b.assetType, &b.status); err != nil {
+				rows.Close()
+				return err
+			}
+			buckets[b] = true
+			touched++
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for b := range buckets {
+			if err := countsTouch(ctx, tx, source, b.assetType, b.status, seenAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return touched, nil
+}
+
+// CreateChangeEvent appends one event to an asset's history.
+func (s *Store) CreateChangeEvent(ctx context.Context, e *model.ChangeEvent) error {
+	before, err := json.Marshal(e.Before)
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(e.After)
+	if err != nil {
+		return err
+	}
+	row := s.pool.QueryRow(ctx,
+		`INSERT INTO change_events (asset_id, action, source, actor, before, after)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		e.AssetID, e.Action, e.Source, e.Actor, before, after)
+	return row.Scan(&e.ID, &e.CreatedAt)
+}
+
+func scanEvent(rows pgx.Rows) (*model.ChangeEvent, error) {
+	e := &model.ChangeEvent{}
+	var before, after []byte
+	if err := rows.Scan(&e.ID, &e.AssetID, &e.Action, &e.Source, &e.Actor, &before, &after, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	if len(before) > 0 {
+		json.Unmarshal(before, &e.Before)
+	}
+	if len(after) > 0 {
+		json.Unmarshal(after, &e.After)
+	}
+	return e, nil
+}
+
+const eventColumns = `id, asset_id, action, source, actor, before, after, created_at`
+
+// ListChangeEventsByAssetID returns a page of an asset's history, newest
+// first. The COUNT(*) is skipped (total -1) when q.WithCount is false,
+// and q.BeforeID selects the page by keyset instead of offset.
+func (s *Store) ListChangeEventsByAssetID(ctx context.Context, assetID int64, q store.EventQuery) ([]*model.ChangeEvent, int64, error) {
+	total := int64(-1)
+	if q.WithCount {
+		if err := s.pool.QueryRow(ctx, `SELECT count(*) FROM change_events WHERE asset_id = $1`, assetID).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+	limit := q.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	var rows pgx.Rows
+	var err error
+	if q.BeforeID > 0 {
+		rows, err = s.pool.Query(ctx,
+			`SELECT `+eventColumns+` FROM change_events WHERE asset_id = $1 AND id < $2 ORDER BY id DESC LIMIT $3`,
+			assetID, q.BeforeID, limit)
+	} else {
+		rows, err = s.pool.Query(ctx,
+			`SELECT `+eventColumns+` FROM change_events WHERE asset_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`,
+			assetID, limit, q.Offset)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []*model.ChangeEvent
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, e)
+	}
+	return out, total, rows.Err()
+}
+
+// ListUnpublishedEvents returns events not yet handed to the event
+// publisher, oldest first.
+func (s *Store) ListUnpublishedEvents(ctx context.Context, limit int) ([]*model.ChangeEvent, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+eventColumns+` FROM change_events WHERE NOT published ORDER BY id LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*model.ChangeEvent
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// UpdateAssetNotes replaces the asset's notes, returning the previous
+// value for the change event. Notes deliberately have their own write
+// path so collector-driven updates can never clobber them.
+func (s *Store) UpdateAssetNotes(ctx context.Context, id int64, notes string) (string, error) {
+	var old string
+	err := s.pool.QueryRow(ctx,
+		`UPDATE assets AS new SET notes = $2, updated_at = now()
+		 FROM assets AS old
+		 WHERE new.id = $1 AND old.id = new.id
+		 RETURNING old.notes`,
+		id, notes).Scan(&old)
+	if err == pgx.ErrNoRows {
+		return "", store.ErrNotFound
+	}
+	return old, err
+}
+
+// BulkTag applies tag changes to every asset matching the filter in a
+// single UPDATE, returning the number of affected rows. Keys in add are
+// set (overwriting existing values); keys in remove are dropped.
+// UpdateAssetOwner assigns the accountable person and team, returning
+// the previous assignment for the change log.
+func (s *Store) UpdateAssetOwner(ctx context.Context, id int64, owner, team string) (prevOwner, prevTeam string, err error) {
+	err = s.pool.QueryRow(ctx,
+		`UPDATE assets AS new SET owner = $2, team = $3, updated_at = now()
+		 FROM assets AS old
+		 WHERE new.id = $1 AND old.id = new.id
+		 RETURNING old.owner, old.team`,
+		id, owner, team).Scan(&prevOwner, &prevTeam)
+	if err == pgx.ErrNoRows {
+		return "", "", store.ErrNotFound
+	}
+	return prevOwner, prevTeam, err
+}
+
+// BulkAssignOwner assigns owner and team to every asset matching the
+// filter in a single UPDATE, returning the number of affected rows.
+func (s *Store) BulkAssignOwner(ctx context.Context, f store.AssetFilter, owner, team string) (int64, error) {
+	where, args := buildAssetWhere(f)
+	args = append(args, owner, team)
+	tag, err := s.pool.Exec(ctx,
+		fmt.Sprintf(`UPDATE assets SET owner = $%d, team = $%d, updated_at = now()`,
+			len(args)-1, len(args))+where,
+		args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (s *Store) BulkTag(ctx context.Context, f store.AssetFilter, add map[string]string, remove []string) (int64, error) {
+	addJSON, err := json.Marshal(add)
+	if err != nil {
+		return 0, err
+	}
+	if remove == nil {
+		remove = []string{}
+	}
+	where, args := buildAssetWhere(f)
+	args = append(args, addJSON, remove)
+	tag, err := s.pool.Exec(ctx,
+		fmt.Sprintf(`UPDATE assets SET tags = (tags || $%d::jsonb) - $%d::text[], updated_at = now()`,
+			len(args)-1, len(args))+where,
+		args...)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// UpdateAssetTags applies add/remove to a single asset's tags, under a
+// row lock so concurrent edits don't lose keys, and returns the
+// previous and resulting tag sets for the change log.
+func (s *Store) UpdateAssetTags(ctx context.Context, id int64, add map[string]string, remove []string) (before, after map[string]string, err error) {
+	err = s.inTx(ctx, func(tx pgx.Tx) error {
+		var raw []byte
+		if err := tx.QueryRow(ctx, `SELECT tags FROM assets WHERE id = $1 FOR UPDATE`, id).Scan(&raw); err != nil {
+			if err == pgx.ErrNoRows {
+				return store.ErrNotFound
+			}
+			return err
+		}
+		before = map[string]string{}
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &before); err != nil {
+				return fmt.Errorf("decode tags for asset %d: %w", id, err)
+			}
+		}
+		after = make(map[string]string, len(before)+len(add))
+		for k, v := range before {
+			after[k] = v
+		}
+		for k, v := range add {
+			after[k] = v
+		}
+		for _, k := range remove {
+			delete(after, k)
+		}
+		out, err := json.Marshal(after)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `UPDATE assets SET tags = $2, updated_at = now() WHERE id = $1`, id, out)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
+
+// MarkEventPublished flags an event as delivered to NATS.
+func (s *Store) MarkEventPublished(ctx context.Context, id int64) error {
+	_, err := s.pool.Exec(ctx, `UPDATE change_events SET published = true WHERE id = $1`, id)
+	return err
+}
+
+// DashboardStats computes the aggregate counts for the dashboard from
+// the asset_counts summary table, so it stays O(buckets) rather than
+// O(assets). recent sets the window of the recent-changes count.
+func (s *Store) DashboardStats(ctx context.Context, recent time.Duration) (*store.DashboardStats, error) {
+	stats := &store.DashboardStats{
+		BySource: map[string]int64{},
+		ByType:   map[string]int64{},
+		ByStatus: map[string]int64{},
+		BySite:   map[string]int64{},
+	}
+	counts, err := s.ListAssetCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stats.OpenVulnerabilities, err = s.CountOpenVulnerabilities(ctx); err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		stats.TotalAssets += c.Count
+		stats.BySource[c.Source] += c.Count
+		stats.ByType[c.AssetType] += c.Count
+		stats.ByStatus[c.Status] += c.Count
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT site, count(*) FROM assets WHERE site <> '' AND deleted_at IS NULL GROUP BY site`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var site string
+		var n int64
+		if err := rows.Scan(&site, &n); err != nil {
+			return nil, err
+		}
+		stats.BySite[site] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// The cutoff is computed in Go as explicit UTC and passed as a
+	// parameter, so the count never depends on the database server's
+	// time zone setting.
+	if recent <= 0 {
+		recent = 24 * time.Hour
+	}
+	cutoff := time.Now().UTC().Add(-recent)
+	err = s.pool.QueryRow(ctx,
+		`SELECT count(*) FROM change_events WHERE created_at > $1`, cutoff).
+		Scan(&stats.RecentChanges)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/store"
+)
+
+// CreateQuarantinedRun stores a refused collector result for review.
+func (s *Store) CreateQuarantinedRun(ctx context.Context, q *store.QuarantinedRun) error {
+	result, err := json.Marshal(q.Result)
+	if err != nil {
+		return err
+	}
+	row := s.pool.QueryRow(ctx,
+		`INSERT INTO quarantined_runs (source, reason, asset_count, result)
+		 VALUES ($1, $2, $3, $4) RETURNING id, created_at`,
+		q.Source, q.Reason, q.AssetCount, result)
+	return row.Scan(&q.ID, &q.CreatedAt)
+}
+
+// ListQuarantinedRuns returns a page of the quarantine queue, newest
+// first, plus the total. The stored results are omitted — they can be
+// large; GetQuarantinedRun fetches one in full.
+func (s *Store) ListQuarantinedRuns(ctx context.Context, limit, offset int) ([]*store.QuarantinedRun, int64, error) {
+	var total int64
+	if err := s.pool.QueryRow(ctx, `SELECT count(*) FROM quarantined_runs`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, source, reason, asset_count, created_at
+		 FROM quarantined_runs ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`,
+		limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []*store.QuarantinedRun
+	for rows.Next() {
+		q := &store.QuarantinedRun{}
+		if err := rows.Scan(&q.ID, &q.Source, &q.Reason, &q.AssetCount, &q.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, q)
+	}
+	return out, total, rows.Err()
+}
+
+// GetQuarantinedRun returns one quarantined result including its stored
+// payload.
+func (s *Store) GetQuarantinedRun(ctx context.Context, id int64) (*store.QuarantinedRun, error) {
+	q := &store.QuarantinedRun{}
+	var result []byte
+	err := s.pool.QueryRow(ctx,
+		`SELECT id, source, reason, asset_count, result, created_at
+		 FROM quarantined_runs WHERE id = $1`, id).
+		Scan(&q.ID, &q.Source, &q.Reason, &q.AssetCount, &result, &q.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(result, &q.Result); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// DeleteQuarantinedRun discards one quarantined result.
+func (s *Store) DeleteQuarantinedRun(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM quarantined_runs WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
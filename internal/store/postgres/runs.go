@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/qrunner/arch/internal/store"
+)
+
+// CreateReconcileRun appends one run record to the reconciliation
+// history.
+func (s *Store) CreateReconcileRun(ctx context.Context, run *store.ReconcileRun) error {
+	row := s.pool.QueryRow(ctx,
+		`INSERT INTO reconciliation_runs
+		 (source, started_at, finished_at, created, updated, unchanged, errors, relationships, duration_ms)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+		run.Source, run.StartedAt, run.FinishedAt, run.Created, run.Updated,
+		run.Unchanged, run.Errors, run.Relationships, run.Duration.Milliseconds())
+	return row.Scan(&run.ID)
+}
+
+// ListReconcileRuns returns a source's run history, newest first.
+func (s *Store) ListReconcileRuns(ctx context.Context, source string, limit int) ([]*store.ReconcileRun, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, source, started_at, finished_at, created, updated, unchanged, errors, relationships, duration_ms
+		 FROM reconciliation_runs WHERE source = $1
+		 ORDER BY started_at DESC, id DESC LIMIT $2`,
+		source, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*store.ReconcileRun
+	for rows.Next() {
+		run := &store.ReconcileRun{}
+		var ms int64
+		if err := rows.Scan(&run.ID, &run.Source, &run.StartedAt, &run.FinishedAt,
+			&run.Created, &run.Updated, &run.Unchanged, &run.Errors, &run.Relationships, &ms); err != nil {
+			return nil, err
+		}
+		run.Duration = time.Duration(ms) * time.Millisecond
+		out = append(out, run)
+	}
+	return out, rows.Err()
+}
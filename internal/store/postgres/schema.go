@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// migrations are applied in order at startup; the index in this slice
+// (plus one) is the schema version recorded in schema_migrations. Never
+// edit an entry that has shipped — append a new one.
+var migrations = []string{
+	`CREATE TABLE assets (
+		id          BIGSERIAL PRIMARY KEY,
+		name        TEXT NOT NULL,
+		fqdn        TEXT NOT NULL DEFAULT '',
+		ips         TEXT[] NOT NULL DEFAULT '{}',
+		asset_type  TEXT NOT NULL,
+		status      TEXT NOT NULL DEFAULT 'discovered',
+		source      TEXT NOT NULL,
+		external_id TEXT NOT NULL,
+		attributes  JSONB NOT NULL DEFAULT '{}',
+		first_seen  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_seen   TIMESTAMPTZ NOT NULL DEFAULT now(),
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		UNIQUE (source, external_id)
+	)`,
+	`CREATE INDEX assets_name_idx ON assets (name)`,
+	`CREATE INDEX assets_ips_idx ON assets USING GIN (ips)`,
+	`CREATE TABLE change_events (
+		id         BIGSERIAL PRIMARY KEY,
+		asset_id   BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+		action     TEXT NOT NULL,
+		source     TEXT NOT NULL,
+		actor      TEXT NOT NULL DEFAULT '',
+		before     JSONB,
+		after      JSONB,
+		published  BOOLEAN NOT NULL DEFAULT false,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX change_events_asset_idx ON change_events (asset_id, created_at DESC)`,
+	`CREATE INDEX change_events_unpublished_idx ON change_events (id) WHERE NOT published`,
+	`CREATE TABLE asset_counts (
+		source        TEXT NOT NULL,
+		asset_type    TEXT NOT NULL,
+		status        TEXT NOT NULL,
+		count         BIGINT NOT NULL DEFAULT 0,
+		min_last_seen TIMESTAMPTZ,
+		max_last_seen TIMESTAMPTZ,
+		PRIMARY KEY (source, asset_type, status)
+	)`,
+	`INSERT INTO asset_counts (source, asset_type, status, count, min_last_seen, max_last_seen)
+	 SELECT source, asset_type, status, count(*), min(last_seen), max(last_seen)
+	 FROM assets GROUP BY 1, 2, 3`,
+	`ALTER TABLE assets ADD COLUMN tags JSONB NOT NULL DEFAULT '{}'`,
+	`CREATE TABLE asset_aliases (
+		source      TEXT NOT NULL,
+		external_id TEXT NOT NULL,
+		asset_id    BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (source, external_id)
+	)`,
+	`CREATE INDEX asset_aliases_asset_idx ON asset_aliases (asset_id)`,
+	`CREATE TABLE reconcile_conflicts (
+		id          BIGSERIAL PRIMARY KEY,
+		source      TEXT NOT NULL,
+		external_id TEXT NOT NULL,
+		asset_id    BIGINT REFERENCES assets(id) ON DELETE SET NULL,
+		reason      TEXT NOT NULL,
+		incoming    JSONB NOT NULL,
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX reconcile_conflicts_created_idx ON reconcile_conflicts (created_at DESC)`,
+	`ALTER TABLE assets ADD COLUMN notes TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE assets ADD COLUMN display_name TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE assets ADD COLUMN seen_count BIGINT NOT NULL DEFAULT 0`,
+	`ALTER TABLE assets ADD COLUMN absent_count BIGINT NOT NULL DEFAULT 0`,
+	`ALTER TABLE assets ADD COLUMN site TEXT NOT NULL DEFAULT ''`,
+	// Attribute queries: a containment index for arbitrary lookups plus
+	// expression indexes for the declared fields filtered on most.
+	`CREATE INDEX assets_attributes_idx ON assets USING GIN (attributes jsonb_path_ops)`,
+	`CREATE INDEX assets_attr_os_idx ON assets ((attributes->>'os'))`,
+	`CREATE INDEX assets_attr_power_state_idx ON assets ((attributes->>'power_state'))`,
+	`ALTER TABLE assets ADD COLUMN macs TEXT[] NOT NULL DEFAULT '{}'`,
+	`CREATE INDEX assets_macs_idx ON assets USING GIN (macs)`,
+	`ALTER TABLE assets ADD COLUMN serial_number TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE assets ADD COLUMN hardware_uuid TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX assets_serial_idx ON assets (serial_number) WHERE serial_number <> ''`,
+	`CREATE INDEX assets_hw_uuid_idx ON assets (hardware_uuid) WHERE hardware_uuid <> ''`,
+	`CREATE TABLE asset_sources (
+		asset_id    BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+		source      TEXT NOT NULL,
+		external_id TEXT NOT NULL,
+		attributes  JSONB NOT NULL DEFAULT '{}',
+		first_seen  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_seen   TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (asset_id, source)
+	)`,
+	`CREATE INDEX asset_sources_identity_idx ON asset_sources (source, external_id)`,
+	`CREATE TABLE reconciliation_runs (
+		id            BIGSERIAL PRIMARY KEY,
+		source        TEXT NOT NULL,
+		started_at    TIMESTAMPTZ NOT NULL,
+		finished_at   TIMESTAMPTZ NOT NULL,
+		created       INT NOT NULL DEFAULT 0,
+		updated       INT NOT NULL DEFAULT 0,
+		unchanged     INT NOT NULL DEFAULT 0,
+		errors        INT NOT NULL DEFAULT 0,
+		relationships INT NOT NULL DEFAULT 0,
+		duration_ms   BIGINT NOT NULL DEFAULT 0
+	)`,
+	`CREATE INDEX reconciliation_runs_source_idx ON reconciliation_runs (source, started_at DESC)`,
+	`CREATE TABLE quarantined_runs (
+		id          BIGSERIAL PRIMARY KEY,
+		source      TEXT NOT NULL,
+		reason      TEXT NOT NULL,
+		asset_count INT NOT NULL DEFAULT 0,
+		result      JSONB NOT NULL,
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX quarantined_runs_created_idx ON quarantined_runs (created_at DESC)`,
+	`ALTER TABLE assets ADD COLUMN deleted_at TIMESTAMPTZ`,
+	`ALTER TABLE assets ADD COLUMN custom JSONB NOT NULL DEFAULT '{}'`,
+	`CREATE TABLE custom_fields (
+		id          BIGSERIAL PRIMARY KEY,
+		name        TEXT NOT NULL UNIQUE,
+		field_type  TEXT NOT NULL,
+		enum_values TEXT[] NOT NULL DEFAULT '{}',
+		description TEXT NOT NULL DEFAULT '',
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE TABLE business_services (
+		id          BIGSERIAL PRIMARY KEY,
+		name        TEXT NOT NULL UNIQUE,
+		description TEXT NOT NULL DEFAULT '',
+		owner       TEXT NOT NULL DEFAULT '',
+		criticality TEXT NOT NULL DEFAULT 'medium',
+		selector    JSONB NOT NULL DEFAULT '{}',
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE TABLE business_service_assets (
+		service_id BIGINT NOT NULL REFERENCES business_services(id) ON DELETE CASCADE,
+		asset_id   BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+		PRIMARY KEY (service_id, asset_id)
+	)`,
+	`CREATE INDEX business_service_assets_asset_idx ON business_service_assets (asset_id)`,
+	`CREATE TABLE maintenance_windows (
+		id         BIGSERIAL PRIMARY KEY,
+		name       TEXT NOT NULL,
+		starts_at  TIMESTAMPTZ NOT NULL,
+		ends_at    TIMESTAMPTZ NOT NULL,
+		asset_ids  BIGINT[] NOT NULL DEFAULT '{}',
+		selector   JSONB NOT NULL DEFAULT '{}',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX maintenance_windows_range_idx ON maintenance_windows (starts_at, ends_at)`,
+	`ALTER TABLE assets ADD COLUMN owner TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE assets ADD COLUMN team TEXT NOT NULL DEFAULT ''`,
+	`CREATE INDEX assets_team_idx ON assets (team) WHERE team <> ''`,
+	`CREATE TABLE asset_software (
+		asset_id  BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+		source    TEXT NOT NULL,
+		name      TEXT NOT NULL,
+		version   TEXT NOT NULL DEFAULT '',
+		last_seen TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (asset_id, source, name)
+	)`,
+	`CREATE INDEX asset_software_name_idx ON asset_software (name)`,
+	`CREATE TABLE asset_vulnerabilities (
+		asset_id    BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+		source      TEXT NOT NULL,
+		cve         TEXT NOT NULL,
+		severity    TEXT NOT NULL,
+		status      TEXT NOT NULL DEFAULT 'open',
+		title       TEXT NOT NULL DEFAULT '',
+		detected_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_seen   TIMESTAMPTZ NOT NULL DEFAULT now(),
+		resolved_at TIMESTAMPTZ,
+		PRIMARY KEY (asset_id, source, cve)
+	)`,
+	`CREATE INDEX asset_vulnerabilities_cve_idx ON asset_vulnerabilities (cve) WHERE status = 'open'`,
+	`CREATE FUNCTION jsonb_text_values(j JSONB) RETURNS TEXT
+		LANGUAGE SQL IMMUTABLE AS
+		$fn$ SELECT coalesce(string_agg(value, ' '), '') FROM jsonb_each_text(j) $fn$`,
+	`ALTER TABLE assets ADD COLUMN search_tsv TSVECTOR GENERATED ALWAYS AS (
+		to_tsvector('simple', name || ' ' || display_name || ' ' || fqdn || ' ' ||
+			serial_number || ' ' || hardware_uuid || ' ' || external_id || ' ' ||
+			jsonb_text_values(attributes))
+		|| array_to_tsvector(array_remove(ips, ''))
+		|| array_to_tsvector(array_remove(macs, ''))
+	) STORED`,
+	`CREATE INDEX assets_search_idx ON assets USING GIN (search_tsv)`,
+}
+
+// migrate brings the schema up to the current version.
+func (s *Store) migrate(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (version INT PRIMARY KEY, applied_at TIMESTAMPTZ NOT NULL DEFAULT now())`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	var current int
+	if err := s.pool.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	for i := current; i < len(migrations); i++ {
+		tx, err := s.pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, migrations[i]); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, i+1); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("record migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/qrunner/arch/internal/store"
+)
+
+// SearchAssets runs a ranked full-text search over the search_tsv
+// column, which covers names, FQDN, identifiers, IPs, MACs and
+// attribute values. Every input word must match, each as a prefix, so
+// typing the start of a serial number or hostname already narrows.
+func (s *Store) SearchAssets(ctx context.Context, q string, limit int) ([]store.SearchResult, error) {
+	tsq := searchTSQuery(q)
+	if tsq == "" {
+		return nil, nil
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, ts_rank(search_tsv, query) FROM assets, to_tsquery('simple', $1) AS query
+		 WHERE search_tsv @@ query AND deleted_at IS NULL
+		 ORDER BY ts_rank(search_tsv, query) DESC, id LIMIT $2`, tsq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	ranks := map[int64]float64{}
+	for rows.Next() {
+		var id int64
+		var rank float64
+		if err := rows.Scan(&id, &rank); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		ranks[id] = rank
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	assetRows, err := s.pool.Query(ctx, `SELECT `+assetColumns+` FROM assets WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer assetRows.Close()
+	assets, err := collectAssets(assetRows)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]int, len(assets))
+	for i, a := range assets {
+		byID[a.ID] = i
+	}
+	// Reassemble in rank order; the second query returns rows by id.
+	out := make([]store.SearchResult, 0, len(ids))
+	for _, id := range ids {
+		i, ok := byID[id]
+		if !ok {
+			continue // deleted between the two queries
+		}
+		out = append(out, store.SearchResult{Asset: assets[i], Rank: ranks[id]})
+	}
+	return out, nil
+}
+
+// searchTSQuery turns free-form input into a tsquery string: one
+// prefix-matched term per word, all required. Each term is quoted, so
+// input cannot alter the query structure — and the result is still
+// passed as a bind parameter, never interpolated.
+func searchTSQuery(q string) string {
+	var terms []string
+	for _, word := range strings.Fields(q) {
+		word = strings.ReplaceAll(word, `\`, "")
+		word = strings.ReplaceAll(word, `'`, `''`)
+		if word == "" || word == "''" {
+			continue
+		}
+		terms = append(terms, "'"+word+"':*")
+	}
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+const serviceColumns = `id, name, description, owner, criticality, selector, created_at, updated_at`
+
+func scanService(row pgx.Row) (*model.BusinessService, error) {
+	svc := &model.BusinessService{}
+	var selector []byte
+	err := row.Scan(&svc.ID, &svc.Name, &svc.Description, &svc.Owner, &svc.Criticality, &selector, &svc.CreatedAt, &svc.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+	if len(selector) > 0 {
+		if err := json.Unmarshal(selector, &svc.Selector); err != nil {
+			return nil, fmt.Errorf("decode selector for service %d: %w", svc.ID, err)
+		}
+	}
+	return svc, nil
+}
+
+func marshalSelector(svc *model.BusinessService) []byte {
+	if len(svc.Selector) == 0 {
+		return []byte("{}")
+	}
+	// Marshaling can't fail for a map[string]string.
+	out, _ := json.Marshal(svc.Selector)
+	return out
+}
+
+// CreateBusinessService inserts a service and fills in its generated
+// fields.
+func (s *Store) CreateBusinessService(ctx context.Context, svc *model.BusinessService) error {
+	if svc.Criticality == "" {
+		svc.Criticality = model.CriticalityMedium
+	}
+	row := s.pool.QueryRow(ctx,
+		`INSERT INTO business_services (name, description, owner, criticality, selector)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, created_at, updated_at`,
+		svc.Name, svc.Description, svc.Owner, svc.Criticality, marshalSelector(svc))
+	return row.Scan(&svc.ID, &svc.CreatedAt, &svc.UpdatedAt)
+}
+
+// UpdateBusinessService rewrites the mutable fields of a service.
+func (s *Store) UpdateBusinessService(ctx context.Context, svc *model.BusinessService) error {
+	row := s.pool.QueryRow(ctx,
+		`UPDATE business_services SET name = $2, description = $3, owner = $4, criticality = $5, selector = $6, updated_at = now()
+		 WHERE id = $1
+		 RETURNING updated_at`,
+		svc.ID, svc.Name, svc.Description, svc.Owner, svc.Criticality, marshalSelector(svc))
+	if err := row.Scan(&svc.UpdatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return store.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteBusinessService removes a service; its membership rows cascade
+// and its assets are untouched.
+func (s *Store) DeleteBusinessService(ctx context.Context, id int64) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM business_services WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// GetBusinessService returns one service without resolving its members.
+func (s *Store) GetBusinessService(ctx context.Context, id int64) (*model.BusinessService, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+serviceColumns+` FROM business_services WHERE id = $1`, id)
+	return scanService(row)
+}
+
+// ListBusinessServices returns all services, ordered by name. Fleets
+// have tens of services, not thousands, so there is no paging.
+func (s *Store) ListBusinessServices(ctx context.Context) ([]*model.BusinessService, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+serviceColumns+` FROM business_services ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*model.BusinessService
+	for rows.Next() {
+		svc, err := scanService(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, svc)
+	}
+	return out, rows.Err()
+}
+
+// UpdateServiceAssets attaches and detaches explicit members. Unknown
+// asset IDs in add fail on the foreign key; IDs in remove that are not
+// members are ignored.
+func (s *Store) UpdateServiceAssets(ctx context.Context, serviceID int64, add, remove []int64) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM business_services WHERE id = $1)`, serviceID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return store.ErrNotFound
+		}
+		if len(add) > 0 {
+			_, err := tx.Exec(ctx,
+				`INSERT INTO business_service_assets (service_id, asset_id)
+				 SELECT $1, unnest($2::bigint[])
+				 ON CONFLICT DO NOTHING`, serviceID, add)
+			if err != nil {
+				return err
+			}
+		}
+		if len(remove) > 0 {
+			_, err := tx.Exec(ctx,
+				`DELETE FROM business_service_assets WHERE service_id = $1 AND asset_id = ANY($2)`, serviceID, remove)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListServiceMembers resolves a service's membership: the explicitly
+// attached assets plus, when the selector is non-empty, every
+// non-deleted asset whose tags contain it.
+func (s *Store) ListServiceMembers(ctx context.Context, serviceID int64) ([]*model.Asset, error) {
+	svc, err := s.GetBusinessService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+assetColumns+` FROM assets
+		 WHERE id IN (SELECT asset_id FROM business_service_assets WHERE service_id = $1)
+		    OR ($2::jsonb <> '{}'::jsonb AND tags @> $2::jsonb AND deleted_at IS NULL)
+		 ORDER BY name, id`, serviceID, marshalSelector(svc))
+	if err != nil {
+		return nil, err
+	}
+	return collectAssets(rows)
+}
+
+// ListServicesForAsset returns the services an asset belongs to, by
+// explicit membership or by selector — the "what does this host serve"
+// side of impact analysis.
+func (s *Store) ListServicesForAsset(ctx context.Context, assetID int64) ([]*model.BusinessService, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+serviceColumns+` FROM business_services s
+		 WHERE EXISTS (SELECT 1 FROM business_service_assets m WHERE m.service_id = s.id AND m.asset_id = $1)
+		    OR (s.selector <> '{}'::jsonb AND EXISTS (SELECT 1 FROM assets a WHERE a.id = $1 AND a.tags @> s.selector))
+		 ORDER BY s.name`, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*model.BusinessService
+	for rows.Next() {
+		svc, err := scanService(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, svc)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// ReplaceAssetSoftware rewrites one source's software inventory for an
+// asset: delete and re-insert in a transaction, since a reporting run
+// is the full package list as of that run.
+func (s *Store) ReplaceAssetSoftware(ctx context.Context, assetID int64, source string, pkgs []model.SoftwarePackage) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM asset_software WHERE asset_id = $1 AND source = $2`, assetID, source); err != nil {
+			return err
+		}
+		if len(pkgs) == 0 {
+			return nil
+		}
+		names := make([]string, len(pkgs))
+		versions := make([]string, len(pkgs))
+		for i, p := range pkgs {
+			names[i] = p.Name
+			versions[i] = p.Version
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO asset_software (asset_id, source, name, version)
+			 SELECT $1, $2, n, v FROM unnest($3::text[], $4::text[]) AS pkg(n, v)
+			 ON CONFLICT (asset_id, source, name) DO UPDATE SET version = excluded.version, last_seen = now()`,
+			assetID, source, names, versions)
+		return err
+	})
+}
+
+// ListAssetSoftware returns an asset's packages across all reporting
+// sources, ordered by name.
+func (s *Store) ListAssetSoftware(ctx context.Context, assetID int64) ([]model.SoftwarePackage, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT name, version, source FROM asset_software WHERE asset_id = $1 ORDER BY name, source`, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.SoftwarePackage
+	for rows.Next() {
+		var p model.SoftwarePackage
+		if err := rows.Scan(&p.Name, &p.Version, &p.Source); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// FindSoftware returns every non-deleted asset carrying the named
+// package. The caller filters by version; see store.Store.
+func (s *Store) FindSoftware(ctx context.Context, name string) ([]store.SoftwareMatch, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT s.asset_id, a.name, s.name, s.version, s.source
+		 FROM asset_software s
+		 JOIN assets a ON a.id = s.asset_id AND a.deleted_at IS NULL
+		 WHERE s.name = $1
+		 ORDER BY a.name, s.asset_id`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []store.SoftwareMatch
+	for rows.Next() {
+		var m store.SoftwareMatch
+		if err := rows.Scan(&m.AssetID, &m.AssetName, &m.Package.Name, &m.Package.Version, &m.Package.Source); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
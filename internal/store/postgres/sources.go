@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/qrunner/arch/internal/model"
+)
+
+// UpsertAssetSource records one source's current view of an asset: its
+// external ID there and the raw attribute payload of the latest run.
+// The payload is replaced wholesale — it is this source's snapshot, not
+// a merge target — and last_seen never moves backwards.
+func (s *Store) UpsertAssetSource(ctx context.Context, rec *model.AssetSource) error {
+	attrs := []byte("{}")
+	if rec.Attributes != nil {
+		var err error
+		if attrs, err = json.Marshal(rec.Attributes); err != nil {
+			return err
+		}
+	}
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO asset_sources (asset_id, source, external_id, attributes, first_seen, last_seen)
+		 VALUES ($1, $2, $3, $4, $5, $5)
+		 ON CONFLICT (asset_id, source) DO UPDATE
+		 SET external_id = EXCLUDED.external_id, attributes = EXCLUDED.attributes,
+		     last_seen = GREATEST(asset_sources.last_seen, EXCLUDED.last_seen)`,
+		rec.AssetID, rec.Source, rec.ExternalID, attrs, rec.LastSeen)
+	return err
+}
+
+// ListAssetSources returns every source's record of the asset, oldest
+// sighting first — the order the default attribute merge overlays them
+// in, so the most recently seen source wins each key.
+func (s *Store) ListAssetSources(ctx context.Context, assetID int64) ([]model.AssetSource, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT asset_id, source, external_id, attributes, first_seen, last_seen
+		 FROM asset_sources WHERE asset_id = $1 ORDER BY last_seen, source`, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []model.AssetSource
+	for rows.Next() {
+		var rec model.AssetSource
+		var attrs []byte
+		if err := rows.Scan(&rec.AssetID, &rec.Source, &rec.ExternalID, &attrs, &rec.FirstSeen, &rec.LastSeen); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(attrs, &rec.Attributes); err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// slowQueryTracer is a pgx query tracer that logs any statement taking
+// longer than the configured threshold, so missing indexes show up in
+// the logs before they show up as incidents.
+type slowQueryTracer struct {
+	threshold time.Duration
+	log       *slog.Logger
+}
+
+type traceKey struct{}
+
+type traceInfo struct {
+	start time.Time
+	sql   string
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *slowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceInfo{start: time.Now(), sql: data.SQL})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *slowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	info, ok := ctx.Value(traceKey{}).(traceInfo)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(info.start)
+	if elapsed < t.threshold {
+		return
+	}
+	t.log.Warn("postgres: slow query",
+		"duration", elapsed,
+		"threshold", t.threshold,
+		"sql", truncateSQL(info.sql),
+		"error", data.Err)
+}
+
+func truncateSQL(sql string) string {
+	const max = 300
+	if len(sql) > max {
+		return sql[:max] + "..."
+	}
+	return sql
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/store"
+)
+
+// ReplaceAssetVulnerabilities applies one source's current findings for
+// an asset: reported CVEs are upserted as open, and the source's open
+// findings missing from the report are marked resolved. Nothing is
+// deleted — resolved rows are the remediation record.
+func (s *Store) ReplaceAssetVulnerabilities(ctx context.Context, assetID int64, source string, vulns []model.Vulnerability) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		cves := make([]string, len(vulns))
+		severities := make([]string, len(vulns))
+		titles := make([]string, len(vulns))
+		for i, v := range vulns {
+			cves[i] = v.CVE
+			severities[i] = string(v.Severity)
+			titles[i] = v.Title
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE asset_vulnerabilities SET status = 'resolved', resolved_at = now()
+			 WHERE asset_id = $1 AND source = $2 AND status = 'open' AND NOT (cve = ANY($3))`,
+			assetID, source, cves); err != nil {
+			return err
+		}
+		if len(vulns) == 0 {
+			return nil
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO asset_vulnerabilities (asset_id, source, cve, severity, title)
+			 SELECT $1, $2, c, s, t FROM unnest($3::text[], $4::text[], $5::text[]) AS v(c, s, t)
+			 ON CONFLICT (asset_id, source, cve) DO UPDATE
+			   SET severity = excluded.severity, title = excluded.title,
+			       status = 'open', resolved_at = NULL, last_seen = now()`,
+			assetID, source, cves, severities, titles)
+		return err
+	})
+}
+
+func collectVulns(rows pgx.Rows) ([]model.Vulnerability, error) {
+	defer rows.Close()
+	var out []model.Vulnerability
+	for rows.Next() {
+		var v model.Vulnerability
+		if err := rows.Scan(&v.CVE, &v.Severity, &v.Status, &v.Source, &v.Title, &v.DetectedAt, &v.LastSeen, &v.ResolvedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// ListAssetVulnerabilities returns an asset's findings across sources,
+// open ones first, worst severity first within each group.
+func (s *Store) ListAssetVulnerabilities(ctx context.Context, assetID int64) ([]model.Vulnerability, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT cve, severity, status, source, title, detected_at, last_seen, resolved_at
+		 FROM asset_vulnerabilities WHERE asset_id = $1
+		 ORDER BY status, array_position(ARRAY['critical','high','medium','low','info'], severity), cve`,
+		assetID)
+	if err != nil {
+		return nil, err
+	}
+	return collectVulns(rows)
+}
+
+// FindVulnerableAssets returns every non-deleted asset with an open
+// finding for the given CVE from any source.
+func (s *Store) FindVulnerableAssets(ctx context.Context, cve string) ([]store.VulnMatch, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT v.asset_id, a.name, v.cve, v.severity, v.status, v.source, v.title, v.detected_at, v.last_seen, v.resolved_at
+		 FROM asset_vulnerabilities v
+		 JOIN assets a ON a.id = v.asset_id AND a.deleted_at IS NULL
+		 WHERE v.cve = $1 AND v.status = 'open'
+		 ORDER BY a.name, v.asset_id`, cve)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []store.VulnMatch
+	for rows.Next() {
+		var m store.VulnMatch
+		if err := rows.Scan(&m.AssetID, &m.AssetName, &m.Finding.CVE, &m.Finding.Severity, &m.Finding.Status,
+			&m.Finding.Source, &m.Finding.Title, &m.Finding.DetectedAt, &m.Finding.LastSeen, &m.Finding.ResolvedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// CountOpenVulnerabilities aggregates open findings by severity across
+// non-deleted assets, for the dashboard.
+func (s *Store) CountOpenVulnerabilities(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT v.severity, count(*)
+		 FROM asset_vulnerabilities v
+		 JOIN assets a ON a.id = v.asset_id AND a.deleted_at IS NULL
+		 WHERE v.status = 'open'
+		 GROUP BY v.severity`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]int64{}
+	for rows.Next() {
+		var sev string
+		var n int64
+		if err := rows.Scan(&sev, &n); err != nil {
+			return nil, err
+		}
+		out[sev] = n
+	}
+	return out, rows.Err()
+}
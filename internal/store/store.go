@@ -0,0 +1,297 @@
+// Package store defines the persistence interface for assets and change
+// events. The canonical implementation lives in the postgres
+// subpackage.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qrunner/arch/internal/model"
+	"github.com/qrunner/arch/internal/query"
+)
+
+// ErrNotFound is returned when a lookup matches no row.
+var ErrNotFound = errors.New("not found")
+
+// MaxAttributesBytes caps the serialized size of an asset's attributes.
+// PostgreSQL would reject (or TOAST into pathology) much larger tuples
+// anyway; checking up front turns an opaque database error into an
+// actionable one naming the asset.
+const MaxAttributesBytes = 1 << 20 // 1 MiB
+
+// AttributesTooLargeError reports an attributes payload over the limit,
+// identifying the offending asset so the collector can be fixed.
+type AttributesTooLargeError struct {
+	Source     string
+	ExternalID string
+	Size       int
+	Limit      int
+}
+
+// Error implements the error interface.
+func (e *AttributesTooLargeError) Error() string {
+	return fmt.Sprintf("attributes for asset %s/%s are %d bytes, exceeding the %d byte limit",
+		e.Source, e.ExternalID, e.Size, e.Limit)
+}
+
+// AssetFilter narrows ListAssets. Zero values mean "no constraint".
+type AssetFilter struct {
+	Source    string
+	AssetType string
+	Status    string
+	Site      string
+	Owner     string
+	Team      string
+	Search    string // case-insensitive substring over name, fqdn and IPs
+	// IncludeDeleted also returns soft-deleted assets, which default
+	// listings hide.
+	IncludeDeleted bool
+	// Tags keeps only assets carrying every given tag with exactly the
+	// given value.
+	Tags map[string]string
+	// Custom keeps only assets whose named custom field equals the
+	// given value, compared as text.
+	Custom map[string]string
+	// MaxSeenCount keeps only assets observed in at most this many
+	// reconcile runs, for surfacing intermittently-seen assets.
+	MaxSeenCount int
+	// Query is a parsed expression from the asset query language,
+	// applied on top of the other conditions.
+	Query query.Expr
+	// Sort orders results by id (the default), name, last_seen or
+	// created_at; Desc flips the direction. Ties break on id.
+	Sort string
+	Desc bool
+	// AfterValue and AfterID resume a keyset page just past the given
+	// sort key and id, decoded from a cursor token. Keyset pages stay
+	// stable while assets churn, where offsets skip or repeat rows.
+	AfterValue any
+	AfterID    int64
+	Limit      int
+	Offset     int
+}
+
+// SearchResult is one full-text search hit with its relevance rank.
+type SearchResult struct {
+	Asset *model.Asset `json:"asset"`
+	Rank  float64      `json:"rank"`
+}
+
+// SoftwareMatch is one asset found to carry a queried package.
+type SoftwareMatch struct {
+	AssetID   int64                 `json:"asset_id"`
+	AssetName string                `json:"asset_name"`
+	Package   model.SoftwarePackage `json:"package"`
+}
+
+// VulnMatch is one asset carrying an open finding for a queried CVE.
+type VulnMatch struct {
+	AssetID   int64               `json:"asset_id"`
+	AssetName string              `json:"asset_name"`
+	Finding   model.Vulnerability `json:"finding"`
+}
+
+// DuplicateGroup is one set of assets from different sources sharing an
+// identity signal (an IP or an FQDN) that matching left separate —
+// merge candidates for human review.
+type DuplicateGroup struct {
+	Key    string         `json:"key"`
+	Assets []*model.Asset `json:"assets"`
+}
+
+// LifecycleTransition is one asset moved by a lifecycle sweep: enough
+// of the asset to emit a change event and update the graph without
+// re-reading the row.
+type LifecycleTransition struct {
+	ID        int64
+	Name      string
+	AssetType string
+	LastSeen  time.Time
+	From      model.AssetStatus
+}
+
+// ReconcileRun is the persisted outcome of one collector execution, so
+// run history survives restarts (the scheduler's in-memory status only
+// remembers the latest run).
+type ReconcileRun struct {
+	ID            int64         `json:"id"`
+	Source        string        `json:"source"`
+	StartedAt     time.Time     `json:"started_at"`
+	FinishedAt    time.Time     `json:"finished_at"`
+	Created       int           `json:"created"`
+	Updated       int           `json:"updated"`
+	Unchanged     int           `json:"unchanged"`
+	Errors        int           `json:"errors"`
+	Relationships int           `json:"relationships"`
+	Duration      time.Duration `json:"duration"`
+}
+
+// QuarantinedRun is a collector result the reconciler refused to apply
+// — a suspicious shrink or too much malformed data — held with its full
+// payload for review. Result is populated on single-record reads only.
+type QuarantinedRun struct {
+	ID         int64                `json:"id"`
+	Source     string               `json:"source"`
+	Reason     string               `json:"reason"`
+	AssetCount int                  `json:"asset_count"`
+	Result     *model.CollectResult `json:"result,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+}
+
+// EventQuery pages through an asset's change-event history. BeforeID,
+// when non-zero, switches to keyset pagination (events with a smaller
+// ID, newest first), which stays fast for high-churn assets where large
+// offsets degrade. WithCount controls whether the total is computed;
+// skipping it avoids a COUNT(*) over the asset's whole history.
+type EventQuery struct {
+	Limit     int
+	Offset    int
+	BeforeID  int64
+	WithCount bool
+}
+
+// AssetCount is one row of the asset_counts summary table: the number
+// of assets in a (source, asset_type, status) bucket and the bucket's
+// last_seen extremes.
+type AssetCount struct {
+	Source      string     `json:"source"`
+	AssetType   string     `json:"asset_type"`
+	Status      string     `json:"status"`
+	Count       int64      `json:"count"`
+	MinLastSeen *time.Time `json:"min_last_seen,omitempty"`
+	MaxLastSeen *time.Time `json:"max_last_seen,omitempty"`
+}
+
+// DashboardStats is the aggregate snapshot behind the dashboard
+// endpoint.
+type DashboardStats struct {
+	TotalAssets   int64            `json:"total_assets"`
+	BySource      map[string]int64 `json:"by_source"`
+	ByType        map[string]int64 `json:"by_type"`
+	ByStatus      map[string]int64 `json:"by_status"`
+	BySite        map[string]int64 `json:"by_site"`
+	RecentChanges int64            `json:"recent_changes"`
+	// OpenVulnerabilities counts open scanner findings by severity.
+	OpenVulnerabilities map[string]int64 `json:"open_vulnerabilities"`
+}
+
+// Store is the persistence contract used by the reconciler and the API.
+type Store interface {
+	CreateAsset(ctx context.Context, a *model.Asset) error
+	UpdateAsset(ctx context.Context, a *model.Asset) error
+	// DeleteAsset drops the row for good, change history included;
+	// SoftDeleteAsset and RestoreAsset are the reversible pair.
+	DeleteAsset(ctx context.Context, id int64) error
+	SoftDeleteAsset(ctx context.Context, id int64) error
+	RestoreAsset(ctx context.Context, id int64) (*model.Asset, error)
+	GetAssetByID(ctx context.Context, id int64) (*model.Asset, error)
+	GetAssetByExternalID(ctx context.Context, source, externalID string) (*model.Asset, error)
+	GetAssetsByExternalIDs(ctx context.Context, source string, externalIDs []string) (map[string]*model.Asset, error)
+	GetAssetIDsByAliases(ctx context.Context, source string, externalIDs []string) (map[string]int64, error)
+	FindAssetsByIPs(ctx context.Context, ips []string) ([]*model.Asset, error)
+	FindAssetsByIPOrFQDN(ctx context.Context, ips []string, fqdn string) ([]*model.Asset, error)
+	FindAssetsByMAC(ctx context.Context, macs []string) ([]*model.Asset, error)
+	FindAssetsBySerial(ctx context.Context, serial, hardwareUUID string) ([]*model.Asset, error)
+	FindAssetsByName(ctx context.Context, name string) ([]*model.Asset, error)
+	ListDuplicateAssets(ctx context.Context, limit int) ([]DuplicateGroup, error)
+	// MergeAssets folds the asset srcID into dstID: aliases and change
+	// history are re-pointed, the src identity becomes an alias of dst,
+	// and the src row is removed — all in one transaction. Field-level
+	// merging onto dst is the caller's job.
+	MergeAssets(ctx context.Context, dstID, srcID int64) error
+	UpsertAssetSource(ctx context.Context, rec *model.AssetSource) error
+	ListAssetSources(ctx context.Context, assetID int64) ([]model.AssetSource, error)
+	CountAssetsByName(ctx context.Context, name string, excludeID int64) (int64, error)
+	ListAssets(ctx context.Context, f AssetFilter) ([]*model.Asset, int64, error)
+	// SearchAssets runs a ranked full-text search over names,
+	// identifiers, addresses and attribute values.
+	SearchAssets(ctx context.Context, query string, limit int) ([]SearchResult, error)
+	BulkTag(ctx context.Context, f AssetFilter, add map[string]string, remove []string) (int64, error)
+	UpdateAssetTags(ctx context.Context, id int64, add map[string]string, remove []string) (before, after map[string]string, err error)
+	IncrementAbsentCounts(ctx context.Context, source string, seenIDs []int64) (int64, error)
+	// TouchAssets bumps last_seen to seenAt (never backwards) for the
+	// source's assets matching the given external IDs, in a single
+	// statement. It exists for heartbeat-style sources that only confirm
+	// liveness and carry no attribute changes worth a full reconcile.
+	TouchAssets(ctx context.Context, source string, externalIDs []string, seenAt time.Time) (int64, error)
+	TransitionAssets(ctx context.Context, source string, cutoff time.Time, from []model.AssetStatus, to model.AssetStatus) ([]LifecycleTransition, error)
+	UpdateAssetNotes(ctx context.Context, id int64, notes string) (previous string, err error)
+	UpdateAssetOwner(ctx context.Context, id int64, owner, team string) (prevOwner, prevTeam string, err error)
+	BulkAssignOwner(ctx context.Context, f AssetFilter, owner, team string) (int64, error)
+
+	CreateAlias(ctx context.Context, source, externalID string, assetID int64) error
+	GetAssetIDByAlias(ctx context.Context, source, externalID string) (int64, error)
+	ListAliasesByAssetID(ctx context.Context, assetID int64) ([]model.Alias, error)
+
+	CreateReconcileConflict(ctx context.Context, c *model.ReconcileConflict) error
+	ListReconcileConflicts(ctx context.Context, limit, offset int) ([]*model.ReconcileConflict, int64, error)
+
+	CreateReconcileRun(ctx context.Context, run *ReconcileRun) error
+	ListReconcileRuns(ctx context.Context, source string, limit int) ([]*ReconcileRun, error)
+
+	CreateCustomField(ctx context.Context, f *model.CustomField) error
+	ListCustomFields(ctx context.Context) ([]*model.CustomField, error)
+	// DeleteCustomField removes a definition; values already stored on
+	// assets under its name are left in place but no longer validated
+	// or filterable.
+	DeleteCustomField(ctx context.Context, id int64) error
+	UpdateAssetCustom(ctx context.Context, id int64, set map[string]any, remove []string) (before, after map[string]any, err error)
+
+	// ReplaceAssetSoftware rewrites one source's software inventory for
+	// an asset; a collector that reports software at all reports all of
+	// it each run.
+	ReplaceAssetSoftware(ctx context.Context, assetID int64, source string, pkgs []model.SoftwarePackage) error
+	ListAssetSoftware(ctx context.Context, assetID int64) ([]model.SoftwarePackage, error)
+	// FindSoftware returns every non-deleted asset carrying the named
+	// package; version filtering happens in the caller, since version
+	// ordering is not expressible in SQL.
+	FindSoftware(ctx context.Context, name string) ([]SoftwareMatch, error)
+
+	// ReplaceAssetVulnerabilities applies one source's current findings
+	// for an asset: reported CVEs are upserted as open, open ones
+	// missing from the report are marked resolved.
+	ReplaceAssetVulnerabilities(ctx context.Context, assetID int64, source string, vulns []model.Vulnerability) error
+	ListAssetVulnerabilities(ctx context.Context, assetID int64) ([]model.Vulnerability, error)
+	FindVulnerableAssets(ctx context.Context, cve string) ([]VulnMatch, error)
+	CountOpenVulnerabilities(ctx context.Context) (map[string]int64, error)
+
+	CreateBusinessService(ctx context.Context, svc *model.BusinessService) error
+	UpdateBusinessService(ctx context.Context, svc *model.BusinessService) error
+	DeleteBusinessService(ctx context.Context, id int64) error
+	GetBusinessService(ctx context.Context, id int64) (*model.BusinessService, error)
+	ListBusinessServices(ctx context.Context) ([]*model.BusinessService, error)
+	UpdateServiceAssets(ctx context.Context, serviceID int64, add, remove []int64) error
+	ListServiceMembers(ctx context.Context, serviceID int64) ([]*model.Asset, error)
+	ListServicesForAsset(ctx context.Context, assetID int64) ([]*model.BusinessService, error)
+
+	CreateMaintenanceWindow(ctx context.Context, w *model.MaintenanceWindow) error
+	UpdateMaintenanceWindow(ctx context.Context, w *model.MaintenanceWindow) error
+	DeleteMaintenanceWindow(ctx context.Context, id int64) error
+	GetMaintenanceWindow(ctx context.Context, id int64) (*model.MaintenanceWindow, error)
+	ListMaintenanceWindows(ctx context.Context) ([]*model.MaintenanceWindow, error)
+	// AssetInMaintenance reports whether an active maintenance window
+	// covers the asset right now, for suppressing alerts about it.
+	AssetInMaintenance(ctx context.Context, assetID int64) (bool, error)
+
+	CreateQuarantinedRun(ctx context.Context, q *QuarantinedRun) error
+	ListQuarantinedRuns(ctx context.Context, limit, offset int) ([]*QuarantinedRun, int64, error)
+	GetQuarantinedRun(ctx context.Context, id int64) (*QuarantinedRun, error)
+	DeleteQuarantinedRun(ctx context.Context, id int64) error
+
+	CreateChangeEvent(ctx context.Context, e *model.ChangeEvent) error
+	// ListChangeEventsByAssetID returns a page of an asset's history,
+	// newest first. The total is -1 when q.WithCount is false.
+	ListChangeEventsByAssetID(ctx context.Context, assetID int64, q EventQuery) ([]*model.ChangeEvent, int64, error)
+	ListUnpublishedEvents(ctx context.Context, limit int) ([]*model.ChangeEvent, error)
+	MarkEventPublished(ctx context.Context, id int64) error
+
+	// DashboardStats aggregates the dashboard snapshot; recent is the
+	// window for the recent-changes count (<= 0 means 24h).
+	DashboardStats(ctx context.Context, recent time.Duration) (*DashboardStats, error)
+	ListAssetCounts(ctx context.Context) ([]AssetCount, error)
+
+	Close()
+}